@@ -0,0 +1,11 @@
+// Package fonts embeds the bundled TrueType fonts, so the server does not depend on its working directory.
+//
+// The fonts are licensed under the SIL Open Font License, see OFL.txt.
+package fonts
+
+import "embed"
+
+// Files contains the bundled font files, named like "OpenSans-Bold.ttf".
+//
+//go:embed *.ttf
+var Files embed.FS
@@ -0,0 +1,363 @@
+package generator
+
+import (
+	"fmt"
+	errorsWithStack "github.com/go-errors/errors"
+	"math"
+)
+
+// kinds of barcodes, see DrawBarcode
+const (
+	BarcodeCode128    = "code128"
+	BarcodeEAN13      = "ean13"
+	BarcodeDataMatrix = "datamatrix"
+)
+
+// barcodeTextScale is the height of the human-readable text relative to its font size.
+const barcodeTextScale = 1.2
+
+// barcode is an encoded barcode with its dark modules row by row, a linear barcode has one row.
+// The quiet zones are the light modules around the code, a scanner requires them to find the code.
+// The guard modules of an EAN-13 code are extended into the human-readable text.
+type barcode struct {
+	modules    [][]bool
+	quietLeft  int
+	quietRight int
+	quietY     int
+	guards     []bool
+}
+
+// columns returns the width of the barcode in modules including the quiet zones.
+func (code barcode) columns() int {
+	return code.quietLeft + len(code.modules[0]) + code.quietRight
+}
+
+// rows returns the height of the barcode in modules including the quiet zones.
+func (code barcode) rows() int {
+	return len(code.modules) + 2*code.quietY
+}
+
+// encodeBarcode encodes content as a barcode of kind, see DrawBarcode.
+func encodeBarcode(kind string, content string) (code barcode, err error) {
+	switch kind {
+	case BarcodeCode128:
+		modules, err := encodeCode128(content)
+		return barcode{modules: [][]bool{modules}, quietLeft: 10, quietRight: 10}, err
+	case BarcodeEAN13:
+		modules, err := encodeEAN13(content)
+		return barcode{modules: [][]bool{modules}, quietLeft: 11, quietRight: 7, guards: ean13Guards()}, err
+	case BarcodeDataMatrix:
+		modules, err := encodeDataMatrix(content)
+		return barcode{modules: modules, quietLeft: 1, quietRight: 1, quietY: 1}, err
+	default:
+		return barcode{}, errorsWithStack.New(fmt.Sprintf("the barcode kind \"%s\" must be code128, ean13 or datamatrix", kind))
+	}
+}
+
+// ValidateBarcode returns an error, if content can't be encoded as a barcode of kind, see DrawBarcode.
+func ValidateBarcode(kind string, content string) error {
+	_, err := encodeBarcode(kind, content)
+	return err
+}
+
+// code128Patterns are the widths of the bars and spaces of the Code 128 symbols 0 to 105, the stop symbol is 106.
+var code128Patterns = [...]string{
+	"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312", "132212", "221213",
+	"221312", "231212", "112232", "122132", "122231", "113222", "123122", "123221", "223211", "221132",
+	"221231", "213212", "223112", "312131", "311222", "321122", "321221", "312212", "322112", "322211",
+	"212123", "212321", "232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+	"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121", "313121", "211331",
+	"231131", "213113", "213311", "213131", "311123", "311321", "331121", "312113", "312311", "332111",
+	"314111", "221411", "431111", "111224", "111422", "121124", "121421", "141122", "141221", "112214",
+	"112412", "122114", "122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+	"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112", "421211", "212141",
+	"214121", "412121", "111143", "111341", "131141", "114113", "114311", "411113", "411311", "113141",
+	"114131", "311141", "411131", "211412", "211214", "211232", "2331112",
+}
+
+// Code 128 symbols switching the code set
+const (
+	code128CodeC  = 99
+	code128CodeB  = 100
+	code128StartB = 104
+	code128StartC = 105
+	code128Stop   = 106
+)
+
+// encodeCode128 encodes the printable ASCII characters of content in the code set B.
+// Runs of digits are encoded in the code set C with two digits per symbol, if this shortens the barcode.
+func encodeCode128(content string) (modules []bool, err error) {
+	if content == "" {
+		return nil, errorsWithStack.New(fmt.Sprintf("the content of a Code 128 barcode must not be empty"))
+	}
+	for _, r := range content {
+		if r < ' ' || r > '~' {
+			return nil, errorsWithStack.New(fmt.Sprintf("the character \"%c\" can't be encoded in a Code 128 barcode", r))
+		}
+	}
+
+	digitRun := func(start int) (length int) {
+		for start+length < len(content) && isDigit(rune(content[start+length])) {
+			length++
+		}
+		return length
+	}
+
+	codeSetC := digitRun(0) >= 4 || digitRun(0) == len(content) && len(content) == 2
+	symbols := []int{code128StartB}
+	if codeSetC {
+		symbols[0] = code128StartC
+	}
+
+	for i := 0; i < len(content); {
+		run := digitRun(i)
+		switch {
+		case codeSetC && run >= 2:
+			symbols = append(symbols, int(content[i]-'0')*10+int(content[i+1]-'0'))
+			i += 2
+		case codeSetC:
+			symbols = append(symbols, code128CodeB)
+			codeSetC = false
+		case run >= 6 || run >= 4 && i+run == len(content):
+			// an odd digit is encoded in the code set B first
+			if run%2 == 1 {
+				symbols = append(symbols, int(content[i]-' '))
+				i++
+			}
+			symbols = append(symbols, code128CodeC)
+			codeSetC = true
+		default:
+			symbols = append(symbols, int(content[i]-' '))
+			i++
+		}
+	}
+
+	checksum := symbols[0]
+	for i, symbol := range symbols[1:] {
+		checksum += (i + 1) * symbol
+	}
+	symbols = append(symbols, checksum%103, code128Stop)
+
+	for _, symbol := range symbols {
+		for i, width := range code128Patterns[symbol] {
+			for w := 0; w < int(width-'0'); w++ {
+				modules = append(modules, i%2 == 0)
+			}
+		}
+	}
+	return modules, nil
+}
+
+// ean13Patterns are the modules of the digits in the left half with odd parity (L code).
+// The G code of the left half is the reversed R code, the R code of the right half is the inverted L code.
+var ean13Patterns = [10]string{"0001101", "0011001", "0010011", "0111101", "0100011", "0110001", "0101111", "0111011", "0110111", "0001011"}
+
+// ean13Parities encode the first digit by the L and G codes of the left half.
+var ean13Parities = [10]string{"LLLLLL", "LLGLGG", "LLGGLG", "LLGGGL", "LGLLGG", "LGGLLG", "LGGGLL", "LGLGLG", "LGLGGL", "LGGLGL"}
+
+// encodeEAN13 encodes 12 digits with their check digit or 13 digits with a valid check digit.
+func encodeEAN13(content string) (modules []bool, err error) {
+	digits, err := ean13Digits(content)
+	if err != nil {
+		return nil, err
+	}
+
+	appendPattern := func(pattern string, invert bool, reverse bool) {
+		for i := range pattern {
+			if reverse {
+				i = len(pattern) - 1 - i
+			}
+			modules = append(modules, (pattern[i] == '1') != invert)
+		}
+	}
+
+	appendPattern("101", false, false)
+	for i, digit := range digits[1:7] {
+		gCode := ean13Parities[digits[0]][i] == 'G'
+		appendPattern(ean13Patterns[digit], gCode, gCode)
+	}
+	appendPattern("01010", false, false)
+	for _, digit := range digits[7:] {
+		appendPattern(ean13Patterns[digit], true, false)
+	}
+	appendPattern("101", false, false)
+
+	return modules, nil
+}
+
+// ean13Digits returns the 13 digits of content, the check digit is appended to 12 digits.
+func ean13Digits(content string) (digits []int, err error) {
+	if len(content) != 12 && len(content) != 13 {
+		return nil, errorsWithStack.New(fmt.Sprintf("an EAN-13 barcode requires 12 or 13 digits, got \"%s\"", content))
+	}
+
+	sum := 0
+	for i, r := range content {
+		if !isDigit(r) {
+			return nil, errorsWithStack.New(fmt.Sprintf("an EAN-13 barcode requires 12 or 13 digits, got \"%s\"", content))
+		}
+		digits = append(digits, int(r-'0'))
+		if i < 12 {
+			sum += digits[i] * (1 + 2*(i%2))
+		}
+	}
+
+	checkDigit := (10 - sum%10) % 10
+	if len(digits) == 13 && digits[12] != checkDigit {
+		return nil, errorsWithStack.New(fmt.Sprintf("the check digit of the EAN-13 barcode \"%s\" must be %d", content, checkDigit))
+	}
+	return append(digits[:12], checkDigit), nil
+}
+
+// ean13Guards returns the guard modules at the start, in the middle and at the end of an EAN-13 barcode.
+func ean13Guards() []bool {
+	guards := make([]bool, 95)
+	for _, i := range []int{0, 1, 2, 45, 46, 47, 48, 49, 92, 93, 94} {
+		guards[i] = true
+	}
+	return guards
+}
+
+// barcodeText is a human-readable text below a barcode, from the module start to the module stop.
+type barcodeText struct {
+	text  string
+	start int
+	stop  int
+}
+
+// texts returns the human-readable texts of content below the barcode.
+// The digits of an EAN-13 barcode are grouped below the halves, the first digit left of the barcode.
+func (code barcode) texts(content string) []barcodeText {
+	if code.guards == nil {
+		return []barcodeText{{text: content, start: 0, stop: code.columns()}}
+	}
+
+	digits, _ := ean13Digits(content)
+	text := ""
+	for _, digit := range digits {
+		text += fmt.Sprint(digit)
+	}
+	return []barcodeText{
+		{text: text[:1], start: 0, stop: code.quietLeft - 1},
+		{text: text[1:7], start: code.quietLeft + 3, stop: code.quietLeft + 45},
+		{text: text[7:], start: code.quietLeft + 50, stop: code.quietLeft + 92},
+	}
+}
+
+// DrawBarcode draws content as a barcode into the rectangle x, y, w, h, e.g. the delivery note number.
+// The barcode is drawn with black rectangles, the rectangle includes the quiet zones around the barcode.
+//
+// kind specifies the barcode:
+//
+//	"code128" for a linear barcode of printable ASCII characters, e.g. an article number,
+//	"ean13" for a linear barcode of 12 digits with a calculated or 13 digits with a valid check digit, or
+//	"datamatrix" for a square 2D code of up to 1558 ASCII characters.
+//
+// A linear barcode fills the rectangle, a 2D code is the largest square centered at the top of the rectangle.
+//
+// If humanReadable is true, content is printed in the current font below the barcode.
+// The font size is reduced, if the text is wider than the barcode.
+func (core *PDFGenerator) DrawBarcode(kind string, content string, x float64, y float64, w float64, h float64, humanReadable bool) {
+	if core.strictErrorHandling == true && core.pdf.Err() {
+		return
+	}
+
+	// --> validate inputs
+	code, err := encodeBarcode(kind, content)
+	if err != nil {
+		core.pdf.SetError(err)
+		return
+	}
+
+	if w <= 0 || h <= 0 {
+		core.pdf.SetError(errorsWithStack.New(fmt.Sprintf("The barcode size %fx%f must be greater than 0.", w, h)))
+		return
+	}
+
+	if !core.pointsOnPage([]Point{{X: x, Y: y}, {X: x + w, Y: y + h}}) {
+		return
+	}
+	// <--
+
+	fontSize := core.GetFontSize()
+	defer core.SetFontSize(fontSize)
+
+	var texts []barcodeText
+	textHeight := 0.
+	if humanReadable {
+		texts = code.texts(content)
+		moduleWidth := w / float64(code.columns())
+		for _, text := range texts {
+			textWidth := core.ComputeStringLength(text.text)
+			if maxWidth := float64(text.stop-text.start) * moduleWidth; textWidth > maxWidth {
+				core.data.FontSize *= maxWidth / textWidth
+			}
+		}
+		core.pdf.SetFontSize(core.data.FontSize)
+		_, fontSizeUnit := core.pdf.GetFontSize()
+		textHeight = barcodeTextScale * fontSizeUnit
+	}
+
+	moduleWidth := w / float64(code.columns())
+	moduleHeight := (h - textHeight) / float64(code.rows())
+	if len(code.modules) > 1 {
+		// the modules of a 2D code are square
+		moduleWidth = math.Min(moduleWidth, moduleHeight)
+		moduleHeight = moduleWidth
+		x += (w - moduleWidth*float64(code.columns())) / 2
+		w = moduleWidth * float64(code.columns())
+	}
+	if moduleHeight <= 0 {
+		core.pdf.SetError(errorsWithStack.New(fmt.Sprintf("The barcode height %f is too small for the human-readable text.", h)))
+		return
+	}
+
+	style := ShapeStyle{Fill: true}
+	restore := core.setShapeStyle(style)
+	for row, modules := range code.modules {
+		rowY := y + float64(row+code.quietY)*moduleHeight
+		for start := 0; start < len(modules); start++ {
+			if !modules[start] {
+				continue
+			}
+
+			// draw the adjacent dark modules as one bar, the guard modules are one module wide
+			stop := start + 1
+			for stop < len(modules) && modules[stop] && (code.guards == nil || code.guards[stop] == code.guards[start]) {
+				stop++
+			}
+
+			barHeight := moduleHeight
+			if code.guards != nil && code.guards[start] && humanReadable {
+				barHeight += textHeight / 2
+			}
+			barX := x + float64(code.quietLeft+start)*moduleWidth
+			barWidth := float64(stop-start) * moduleWidth
+			core.pdf.Rect(barX, rowY, barWidth, barHeight, "F")
+			core.recordShape(rectOutline(barX, rowY, barWidth, barHeight, 0), true, style, 0)
+			start = stop
+		}
+	}
+	restore()
+
+	core.printBarcodeTexts(texts, x, y+float64(code.rows())*moduleHeight, moduleWidth, textHeight)
+}
+
+// printBarcodeTexts prints the human-readable texts of a barcode in cells of textHeight starting at y.
+func (core *PDFGenerator) printBarcodeTexts(texts []barcodeText, x float64, y float64, moduleWidth float64, textHeight float64) {
+	oldX, oldY := core.pdf.GetXY()
+	autoPageBreak, marginBottom := core.pdf.GetAutoPageBreak()
+	core.pdf.SetAutoPageBreak(false, marginBottom)
+
+	for _, text := range texts {
+		alignStr := "C"
+		if len(texts) > 1 && text.start == 0 {
+			alignStr = "R"
+		}
+		core.pdf.SetXY(x+float64(text.start)*moduleWidth, y)
+		core.PrintPdfTextFormatted(text.text, "", alignStr, "", false, Color{}, textHeight, float64(text.stop-text.start)*moduleWidth)
+	}
+
+	core.pdf.SetAutoPageBreak(autoPageBreak, marginBottom)
+	core.pdf.SetXY(oldX, oldY)
+}
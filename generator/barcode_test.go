@@ -0,0 +1,322 @@
+package generator
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// code128Symbols returns the symbols of the Code 128 modules, each symbol has 11 modules, the stop symbol 13.
+func code128Symbols(t *testing.T, modules []bool) (symbols []int) {
+	for start, stop := 0, 0; start < len(modules); start = stop {
+		stop = start + 11
+		if stop+2 == len(modules) {
+			stop += 2
+		}
+
+		pattern := ""
+		for i := start; i < stop; {
+			width := 1
+			for i+width < stop && modules[i+width] == modules[i] {
+				width++
+			}
+			pattern += string(rune('0' + width))
+			i += width
+		}
+
+		symbol := -1
+		for i, p := range code128Patterns {
+			if p == pattern {
+				symbol = i
+			}
+		}
+		if symbol < 0 {
+			t.Fatalf("the modules at %d are no Code 128 symbol: %s", start, pattern)
+		}
+		symbols = append(symbols, symbol)
+	}
+	return symbols
+}
+
+func Test_encodeCode128(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		wantSymbols []int
+		wantErr     bool
+	}{
+		{
+			name:        "code set B",
+			content:     "PJJ123C",
+			wantSymbols: []int{104, 48, 42, 42, 17, 18, 19, 35, 55, 106},
+			wantErr:     false,
+		},
+		{
+			name:        "code set C",
+			content:     "12345678",
+			wantSymbols: []int{105, 12, 34, 56, 78, 47, 106},
+			wantErr:     false,
+		},
+		{
+			name:        "odd digits at the end in code set C",
+			content:     "LS-2024-00042",
+			wantSymbols: []int{104, 44, 51, 13, 18, 16, 18, 20, 13, 16, 99, 0, 42, 62, 106},
+			wantErr:     false,
+		},
+		{
+			name:        "digits at the start in code set C",
+			content:     "12345A",
+			wantSymbols: []int{105, 12, 34, 100, 21, 33, 13, 106},
+			wantErr:     false,
+		},
+		{
+			name:    "empty content",
+			content: "",
+			wantErr: true,
+		},
+		{
+			name:    "no ASCII character",
+			content: "Lieferschein Nr. 1 – Köln",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modules, err := encodeCode128(tt.content)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("encodeCode128() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got := code128Symbols(t, modules); !reflect.DeepEqual(got, tt.wantSymbols) {
+				t.Errorf("encodeCode128() symbols = %v, want %v", got, tt.wantSymbols)
+			}
+		})
+	}
+}
+
+func Test_encodeEAN13(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		wantModules string
+		wantErr     bool
+	}{
+		{
+			name:    "calculated check digit",
+			content: "400638133393",
+			wantModules: "101" + "0001101" + "0100111" + "0101111" + "0111101" + "0001001" + "0110011" + "01010" +
+				"1000010" + "1000010" + "1000010" + "1110100" + "1000010" + "1100110" + "101",
+			wantErr: false,
+		},
+		{
+			name:    "valid check digit",
+			content: "4006381333931",
+			wantModules: "101" + "0001101" + "0100111" + "0101111" + "0111101" + "0001001" + "0110011" + "01010" +
+				"1000010" + "1000010" + "1000010" + "1110100" + "1000010" + "1100110" + "101",
+			wantErr: false,
+		},
+		{
+			name:    "invalid check digit",
+			content: "4006381333932",
+			wantErr: true,
+		},
+		{
+			name:    "too few digits",
+			content: "40063813339",
+			wantErr: true,
+		},
+		{
+			name:    "no digits",
+			content: "40063813339A",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modules, err := encodeEAN13(tt.content)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("encodeEAN13() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			got := ""
+			for _, module := range modules {
+				if module {
+					got += "1"
+				} else {
+					got += "0"
+				}
+			}
+			if got != tt.wantModules {
+				t.Errorf("encodeEAN13() = %s, want %s", got, tt.wantModules)
+			}
+		})
+	}
+}
+
+func Test_encodeDataMatrix(t *testing.T) {
+	// the example of ISO/IEC 16022
+	data, err := dataMatrixASCII("123456")
+	if err != nil {
+		t.Fatalf("dataMatrixASCII() error = %v", err)
+	}
+	if want := []byte{142, 164, 186}; !bytes.Equal(data, want) {
+		t.Errorf("dataMatrixASCII() = %v, want %v", data, want)
+	}
+	if got, want := dataMatrixSymbols[0].errorCorrection(data), []byte{114, 25, 5, 88, 102}; !bytes.Equal(got, want) {
+		t.Errorf("errorCorrection() = %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		name     string
+		content  string
+		wantSize int
+		wantErr  bool
+	}{
+		{
+			name:     "smallest symbol",
+			content:  "123456",
+			wantSize: 10,
+			wantErr:  false,
+		},
+		{
+			name:     "extended characters",
+			content:  "Lieferschein Köln",
+			wantSize: 18,
+			wantErr:  false,
+		},
+		{
+			name:     "interleaved blocks",
+			content:  string(bytes.Repeat([]byte("A"), 1000)),
+			wantSize: 120,
+			wantErr:  false,
+		},
+		{
+			name:    "too long",
+			content: string(bytes.Repeat([]byte("A"), 1559)),
+			wantErr: true,
+		},
+		{
+			name:    "no ISO 8859-1 character",
+			content: "Lieferschein 1 – Köln",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modules, err := encodeDataMatrix(tt.content)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("encodeDataMatrix() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if len(modules) != tt.wantSize || len(modules[0]) != tt.wantSize {
+				t.Fatalf("encodeDataMatrix() has %dx%d modules, want %dx%d", len(modules), len(modules[0]), tt.wantSize, tt.wantSize)
+			}
+
+			// the finder pattern is solid on the left and the bottom, alternating on the top and the right
+			last := tt.wantSize - 1
+			for i := 0; i < tt.wantSize; i++ {
+				if !modules[i][0] || !modules[last][i] || modules[0][i] != (i%2 == 0) || modules[i][last] != (i%2 == 1) {
+					t.Fatalf("encodeDataMatrix() has no finder pattern at %d", i)
+				}
+			}
+		})
+	}
+}
+
+func TestPDFGenerator_DrawBarcode(t *testing.T) {
+	tests := []struct {
+		name          string
+		kind          string
+		content       string
+		h             float64
+		humanReadable bool
+		wantErr       bool
+	}{
+		{
+			name:          "Code 128 with text",
+			kind:          BarcodeCode128,
+			content:       "LS-2024-00042",
+			h:             15,
+			humanReadable: true,
+			wantErr:       false,
+		},
+		{
+			name:          "EAN-13 with text",
+			kind:          BarcodeEAN13,
+			content:       "400638133393",
+			h:             15,
+			humanReadable: true,
+			wantErr:       false,
+		},
+		{
+			name:          "DataMatrix",
+			kind:          BarcodeDataMatrix,
+			content:       "https://example.com/delivery/LS-2024-00042",
+			h:             20,
+			humanReadable: false,
+			wantErr:       false,
+		},
+		{
+			name:    "unknown kind",
+			kind:    "qr",
+			content: "LS-2024-00042",
+			h:       15,
+			wantErr: true,
+		},
+		{
+			name:    "invalid content",
+			kind:    BarcodeEAN13,
+			content: "LS-2024-00042",
+			h:       15,
+			wantErr: true,
+		},
+		{
+			name:          "no space for the bars",
+			kind:          BarcodeCode128,
+			content:       "LS-2024-00042",
+			h:             .3,
+			humanReadable: true,
+			wantErr:       true,
+		},
+		{
+			name:    "outside the page",
+			kind:    BarcodeCode128,
+			content: "LS-2024-00042",
+			h:       500,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, err := NewPDFGenerator(_defaultMetaData, false, &_logger, func() {}, func(isLastPage bool) {})
+			if err != nil {
+				t.Fatalf("init core error\n%s", err.Error())
+			}
+			core.NewPage()
+			fontSize := core.GetFontSize()
+
+			core.DrawBarcode(tt.kind, tt.content, 20, 20, 60, tt.h, tt.humanReadable)
+			if gotErr := core.pdf.Err(); gotErr != tt.wantErr {
+				t.Fatalf("DrawBarcode() set a error = %v, want %v", core.pdf.Error(), tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if core.GetFontSize() != fontSize {
+				t.Errorf("DrawBarcode() changed the font size to %v, want %v", core.GetFontSize(), fontSize)
+			}
+
+			var buffer bytes.Buffer
+			if err = core.Output(&buffer); err != nil {
+				t.Errorf("Output() error = %v", err)
+			}
+		})
+	}
+}
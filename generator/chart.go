@@ -0,0 +1,437 @@
+package generator
+
+import (
+	"fmt"
+	errorsWithStack "github.com/go-errors/errors"
+	"math"
+)
+
+// kinds of charts, see ChartBox
+const (
+	ChartBar        = "bar"
+	ChartStackedBar = "stackedBar"
+	ChartLine       = "line"
+	ChartPie        = "pie"
+)
+
+// chartTicks is the preferred number of intervals on the value axis.
+const chartTicks = 5
+
+// DefaultChartColors are the colors of the series or the pie slices of a ChartBox without colors.
+var DefaultChartColors = []Color{
+	{R: 31, G: 119, B: 180},
+	{R: 255, G: 127, B: 14},
+	{R: 44, G: 160, B: 44},
+	{R: 214, G: 39, B: 40},
+	{R: 148, G: 103, B: 189},
+	{R: 140, G: 86, B: 75},
+	{R: 227, G: 119, B: 194},
+	{R: 127, G: 127, B: 127},
+}
+
+// ChartSeries is a named row of values, one value for each label of the chart.
+type ChartSeries struct {
+	Name   string
+	Values []float64
+}
+
+// ChartBox is a bar, stacked bar, line or pie chart with a legend, e.g. the hours per project of a time-tracking report.
+//
+// Kind is "bar", "stackedBar", "line" or "pie".
+//
+// Labels are the categories on the x-axis, or the slices of a pie chart.
+// Each of the Series has one value for each label, a pie chart shows exactly one series.
+// The values of a stacked bar and a pie chart must not be negative.
+//
+// The optional Title is printed in bold above the chart. The legend below the chart shows the series names,
+// or the labels of the pie slices with their percentages.
+//
+// Colors are used one after the other for the series or the pie slices, DefaultChartColors if empty.
+// The grid lines are drawn in GridColor.
+//
+// Height is the overall height including the title and the legend.
+// FontSize is measured in points, if it is 0, the current font size of the generator is used.
+type ChartBox struct {
+	BoxConstraints
+	Kind      string
+	Title     string
+	Labels    []string
+	Series    []ChartSeries
+	Colors    []Color
+	GridColor Color
+	Height    float64
+	FontSize  float64
+}
+
+// chartArea is a rectangle of the chart in the unit of measure specified in NewPDFGenerator().
+type chartArea struct {
+	x, y, w, h float64
+}
+
+// legendEntry is a colored square with a text in the legend.
+type legendEntry struct {
+	color Color
+	text  string
+}
+
+// Validate checks the kind, that each series has a value for each label, the values of stacked bar and pie charts
+// and that the values fit on the value axis.
+func (c ChartBox) Validate() error {
+	switch c.Kind {
+	case ChartBar, ChartStackedBar, ChartLine, ChartPie:
+	default:
+		return errorsWithStack.New(fmt.Sprintf("the chart kind \"%s\" must be bar, stackedBar, line or pie", c.Kind))
+	}
+
+	if len(c.Labels) == 0 || len(c.Series) == 0 {
+		return errorsWithStack.New(fmt.Sprintf("a chart requires at least one label and one series"))
+	}
+
+	if c.Kind == ChartPie && len(c.Series) != 1 {
+		return errorsWithStack.New(fmt.Sprintf("a pie chart shows exactly one series, got %d", len(c.Series)))
+	}
+
+	if c.Height <= 0 {
+		return errorsWithStack.New(fmt.Sprintf("the chart height (%f) must be greater than 0", c.Height))
+	}
+
+	total := 0.
+	for _, series := range c.Series {
+		if len(series.Values) != len(c.Labels) {
+			return errorsWithStack.New(fmt.Sprintf("the series \"%s\" has %d values for %d labels", series.Name, len(series.Values), len(c.Labels)))
+		}
+
+		for _, value := range series.Values {
+			if math.IsNaN(value) || math.IsInf(value, 0) {
+				return errorsWithStack.New(fmt.Sprintf("the series \"%s\" has an invalid value", series.Name))
+			}
+			if value < 0 && (c.Kind == ChartStackedBar || c.Kind == ChartPie) {
+				return errorsWithStack.New(fmt.Sprintf("the series \"%s\" of a %s chart has a negative value", series.Name, c.Kind))
+			}
+			total += value
+		}
+	}
+
+	if c.Kind == ChartPie && total == 0 {
+		return errorsWithStack.New(fmt.Sprintf("the values of a pie chart must not all be 0"))
+	}
+
+	if math.IsInf(total, 0) {
+		return errorsWithStack.New(fmt.Sprintf("the sum of the chart values is too large"))
+	}
+
+	if c.Kind != ChartPie {
+		if _, err := axisTicks(c.valueRange()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Measure returns the fixed height.
+func (c ChartBox) Measure(_ Generator, _ float64) (height float64) {
+	return c.Height
+}
+
+// Render draws the title, the chart and the legend.
+func (c ChartBox) Render(core Generator, x float64, y float64, width float64) {
+	if err := c.Validate(); err != nil {
+		core.SetError(errorsWithStack.New(err.Error()))
+		return
+	}
+
+	fontSize := core.GetFontSize()
+	defer core.SetFontSize(fontSize)
+	if c.FontSize > 0 {
+		core.SetFontSize(c.FontSize)
+	}
+	textHeight := core.GetLineHeight(core.GetFontSize(), 0)
+
+	area := chartArea{x: x, y: y, w: width, h: c.Height}
+	if c.Title != "" {
+		core.SetUnsafeCursor(x+width/2, y)
+		core.PrintPdfText(c.Title, "b", "C")
+		area.y += 1.5 * textHeight
+		area.h -= 1.5 * textHeight
+	}
+
+	legendHeight := c.renderLegend(core, area, textHeight)
+	area.h -= legendHeight + textHeight/2
+
+	if area.h <= 2*textHeight {
+		core.SetError(errorsWithStack.New(fmt.Sprintf("The chart height %f is too small for its title and legend.", c.Height)))
+		return
+	}
+
+	if c.Kind == ChartPie {
+		c.renderPie(core, area)
+	} else {
+		c.renderAxes(core, area, textHeight)
+	}
+}
+
+// color returns the color of the series or the pie slice i.
+func (c ChartBox) color(i int) Color {
+	colors := c.Colors
+	if len(colors) == 0 {
+		colors = DefaultChartColors
+	}
+	return colors[i%len(colors)]
+}
+
+// legendEntries returns the series names, or the labels of the pie slices with their percentages.
+func (c ChartBox) legendEntries() (entries []legendEntry) {
+	if c.Kind == ChartPie {
+		total := 0.
+		for _, value := range c.Series[0].Values {
+			total += value
+		}
+		for i, label := range c.Labels {
+			entries = append(entries, legendEntry{color: c.color(i), text: fmt.Sprintf("%s (%.0f %%)", label, 100*c.Series[0].Values[i]/total)})
+		}
+		return entries
+	}
+
+	for i, series := range c.Series {
+		if series.Name != "" {
+			entries = append(entries, legendEntry{color: c.color(i), text: series.Name})
+		}
+	}
+	return entries
+}
+
+// renderLegend prints the legend entries at the bottom of area in centered rows and returns the height of the legend.
+func (c ChartBox) renderLegend(core Generator, area chartArea, textHeight float64) (height float64) {
+	entries := c.legendEntries()
+	if len(entries) == 0 {
+		return 0
+	}
+
+	square := .7 * textHeight
+	entryWidth := func(entry legendEntry) float64 {
+		return square + 1 + core.ComputeStringLength(entry.text) + 2*square
+	}
+
+	// wrap the entries into rows of the area width
+	var rows [][]legendEntry
+	rowWidth := 0.
+	for _, entry := range entries {
+		if len(rows) == 0 || rowWidth+entryWidth(entry) > area.w {
+			rows = append(rows, nil)
+			rowWidth = 0
+		}
+		rows[len(rows)-1] = append(rows[len(rows)-1], entry)
+		rowWidth += entryWidth(entry)
+	}
+
+	height = float64(len(rows)) * 1.5 * textHeight
+	y := area.y + area.h - height
+	for _, row := range rows {
+		rowWidth = 0
+		for _, entry := range row {
+			rowWidth += entryWidth(entry)
+		}
+
+		x := area.x + (area.w-rowWidth)/2
+		for _, entry := range row {
+			core.DrawRect(x, y+(textHeight-square)/2, square, square, 0, ShapeStyle{Fill: true, FillColor: entry.color})
+			core.SetUnsafeCursor(x+square, y)
+			core.PrintPdfText(entry.text, "", "L")
+			x += entryWidth(entry)
+		}
+		y += 1.5 * textHeight
+	}
+	return height
+}
+
+// valueRange returns the lowest and the highest value shown on the value axis, including 0.
+func (c ChartBox) valueRange() (low float64, high float64) {
+	for i := range c.Labels {
+		stacked := 0.
+		for _, series := range c.Series {
+			value := series.Values[i]
+			stacked += value
+			low = math.Min(low, value)
+			high = math.Max(high, value)
+		}
+		if c.Kind == ChartStackedBar {
+			high = math.Max(high, stacked)
+		}
+	}
+	return low, high
+}
+
+// renderAxes draws the value axis with the grid lines, the labels and the bars or lines.
+func (c ChartBox) renderAxes(core Generator, area chartArea, textHeight float64) {
+	// the ticks are checked by Validate
+	ticks, _ := axisTicks(c.valueRange())
+	decimals := int(math.Max(0, -math.Floor(math.Log10(ticks[1]-ticks[0])+1e-9)))
+
+	// the value labels are printed left of the plot, the labels below
+	labelWidth := 0.
+	for _, tick := range ticks {
+		labelWidth = math.Max(labelWidth, core.ComputeStringLength(fmt.Sprintf("%.*f", decimals, tick)))
+	}
+	plot := chartArea{x: area.x + labelWidth + 2, y: area.y + textHeight/2, w: area.w - labelWidth - 2}
+	plot.h = area.y + area.h - textHeight - 1 - plot.y
+
+	low, high := ticks[0], ticks[len(ticks)-1]
+	yOf := func(value float64) float64 {
+		return plot.y + plot.h - (value-low)/(high-low)*plot.h
+	}
+
+	for _, tick := range ticks {
+		core.DrawStyledLine(plot.x, yOf(tick), plot.x+plot.w, yOf(tick), LineStyle{Color: c.GridColor, Width: .1})
+		core.SetUnsafeCursor(plot.x-1, yOf(tick)-textHeight/2)
+		core.PrintPdfText(fmt.Sprintf("%.*f", decimals, tick), "", "R")
+	}
+
+	groupWidth := plot.w / float64(len(c.Labels))
+	for i, label := range c.Labels {
+		if label != "" {
+			core.SetUnsafeCursor(plot.x+(float64(i)+.5)*groupWidth, plot.y+plot.h+1)
+			core.PrintPdfText(fitText(core, label, groupWidth), "", "C")
+		}
+	}
+
+	switch c.Kind {
+	case ChartLine:
+		c.renderLines(core, plot, groupWidth, yOf)
+	default:
+		c.renderBars(core, plot, groupWidth, yOf)
+	}
+
+	// the zero line above the bars
+	core.DrawStyledLine(plot.x, yOf(0), plot.x+plot.w, yOf(0), LineStyle{Color: c.GridColor, Width: .3})
+}
+
+// renderBars draws the bars of each label side by side, or stacked.
+func (c ChartBox) renderBars(core Generator, plot chartArea, groupWidth float64, yOf func(value float64) float64) {
+	barWidth := .7 * groupWidth
+	if c.Kind == ChartBar {
+		barWidth /= float64(len(c.Series))
+	}
+
+	for i := range c.Labels {
+		x := plot.x + (float64(i)+.15)*groupWidth
+		stacked := 0.
+		for s, series := range c.Series {
+			from, to := 0., series.Values[i]
+			if c.Kind == ChartStackedBar {
+				from, to = stacked, stacked+series.Values[i]
+				stacked = to
+			}
+
+			top, bottom := yOf(math.Max(from, to)), yOf(math.Min(from, to))
+			if bottom-top > 1e-9 {
+				core.DrawRect(x, top, barWidth, bottom-top, 0, ShapeStyle{Fill: true, FillColor: c.color(s)})
+			}
+			if c.Kind == ChartBar {
+				x += barWidth
+			}
+		}
+	}
+}
+
+// renderLines draws each series as line with a marker at each value.
+func (c ChartBox) renderLines(core Generator, plot chartArea, groupWidth float64, yOf func(value float64) float64) {
+	for s, series := range c.Series {
+		color := c.color(s)
+		for i, value := range series.Values {
+			x := plot.x + (float64(i)+.5)*groupWidth
+			if i > 0 {
+				core.DrawStyledLine(x-groupWidth, yOf(series.Values[i-1]), x, yOf(value), LineStyle{Color: color, Width: .5})
+			}
+			core.DrawCircle(x, yOf(value), .8, ShapeStyle{Fill: true, FillColor: color})
+		}
+	}
+}
+
+// renderPie draws the slices clockwise from the top with white borders.
+func (c ChartBox) renderPie(core Generator, area chartArea) {
+	values := c.Series[0].Values
+	total := 0.
+	for _, value := range values {
+		total += value
+	}
+
+	r := math.Min(area.w, area.h) / 2
+	cx, cy := area.x+area.w/2, area.y+area.h/2
+	border := LineStyle{Color: Color{R: 255, G: 255, B: 255}, Width: .3}
+
+	angle := -math.Pi / 2
+	for i, value := range values {
+		if value == 0 {
+			continue
+		}
+		style := ShapeStyle{Fill: true, FillColor: c.color(i), Stroke: true, Line: border}
+		if value == total {
+			core.DrawCircle(cx, cy, r, style)
+			return
+		}
+
+		sweep := 2 * math.Pi * value / total
+		points := []Point{{X: cx, Y: cy}}
+		steps := int(math.Ceil(sweep/(2*math.Pi)*shapeCurveSegments)) + 1
+		for step := 0; step <= steps; step++ {
+			a := angle + sweep*float64(step)/float64(steps)
+			points = append(points, Point{X: cx + r*math.Cos(a), Y: cy + r*math.Sin(a)})
+		}
+		core.DrawPolygon(points, style)
+		angle += sweep
+	}
+}
+
+// axisTicks returns the values of the grid lines in steps of 1, 2 or 5 times a power of ten from low to high.
+// It returns an error, if the range can't be divided into steps of float64 values, e.g. from -1e308 to 1e308.
+func axisTicks(low float64, high float64) (ticks []float64, err error) {
+	if high-low <= 0 {
+		high = low + 1
+	}
+
+	rawStep := (high - low) / chartTicks
+	if math.IsNaN(rawStep) || math.IsInf(rawStep, 0) || rawStep == 0 {
+		return nil, errorsWithStack.New(fmt.Sprintf("the values from %g to %g can't be shown on a value axis", low, high))
+	}
+	magnitude := math.Pow(10, math.Floor(math.Log10(rawStep)))
+	step := 10 * magnitude
+	for _, factor := range []float64{1, 2, 5} {
+		if rawStep <= factor*magnitude {
+			step = factor * magnitude
+			break
+		}
+	}
+
+	// the step must change the ticks, e.g. it is lost in 1e20 + 1
+	start := math.Floor(low/step+1e-9) * step
+	if math.IsNaN(start) || math.IsInf(start, 0) || math.IsInf(step, 0) || start+step == start {
+		return nil, errorsWithStack.New(fmt.Sprintf("the values from %g to %g can't be shown on a value axis", low, high))
+	}
+
+	// the steps cover the range with chartTicks + 2 ticks at most, more ticks are rounding errors
+	for i := 0; i <= 2*chartTicks; i++ {
+		tick := start + float64(i)*step
+		if math.IsInf(tick, 0) {
+			break
+		}
+		ticks = append(ticks, tick)
+		if tick >= high-1e-9*step {
+			return ticks, nil
+		}
+	}
+	return nil, errorsWithStack.New(fmt.Sprintf("the values from %g to %g can't be shown on a value axis", low, high))
+}
+
+// fitText shortens text with "...", until it fits into width.
+func fitText(core Generator, text string, width float64) string {
+	runes := []rune(text)
+	for len(runes) > 1 && core.ComputeStringLength(string(runes)) > width {
+		runes = runes[:len(runes)-1]
+		text = string(runes) + "..."
+		if core.ComputeStringLength(text) <= width {
+			return text
+		}
+	}
+	return text
+}
@@ -0,0 +1,195 @@
+package generator
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestChartBox_Validate(t *testing.T) {
+	labels := []string{"Jan", "Feb", "Mar"}
+
+	tests := []struct {
+		name    string
+		chart   ChartBox
+		wantErr bool
+	}{
+		{
+			name:    "bar chart with negative values",
+			chart:   ChartBox{Kind: ChartBar, Labels: labels, Series: []ChartSeries{{Name: "Saldo", Values: []float64{3, -2, 5}}}, Height: 60},
+			wantErr: false,
+		},
+		{
+			name:    "pie chart",
+			chart:   ChartBox{Kind: ChartPie, Labels: labels, Series: []ChartSeries{{Values: []float64{3, 0, 5}}}, Height: 60},
+			wantErr: false,
+		},
+		{
+			name:    "unknown kind",
+			chart:   ChartBox{Kind: "area", Labels: labels, Series: []ChartSeries{{Values: []float64{3, 2, 5}}}, Height: 60},
+			wantErr: true,
+		},
+		{
+			name:    "missing value",
+			chart:   ChartBox{Kind: ChartLine, Labels: labels, Series: []ChartSeries{{Values: []float64{3, 2}}}, Height: 60},
+			wantErr: true,
+		},
+		{
+			name:    "negative stacked value",
+			chart:   ChartBox{Kind: ChartStackedBar, Labels: labels, Series: []ChartSeries{{Values: []float64{3, -2, 5}}}, Height: 60},
+			wantErr: true,
+		},
+		{
+			name:    "pie chart with two series",
+			chart:   ChartBox{Kind: ChartPie, Labels: labels, Series: []ChartSeries{{Values: []float64{3, 2, 5}}, {Values: []float64{3, 2, 5}}}, Height: 60},
+			wantErr: true,
+		},
+		{
+			name:    "values exceeding the value axis",
+			chart:   ChartBox{Kind: ChartBar, Labels: labels[:2], Series: []ChartSeries{{Values: []float64{-1e308, 1e308}}}, Height: 60},
+			wantErr: true,
+		},
+		{
+			name:    "stacked values exceeding the value axis",
+			chart:   ChartBox{Kind: ChartStackedBar, Labels: labels[:1], Series: []ChartSeries{{Values: []float64{1e308}}, {Values: []float64{1e308}}}, Height: 60},
+			wantErr: true,
+		},
+		{
+			name:    "pie chart with an infinite sum",
+			chart:   ChartBox{Kind: ChartPie, Labels: labels[:2], Series: []ChartSeries{{Values: []float64{1e308, 1e308}}}, Height: 60},
+			wantErr: true,
+		},
+		{
+			name:    "pie chart without values",
+			chart:   ChartBox{Kind: ChartPie, Labels: labels, Series: []ChartSeries{{Values: []float64{0, 0, 0}}}, Height: 60},
+			wantErr: true,
+		},
+		{
+			name:    "invalid value",
+			chart:   ChartBox{Kind: ChartBar, Labels: labels, Series: []ChartSeries{{Values: []float64{3, math.NaN(), 5}}}, Height: 60},
+			wantErr: true,
+		},
+		{
+			name:    "no height",
+			chart:   ChartBox{Kind: ChartBar, Labels: labels, Series: []ChartSeries{{Values: []float64{3, 2, 5}}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.chart.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_axisTicks(t *testing.T) {
+	tests := []struct {
+		name      string
+		low       float64
+		high      float64
+		wantTicks []float64
+		wantErr   bool
+	}{
+		{name: "steps of 2", low: 0, high: 9, wantTicks: []float64{0, 2, 4, 6, 8, 10}},
+		{name: "steps of 50", low: 0, high: 230, wantTicks: []float64{0, 50, 100, 150, 200, 250}},
+		{name: "negative values", low: -3, high: 4, wantTicks: []float64{-4, -2, 0, 2, 4}},
+		{name: "small values", low: 0, high: .35, wantTicks: []float64{0, .1, .2, .30000000000000004, .4}},
+		{name: "no values", low: 0, high: 0, wantTicks: []float64{0, .2, .4, .6000000000000001, .8, 1}},
+		{name: "range overflows", low: -1e308, high: 1e308, wantErr: true},
+		{name: "last tick overflows", low: 0, high: 1.7e308, wantErr: true},
+		{name: "infinite value", low: 0, high: math.Inf(1), wantErr: true},
+		{name: "not a number", low: math.NaN(), high: 0, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := axisTicks(tt.low, tt.high)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("axisTicks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.wantTicks) {
+				t.Errorf("axisTicks() = %v, want %v", got, tt.wantTicks)
+			}
+		})
+	}
+}
+
+func TestChartBox_Render(t *testing.T) {
+	labels := []string{"Jan", "Feb", "Mar"}
+	series := []ChartSeries{{Name: "Projekt A", Values: []float64{3, 0, 5}}, {Name: "Projekt B", Values: []float64{1, 2, 2}}}
+
+	tests := []struct {
+		name         string
+		chart        ChartBox
+		wantRects    int
+		wantPolygons int
+		wantLines    int
+		wantErr      bool
+	}{
+		{
+			name:      "bar chart",
+			chart:     ChartBox{Kind: ChartBar, Title: "Stunden", Labels: labels, Series: series, Height: 60},
+			wantRects: 2 + 5,
+			wantLines: 6 + 1,
+			wantErr:   false,
+		},
+		{
+			name:      "stacked bar chart",
+			chart:     ChartBox{Kind: ChartStackedBar, Labels: labels, Series: series, Height: 60},
+			wantRects: 2 + 5,
+			wantLines: 5 + 1,
+			wantErr:   false,
+		},
+		{
+			name:      "line chart",
+			chart:     ChartBox{Kind: ChartLine, Labels: labels, Series: series, Height: 60},
+			wantRects: 2,
+			wantLines: 6 + 2*2 + 1,
+			wantErr:   false,
+		},
+		{
+			name:         "pie chart",
+			chart:        ChartBox{Kind: ChartPie, Labels: labels, Series: series[:1], Height: 60},
+			wantRects:    3,
+			wantPolygons: 2,
+			wantErr:      false,
+		},
+		{
+			name:    "too small for the legend",
+			chart:   ChartBox{Kind: ChartBar, Title: "Stunden", Labels: labels, Series: series, Height: 5},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, err := NewRecordingGenerator(_defaultMetaData, false, &_logger, func() {}, func(isLastPage bool) {})
+			if err != nil {
+				t.Fatalf("init recorder error\n%s", err.Error())
+			}
+			rec.NewPage()
+			rec.SetFontSize(10)
+
+			tt.chart.Render(rec, 20, 20, 150)
+			if (rec.GetError() != nil) != tt.wantErr {
+				t.Fatalf("Render() error = %v, wantErr %v", rec.GetError(), tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got := len(rec.OperationsByName("DrawRect")); got != tt.wantRects {
+				t.Errorf("Render() drew %d rectangles, want %d", got, tt.wantRects)
+			}
+			if got := len(rec.OperationsByName("DrawPolygon")); got != tt.wantPolygons {
+				t.Errorf("Render() drew %d polygons, want %d", got, tt.wantPolygons)
+			}
+			if got := len(rec.OperationsByName("DrawStyledLine")); got != tt.wantLines {
+				t.Errorf("Render() drew %d lines, want %d", got, tt.wantLines)
+			}
+			if rec.GetFontSize() != 10 {
+				t.Errorf("Render() changed the font size to %v", rec.GetFontSize())
+			}
+		})
+	}
+}
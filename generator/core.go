@@ -6,7 +6,6 @@ import (
 	"github.com/jung-kurt/gofpdf"
 	"github.com/rs/zerolog"
 	"math"
-	"net/http"
 	"net/url"
 	"strings"
 )
@@ -48,21 +47,96 @@ func NewPDFGenerator(data MetaData, strictErrorHandling bool, logger *zerolog.Lo
 	if data.MarginBottom < 0 {
 		return nil, errorsWithStack.New(fmt.Sprintf("A negative MarginBottom (%f) is not allowed.", data.MarginBottom))
 	}
-	// <--
 
-	// create new PDF
-	pdf := gofpdf.New("P", data.Unit, "A4", "")
-	if data.FontName == "OpenSans" {
-		pdf.AddUTF8Font("OpenSans", "", "fonts/OpenSans-Regular.ttf")
-		pdf.AddUTF8Font("OpenSans", "l", "fonts/OpenSans-Light.ttf")
-		pdf.AddUTF8Font("OpenSans", "i", "fonts/OpenSans-Italic.ttf")
-		pdf.AddUTF8Font("OpenSans", "b", "fonts/OpenSans-Bold.ttf")
-		pdf.AddUTF8Font("OpenSans", "m", "fonts/OpenSans-Medium.ttf")
+	if data.PageSize.Width < 0 || data.PageSize.Height < 0 {
+		return nil, errorsWithStack.New(fmt.Sprintf("A negative PageSize (%f x %f) is not allowed.", data.PageSize.Width, data.PageSize.Height))
+	}
+
+	if data.Orientation == "" {
+		data.Orientation = "P"
+	}
+
+	if data.Orientation != "P" && data.Orientation != "L" {
+		return nil, errorsWithStack.New(fmt.Sprintf("The Orientation must be P or L."))
+	}
+
+	if data.TableStyle == (TableStyle{}) {
+		data.TableStyle = DefaultTableStyle
+		data.TableStyle.BorderColor = data.DefaultLineColor
+	}
+
+	if data.TableStyle.Border == "" {
+		data.TableStyle.Border = "horizontal"
+	}
+
+	validBorders := map[string]bool{"horizontal": true, "grid": true, "none": true}
+	if !validBorders[data.TableStyle.Border] {
+		return nil, errorsWithStack.New(fmt.Sprintf("The table border \"%s\" must be horizontal, grid or none.", data.TableStyle.Border))
+	}
+
+	if data.FontRegistry == nil {
+		data.FontRegistry = DefaultFontRegistry
+	}
+
+	if data.ImageCache == nil {
+		data.ImageCache = DefaultImageCache
 	}
-	pdf.SetFont(data.FontName, "", data.FontSize)
+
+	var family *fontFamily
+	if !coreFontNames[strings.ToLower(data.FontName)] {
+		family, err = data.FontRegistry.family(data.FontName)
+		if err != nil {
+			return nil, errorsWithStack.New(fmt.Sprintf("Can not load the font families: %s", err.Error()))
+		}
+	}
+
+	var fallbackFamilies []*fontFamily
+	for _, fallbackName := range data.FallbackFontNames {
+		fallback, err := data.FontRegistry.family(fallbackName)
+		if err != nil {
+			return nil, errorsWithStack.New(fmt.Sprintf("Can not load the font families: %s", err.Error()))
+		}
+		if fallback == nil {
+			return nil, errorsWithStack.New(fmt.Sprintf("The fallback font \"%s\" is not registered.", fallbackName))
+		}
+		fallbackFamilies = append(fallbackFamilies, fallback)
+	}
+
+	if len(fallbackFamilies) > 0 && family == nil {
+		return nil, errorsWithStack.New(fmt.Sprintf("Fallback fonts require a registered font family, \"%s\" is not registered.", data.FontName))
+	}
+	// <--
+
+	// create new PDF, the page size is DIN A4 if data.PageSize is not set
+	pdf := gofpdf.NewCustom(&gofpdf.InitType{
+		OrientationStr: data.Orientation,
+		UnitStr:        data.Unit,
+		SizeStr:        "A4",
+		Size:           gofpdf.SizeType{Wd: data.PageSize.Width, Ht: data.PageSize.Height},
+	})
+
+	// create new PDFGenerator instance
+	gen = new(PDFGenerator)
+	gen.logger = logger
+	pageWidth, pageHeight := pdf.GetPageSize()
+	gen.pdf = pdf
+	gen.data = data
+	gen.fonts = data.FontRegistry
+	gen.fontFamily = family
+	gen.fallbackFontFamilies = fallbackFamilies
+	gen.addedFontStyles = map[string]bool{}
+	gen.strictErrorHandling = strictErrorHandling
+	gen.maxSaveX = pageWidth - data.MarginRight
+	gen.maxSaveY = pageHeight - data.MarginBottom
+	gen.registeredImageTypes = map[string]string{}
+	gen.registeredSvgImages = map[string]*svgImage{}
+	gen.registeredPdfPages = map[string]int{}
+
+	gen.setFont(family, "", data.FontSize)
 	pdf.SetMargins(data.MarginLeft, data.MarginTop, data.MarginRight)
 	pdf.SetLineWidth(data.DefaultLineWidth)
 	pdf.SetDrawColor(int(data.DefaultLineColor.R), int(data.DefaultLineColor.G), int(data.DefaultLineColor.B))
+	pdf.SetTextColor(int(data.DefaultTextColor.R), int(data.DefaultTextColor.G), int(data.DefaultTextColor.B))
 	pdf.SetHomeXY()
 	pdf.SetAutoPageBreak(true, data.MarginBottom)
 	//pdf.AliasNbPages("{entute}")
@@ -80,17 +154,6 @@ func NewPDFGenerator(data MetaData, strictErrorHandling bool, logger *zerolog.Lo
 		return nil, pdf.Error()
 	}
 
-	// create new PDFGenerator instance
-	gen = new(PDFGenerator)
-	gen.logger = logger
-	pageWidth, pageHeight := pdf.GetPageSize()
-	gen.pdf = pdf
-	gen.data = data
-	gen.strictErrorHandling = strictErrorHandling
-	gen.maxSaveX = pageWidth - data.MarginRight
-	gen.maxSaveY = pageHeight - data.MarginBottom
-	gen.registeredImageTypes = map[string]string{}
-
 	return gen, pdf.Error()
 }
 
@@ -102,9 +165,16 @@ func NewPDFGenerator(data MetaData, strictErrorHandling bool, logger *zerolog.Lo
 //
 //	"" non-specific font style
 //	"l" light font
-//	"i" italic font
-//	"b" bold font
 //	"m" medium font
+//	"d" semi-bold font
+//	"b" bold font
+//	"e" extra-bold font
+//	"i" italic font
+//	"u" underlined text
+//	"s" struck-through text
+//
+// The letters can be combined with at most one weight, e.g. "di" or "bu" (see ParseFontStyle).
+// A missing weight or italic font of the font family is simulated.
 //
 // alignStr set the align mode:
 //
@@ -129,27 +199,31 @@ func (core *PDFGenerator) PrintPdfText(text string, styleStr string, alignStr st
 		return
 	}
 
-	// TODO adjust style string ("B" (bold), "I" (italic), "U" (underscore), "S" (strike-out) or any combination. The default value (specified with an empty string) is regular.)
+	if _, err := ParseFontStyle(styleStr); err != nil {
+		core.pdf.SetError(errorsWithStack.New(err.Error()))
+		return
+	}
 	// <--
 
-	core.pdf.SetFont(core.data.FontName, styleStr, core.GetFontSize())
+	core.setFont(core.fontFamily, styleStr, core.GetFontSize())
 	_, lineHeight := core.pdf.GetFontSize()
-	stringWidth := core.pdf.GetStringWidth(text) + 2
+	stringWidth := core.textWidth(text, styleStr) + 2
 
 	switch alignStr {
 	case "L":
-		core.pdf.Cell(stringWidth, lineHeight, text)
+		core.cellFormat(stringWidth, lineHeight, text, styleStr, "", "", false)
 	case "R":
 		x := core.pdf.GetX()
 		core.pdf.SetX(x - stringWidth)
-		core.pdf.Cell(stringWidth, lineHeight, text)
+		core.cellFormat(stringWidth, lineHeight, text, styleStr, "", "", false)
 	case "C":
 		x := core.pdf.GetX()
 		core.pdf.SetX(x - stringWidth/2)
-		core.pdf.Cell(stringWidth, lineHeight, text)
+		core.cellFormat(stringWidth, lineHeight, text, styleStr, "", "", false)
 		//default:
 		//	core.pdf.SetError(errorsWithStack.New("can't interpret the given text align code"))
 	}
+	core.recordCell(text, styleStr, "", "", false, Color{}, lineHeight, stringWidth)
 }
 
 // PrintLnPdfText prints from the current cursor position a simple text cell in the PDF
@@ -162,9 +236,15 @@ func (core *PDFGenerator) PrintPdfText(text string, styleStr string, alignStr st
 //
 //	"" non-specific font style,
 //	"l" light font,
+//	"m" medium font,
+//	"d" semi-bold font,
+//	"b" bold font,
+//	"e" extra-bold font,
 //	"i" italic font,
-//	"b" bold font, or
-//	"m" medium font.
+//	"u" underlined text, or
+//	"s" struck-through text.
+//
+// The letters can be combined with at most one weight, e.g. "di" or "bu" (see ParseFontStyle).
 //
 // alignStr set the align mode:
 //
@@ -189,7 +269,10 @@ func (core *PDFGenerator) PrintLnPdfText(text string, styleStr string, alignStr
 		return
 	}
 
-	// TODO adjust style string ("B" (bold), "I" (italic), "U" (underscore), "S" (strike-out) or any combination. The default value (specified with an empty string) is regular.)
+	if _, err := ParseFontStyle(styleStr); err != nil {
+		core.pdf.SetError(errorsWithStack.New(err.Error()))
+		return
+	}
 	// <--
 
 	lines := core.extractLinesFromText(text)
@@ -272,11 +355,17 @@ func (core *PDFGenerator) extractLinesFromText(text string) (textLines []string)
 //
 // styleStr defines the font style:
 //
-//	 "" non-specific font style
-//		"l" light font
-//		"i" italic font
-//		"b" bold font
-//		"m" medium font
+//	"" non-specific font style
+//	"l" light font
+//	"m" medium font
+//	"d" semi-bold font
+//	"b" bold font
+//	"e" extra-bold font
+//	"i" italic font
+//	"u" underlined text
+//	"s" struck-through text
+//
+// The letters can be combined with at most one weight, e.g. "di" or "bu" (see ParseFontStyle).
 //
 // *alignStr* set the align mode. The default alignment is left middle.
 //
@@ -319,56 +408,52 @@ func (core *PDFGenerator) PrintPdfTextFormatted(text string, styleStr string, al
 		return
 	}
 
-	// TODO adjust style string ("B" (bold), "I" (italic), "U" (underscore), "S" (strike-out) or any combination. The default value (specified with an empty string) is regular.)
+	if _, err := ParseFontStyle(styleStr); err != nil {
+		core.pdf.SetError(errorsWithStack.New(err.Error()))
+		return
+	}
 	// TODO valide borderStr?
 	// <--
 
-	core.pdf.SetFont(core.data.FontName, styleStr, core.GetFontSize())
+	core.setFont(core.fontFamily, styleStr, core.GetFontSize())
 	if fill {
 		core.pdf.SetFillColor(int(backgroundColor.R), int(backgroundColor.G), int(backgroundColor.B))
 	}
-	core.pdf.CellFormat(cellWidth, cellHeight, text, borderStr, 0, alignStr, fill, 0, "")
+	core.cellFormat(cellWidth, cellHeight, text, styleStr, borderStr, alignStr, fill)
+	core.recordCell(text, styleStr, alignStr, borderStr, fill, backgroundColor, cellHeight, cellWidth)
 }
 
 // DrawLine draw a user defines line between two points.
+// Use GetLineColor and GetLineWidth for a line in the DefaultLineColor and the DefaultLineWidth,
+// use DrawStyledLine for a dashed or dotted line.
 //
 // x1 and y1 defines the abscissa (x) and ordinate (y) cursor start point.
 //
 // x2 and y2 defines the abscissa (x) and ordinate (y) cursor end point.
 //
-// color specifies the color of the line.
-//
-// lineWith specifies the thinness of the line in the unit of measure specified in NewPDFGenerator().
-func (core *PDFGenerator) DrawLine(x1 float64, y1 float64, x2 float64, y2 float64) {
+// color and lineWidth define the look of the line, a lineWidth of 0 uses the DefaultLineWidth.
+func (core *PDFGenerator) DrawLine(x1 float64, y1 float64, x2 float64, y2 float64, color Color, lineWidth float64) {
 	if core.strictErrorHandling == true && core.pdf.Err() {
 		return
 	}
 
-	// --> validate inputs
-	pageWidth, pageLength := core.pdf.GetPageSize()
-
-	if x1 < 0 || x1 > pageWidth {
-		core.pdf.SetError(errorsWithStack.New(fmt.Sprintf("x1 (%f) is out of range [%f, %f].", x1, 0.0, pageWidth)))
-		return
-	}
-
-	if x2 < 0 || x2 > pageWidth {
-		core.pdf.SetError(errorsWithStack.New(fmt.Sprintf("x2 (%f) is out of range [%f, %f].", x2, 0.0, pageWidth)))
-		return
-	}
+	style := LineStyle{Color: color, Width: lineWidth}
 
-	if y1 < 0 || y1 > pageLength {
-		core.pdf.SetError(errorsWithStack.New(fmt.Sprintf("y1 (%f) is out of range [%f, %f].", y1, 0.0, pageLength)))
+	// --> validate inputs
+	if err := style.validate(); err != nil {
+		core.pdf.SetError(err)
 		return
 	}
 
-	if y2 < 0 || y2 > pageLength {
-		core.pdf.SetError(errorsWithStack.New(fmt.Sprintf("y2 (%f) is out of range [%f, %f].", y2, 0.0, pageLength)))
+	if !core.pointsOnPage([]Point{{X: x1, Y: y1}, {X: x2, Y: y2}}) {
 		return
 	}
 	// <--
 
+	restore := core.setLineStyle(style)
 	core.pdf.Line(x1, y1, x2, y2)
+	core.recordLine(x1, y1, x2, y2, color, core.pdf.GetLineWidth())
+	restore()
 }
 
 // RegisterMimeImageToPdf downloade a JPEG, PNG or GIF image (from mostly a Content Delivery Network (CDN)) URL
@@ -376,6 +461,10 @@ func (core *PDFGenerator) DrawLine(x1 float64, y1 float64, x2 float64, y2 float6
 // The image will be registered in the PDF but not place on a page!
 // Use PlaceRegisteredImageOnPage to place the image on a page.
 //
+// The image is downloaded once and kept in the MetaData.ImageCache, see ImageCache for the timeout and the allowed hosts.
+// The download is limited to MaxImageSize, the image type is read from the image data.
+// Use RegisterImage to register an image without a download.
+//
 // cdnUrl specifies a parsed (CDN) URL.
 //
 // return imageNameStr, the image identifier for placing the image on a pdf page.
@@ -384,31 +473,18 @@ func (core *PDFGenerator) RegisterMimeImageToPdf(cdnUrl *url.URL) (imageNameStr
 		return
 	}
 
-	var rsp *http.Response
-
-	rsp, err := http.Get(cdnUrl.String())
+	imageData, err := core.data.ImageCache.Get(cdnUrl)
 	if err != nil {
 		core.pdf.SetError(errorsWithStack.New(err))
-		return
+		return ""
 	}
 
 	imageNameStr = cdnUrl.String()
-
-	imageType := core.pdf.ImageTypeFromMime(rsp.Header["Content-Type"][0])
-
-	switch imageType {
-	case "jpg":
-	case "png":
-	case "gif":
-		break
-	default:
-		core.pdf.SetError(errorsWithStack.New(fmt.Sprintf("Image type is not supported.")))
+	core.RegisterImage(imageNameStr, imageData)
+	if core.pdf.Err() {
 		return ""
 	}
 
-	core.pdf.RegisterImageReader(cdnUrl.String(), imageType, rsp.Body)
-	core.registeredImageTypes[imageNameStr] = imageType
-
 	return imageNameStr
 }
 
@@ -444,9 +520,8 @@ func (core *PDFGenerator) PlaceRegisteredImageOnPage(imageNameStr string, alignS
 	}
 	// <--
 
-	imageInfoType := core.pdf.GetImageInfo(imageNameStr)
 	posX, posY := core.GetCursor()
-	imgWd, imgHt := imageInfoType.Extent()
+	imgWd, imgHt := core.GetRegisteredImageExtent(imageNameStr)
 	imgWd, imgHt = imgWd*scale, imgHt*scale
 
 	switch alignStr {
@@ -462,7 +537,12 @@ func (core *PDFGenerator) PlaceRegisteredImageOnPage(imageNameStr string, alignS
 	}
 
 	if core.pdf.Ok() {
-		core.pdf.Image(imageNameStr, posX, posY, imgWd, imgHt, false, core.registeredImageTypes[imageNameStr], 0, "")
+		if svg, ok := core.registeredSvgImages[imageNameStr]; ok {
+			core.drawSvgImage(svg, posX, posY, imgWd, imgHt)
+		} else {
+			core.pdf.Image(imageNameStr, posX, posY, imgWd, imgHt, false, core.registeredImageTypes[imageNameStr], 0, "")
+		}
+		core.record(displayItem{kind: displayImage, x: posX, y: posY, w: imgWd, h: imgHt, imageName: imageNameStr})
 	}
 
 	return
@@ -491,8 +571,10 @@ func (core *PDFGenerator) PrintTableHeader(cells []string, columnWidth []float64
 	_, lineHeight := core.pdf.GetFontSize()
 	newlineHeight := lineHeight + core.data.FontGapY*2
 
+	defer core.useTableBorderColor()()
+
 	for i, cell := range cells {
-		core.PrintPdfTextFormatted(cell, "b", columnAlignStrings[i], "TB", true, Color{R: 239, G: 239, B: 239}, newlineHeight, columnWidth[i])
+		core.PrintPdfTextFormatted(cell, "b", columnAlignStrings[i], core.tableBorder("TB", "1"), true, core.data.TableStyle.HeaderFillColor, newlineHeight, columnWidth[i])
 	}
 
 	core.SetCursor(referenceX, core.pdf.GetY()+newlineHeight)
@@ -524,15 +606,19 @@ func (core *PDFGenerator) PrintTableBody(cells [][]string, columnWidths []float6
 		return
 	}
 
-	// TODO check all cells, that the length is equal to len(columnWidths) or len (columnAlignStrings)
-
+	if err := validateTableCells(cells, columnWidths); err != nil {
+		core.pdf.SetError(err)
+		return
+	}
 	// <--
 
 	referenceX := core.pdf.GetX()
 	_, lineHeight := core.pdf.GetFontSize()
 	newlineHeight := lineHeight + core.data.FontGapY*2
 
-	for _, row := range cells {
+	defer core.useTableBorderColor()()
+
+	for rowIndex, row := range cells {
 		var extractedLines [][]string
 		var maxLines = 0
 
@@ -543,7 +629,7 @@ func (core *PDFGenerator) PrintTableBody(cells [][]string, columnWidths []float6
 		}
 
 		for i := 0; i < maxLines; i++ {
-			core.printTableBodyRow(extractedLines, i, maxLines, columnAlignStrings, newlineHeight, columnWidths, referenceX)
+			core.printTableBodyRow(extractedLines, i, maxLines, rowIndex%2 == 1, columnAlignStrings, newlineHeight, columnWidths, referenceX)
 		}
 	}
 }
@@ -558,6 +644,8 @@ func (core *PDFGenerator) PrintTableBody(cells [][]string, columnWidths []float6
 // If one column includes less the maxItems content strings,
 // the rest will be filled with empty content items to print the bottom boarder correctly.
 //
+// stripe fills the row with the StripeFillColor of the TableStyle, if the table is striped.
+//
 // alignStrings specifies the align type of each column. Use:
 //
 //	"L" for align the left side of the text to the left side of the table cell,
@@ -571,21 +659,29 @@ func (core *PDFGenerator) PrintTableBody(cells [][]string, columnWidths []float6
 // columnWidth defines the width of each column. NOTE: use here the same widths as in PrintTableBody().
 //
 // referenceX defines the left row position to set the cursor at the end to a new line.
-func (core *PDFGenerator) printTableBodyRow(extractedLines [][]string, currentLine int, maxItems int, alignStrings []string, newlineHeight float64, columnWidth []float64, referenceX float64) {
+func (core *PDFGenerator) printTableBodyRow(extractedLines [][]string, currentLine int, maxItems int, stripe bool, alignStrings []string, newlineHeight float64, columnWidth []float64, referenceX float64) {
 	// TODO input validation
+	fill := stripe && core.data.TableStyle.Striped
+
 	for j, cell := range extractedLines {
 		var text = ""
-		var borderStr = ""
+		var horizontalBorderStr = ""
+		var gridBorderStr = "LR"
 
 		if currentLine < len(cell) {
 			text = cell[currentLine]
 		}
 
+		if currentLine == 0 {
+			gridBorderStr += "T"
+		}
+
 		if currentLine == maxItems-1 {
-			borderStr = "B"
+			horizontalBorderStr = "B"
+			gridBorderStr += "B"
 		}
 
-		core.PrintPdfTextFormatted(text, "", alignStrings[j], borderStr, false, Color{R: 239, G: 239, B: 239}, newlineHeight, columnWidth[j])
+		core.PrintPdfTextFormatted(text, "", alignStrings[j], core.tableBorder(horizontalBorderStr, gridBorderStr), fill, core.data.TableStyle.StripeFillColor, newlineHeight, columnWidth[j])
 	}
 	core.SetCursor(referenceX, core.pdf.GetY()+newlineHeight)
 }
@@ -613,8 +709,10 @@ func (core *PDFGenerator) PrintTableFooter(cells [][]string, columnWidths []floa
 		return
 	}
 
-	// TODO check all cells, that the length is equal to len(columnWidths) or len (columnAlignStrings)
-
+	if err := validateTableCells(cells, columnWidths); err != nil {
+		core.pdf.SetError(err)
+		return
+	}
 	// <--
 	if core.strictErrorHandling == true && core.pdf.Err() {
 		return
@@ -624,22 +722,24 @@ func (core *PDFGenerator) PrintTableFooter(cells [][]string, columnWidths []floa
 	_, lineHeight := core.pdf.GetFontSize()
 	newlineHeight := lineHeight + core.data.FontGapY*2
 
+	defer core.useTableBorderColor()()
+
 	for i, row := range cells {
-		boarderStr := ""
+		boarderStr := core.tableBorder("", "1")
 		fill := false
 		styleStr := ""
 
 		if len(cells)-1 == i {
-			boarderStr = "BT"
+			boarderStr = core.tableBorder("BT", "1")
 			fill = true
 			styleStr = "B"
 		}
 
 		for j, cell := range row {
 			if cell == "" {
-				core.PrintPdfTextFormatted(row[j], "", columnAlignStrings[j], "", false, Color{R: 239, G: 239, B: 239}, newlineHeight, columnWidths[j])
+				core.PrintPdfTextFormatted(row[j], "", columnAlignStrings[j], "", false, core.data.TableStyle.FooterFillColor, newlineHeight, columnWidths[j])
 			} else {
-				core.PrintPdfTextFormatted(row[j], styleStr, columnAlignStrings[j], boarderStr, fill, Color{R: 239, G: 239, B: 239}, newlineHeight, columnWidths[j])
+				core.PrintPdfTextFormatted(row[j], styleStr, columnAlignStrings[j], boarderStr, fill, core.data.TableStyle.FooterFillColor, newlineHeight, columnWidths[j])
 			}
 		}
 
@@ -647,10 +747,49 @@ func (core *PDFGenerator) PrintTableFooter(cells [][]string, columnWidths []floa
 	}
 }
 
+// validateTableCells checks, that no row of a table body or footer has more cells than columnWidths.
+// Shorter rows leave the last columns empty.
+func validateTableCells(cells [][]string, columnWidths []float64) error {
+	for i, row := range cells {
+		if len(row) > len(columnWidths) {
+			return errorsWithStack.New(fmt.Sprintf("The row %d has %d cells, but only %d columns.", i, len(row), len(columnWidths)))
+		}
+	}
+	return nil
+}
+
+// tableBorder returns the border string of a table cell for the Border of the TableStyle.
+// horizontalStr is used for the "horizontal" and gridStr for the "grid" border.
+func (core *PDFGenerator) tableBorder(horizontalStr string, gridStr string) string {
+	switch core.data.TableStyle.Border {
+	case "grid":
+		return gridStr
+	case "none":
+		return ""
+	default:
+		return horizontalStr
+	}
+}
+
+// useTableBorderColor sets the BorderColor of the TableStyle as draw color
+// and returns a function to restore the previous draw color.
+func (core *PDFGenerator) useTableBorderColor() (restore func()) {
+	r, g, b := core.pdf.GetDrawColor()
+	borderColor := core.data.TableStyle.BorderColor
+	core.pdf.SetDrawColor(int(borderColor.R), int(borderColor.G), int(borderColor.B))
+
+	return func() {
+		core.pdf.SetDrawColor(r, g, b)
+	}
+}
+
 func (core *PDFGenerator) NewPage() {
 	core.pdf.AddPage()
 }
 
+// ComputeStringLength returns the length of str with the current font size (see SetFontSize)
+// in the unit of measure specified in NewPDFGenerator().
 func (core *PDFGenerator) ComputeStringLength(str string) (length float64) {
-	return core.pdf.GetStringWidth(str)
+	core.pdf.SetFontSize(core.GetFontSize())
+	return core.textWidth(str, "")
 }
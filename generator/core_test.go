@@ -3,6 +3,7 @@ package generator
 import (
 	"github.com/jung-kurt/gofpdf"
 	"github.com/rs/zerolog"
+	"math"
 	"os"
 	"reflect"
 	"strings"
@@ -12,6 +13,26 @@ import (
 var _logger = zerolog.New(zerolog.ConsoleWriter{Out: os.Stdout}).Level(zerolog.DebugLevel).With().Timestamp().Logger()
 
 func TestNewPDFGenerator(t *testing.T) {
+	usLetterMetaData := _defaultMetaData
+	usLetterMetaData.PageSize = PageSize{Width: 215.9, Height: 279.4}
+
+	negativePageSizeMetaData := _defaultMetaData
+	negativePageSizeMetaData.PageSize = PageSize{Width: -1, Height: 279.4}
+
+	landscapeMetaData := _defaultMetaData
+	landscapeMetaData.Orientation = "L"
+
+	invalidOrientationMetaData := _defaultMetaData
+	invalidOrientationMetaData.Orientation = "X"
+
+	gridTableMetaData := _defaultMetaData
+	gridTableMetaData.TableStyle = DefaultTableStyle
+	gridTableMetaData.TableStyle.Border = "grid"
+
+	invalidTableBorderMetaData := _defaultMetaData
+	invalidTableBorderMetaData.TableStyle = DefaultTableStyle
+	invalidTableBorderMetaData.TableStyle.Border = "dotted"
+
 	type args struct {
 		data                MetaData
 		strictErrorHandling bool
@@ -29,6 +50,54 @@ func TestNewPDFGenerator(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "custom page size",
+			args: args{
+				data:                usLetterMetaData,
+				strictErrorHandling: false,
+			},
+			wantErr: false,
+		},
+		{
+			name: "negative page size",
+			args: args{
+				data:                negativePageSizeMetaData,
+				strictErrorHandling: false,
+			},
+			wantErr: true,
+		},
+		{
+			name: "landscape",
+			args: args{
+				data:                landscapeMetaData,
+				strictErrorHandling: false,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid orientation",
+			args: args{
+				data:                invalidOrientationMetaData,
+				strictErrorHandling: false,
+			},
+			wantErr: true,
+		},
+		{
+			name: "grid table border",
+			args: args{
+				data:                gridTableMetaData,
+				strictErrorHandling: false,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid table border",
+			args: args{
+				data:                invalidTableBorderMetaData,
+				strictErrorHandling: false,
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -43,39 +112,99 @@ func TestNewPDFGenerator(t *testing.T) {
 	}
 }
 
-func TestPDFGenerator_DrawLine(t *testing.T) {
-	type fields struct {
-		pdf                 *gofpdf.Fpdf
-		data                MetaData
-		maxSaveX            float64
-		maxSaveY            float64
-		strictErrorHandling bool
+func TestPDFGenerator_ComputeStringLength(t *testing.T) {
+	const text = "Hello World"
+
+	tests := []struct {
+		name          string
+		printFontSize float64
+		fontSize      float64
+	}{
+		{
+			name:     "default font size",
+			fontSize: 1,
+		},
+		{
+			name:     "larger font size",
+			fontSize: 10,
+		},
+		{
+			name:          "after printing with a larger font size",
+			printFontSize: 20,
+			fontSize:      10,
+		},
+		{
+			name:          "after printing with a smaller font size",
+			printFontSize: 5,
+			fontSize:      10,
+		},
 	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, err := NewPDFGenerator(_defaultMetaData, false, &_logger, func() {}, func(isLastPage bool) {})
+			if err != nil {
+				t.Errorf("init core error\n%s", err.Error())
+				return
+			}
+			// the length of the text with the default font size 1
+			defaultLength := core.ComputeStringLength(text)
+
+			core.NewPage()
+			if tt.printFontSize > 0 {
+				core.SetFontSize(tt.printFontSize)
+				core.PrintPdfText(text, "", "L")
+			}
+			core.SetFontSize(tt.fontSize)
+
+			if gotLength := core.ComputeStringLength(text); math.Abs(gotLength-tt.fontSize*defaultLength) > 1e-9 {
+				t.Errorf("ComputeStringLength() = %v, want %v", gotLength, tt.fontSize*defaultLength)
+			}
+		})
+	}
+}
+
+func TestPDFGenerator_DrawLine(t *testing.T) {
 	type args struct {
-		x1       float64
-		y1       float64
-		x2       float64
-		y2       float64
-		color    Color
-		lineWith float64
+		x1        float64
+		y1        float64
+		x2        float64
+		y2        float64
+		color     Color
+		lineWidth float64
 	}
 	tests := []struct {
-		name   string
-		fields fields
-		args   args
+		name    string
+		args    args
+		wantErr bool
 	}{
-		// TODO: Add test cases.
+		{name: "default width", args: args{x1: 10, y1: 20, x2: 100, y2: 20, color: Color{R: 239, G: 239, B: 239}, lineWidth: 0}, wantErr: false},
+		{name: "colored", args: args{x1: 10, y1: 20, x2: 100, y2: 40, color: Color{R: 255}, lineWidth: 1.5}, wantErr: false},
+		{name: "negative width", args: args{x1: 10, y1: 20, x2: 100, y2: 20, lineWidth: -1}, wantErr: true},
+		{name: "outside of the page", args: args{x1: 10, y1: 20, x2: 1000, y2: 20, lineWidth: 1}, wantErr: true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			core := &PDFGenerator{
-				pdf:                 tt.fields.pdf,
-				data:                tt.fields.data,
-				maxSaveX:            tt.fields.maxSaveX,
-				maxSaveY:            tt.fields.maxSaveY,
-				strictErrorHandling: tt.fields.strictErrorHandling,
+			core, err := NewPDFGenerator(_defaultMetaData, false, &_logger, func() {}, func(isLastPage bool) {})
+			if err != nil {
+				t.Fatalf("init core error\n%s", err.Error())
+			}
+			core.NewPage()
+
+			lineWidth := core.GetLineWidth()
+			lineColor := core.GetLineColor()
+
+			core.DrawLine(tt.args.x1, tt.args.y1, tt.args.x2, tt.args.y2, tt.args.color, tt.args.lineWidth)
+			if gotErr := core.pdf.Err(); gotErr != tt.wantErr {
+				t.Fatalf("DrawLine() set a error = %v, want %v", core.pdf.Error(), tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			// the color and the width are only used for the line
+			if core.GetLineWidth() != lineWidth || core.GetLineColor() != lineColor {
+				t.Errorf("the line style is not restored, got width %v and color %v", core.GetLineWidth(), core.GetLineColor())
 			}
-			core.DrawLine(tt.args.x1, tt.args.y1, tt.args.x2, tt.args.y2)
 		})
 	}
 }
@@ -455,6 +584,54 @@ func TestPDFGenerator_PrintTableBody(t *testing.T) {
 	}
 }
 
+func TestPDFGenerator_PrintTableBody_tableStyle(t *testing.T) {
+	tests := []struct {
+		name       string
+		tableStyle TableStyle
+	}{
+		{
+			name:       "default style",
+			tableStyle: TableStyle{},
+		},
+		{
+			name:       "striped grid",
+			tableStyle: TableStyle{HeaderFillColor: Color{R: 220, G: 230, B: 242}, StripeFillColor: Color{R: 243, G: 246, B: 250}, Striped: true, Border: "grid", BorderColor: Color{R: 26, G: 77, B: 143}},
+		},
+		{
+			name:       "no borders",
+			tableStyle: TableStyle{Border: "none"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := _defaultMetaData
+			data.TableStyle = tt.tableStyle
+
+			core, err := NewPDFGenerator(data, false, &_logger, func() {}, func(isLastPage bool) {})
+			if err != nil {
+				t.Errorf("init core error\n%s", err.Error())
+				return
+			}
+			core.NewPage()
+
+			columnWidths := []float64{20, 40}
+			columnAlignStrings := []string{"LM", "RM"}
+			core.PrintTableHeader([]string{"Pos", "Price"}, columnWidths, columnAlignStrings)
+			core.PrintTableBody([][]string{{"1", "1,00€"}, {"2", "2,00\n3,00€"}, {"3", "4,00€"}}, columnWidths, columnAlignStrings)
+			core.PrintTableFooter([][]string{{"Sum", "10,00€"}}, columnWidths, columnAlignStrings)
+
+			if core.pdf.Err() {
+				t.Errorf("print table error\n%s", core.pdf.Error())
+			}
+
+			// the border color is only used for the table
+			if r, g, b := core.pdf.GetDrawColor(); r != int(data.DefaultLineColor.R) || g != int(data.DefaultLineColor.G) || b != int(data.DefaultLineColor.B) {
+				t.Errorf("draw color after the table = (%d, %d, %d), want %v", r, g, b, data.DefaultLineColor)
+			}
+		})
+	}
+}
+
 func TestPDFGenerator_PrintTableFooter(t *testing.T) {
 	type fields struct {
 		pdf                 *gofpdf.Fpdf
@@ -489,6 +666,67 @@ func TestPDFGenerator_PrintTableFooter(t *testing.T) {
 	}
 }
 
+func TestPDFGenerator_PrintTableFooter_cells(t *testing.T) {
+	tests := []struct {
+		name    string
+		cells   [][]string
+		wantErr bool
+	}{
+		{
+			name:    "one cell per column",
+			cells:   [][]string{{"Sum", "10,00€"}},
+			wantErr: false,
+		},
+		{
+			name:    "less cells",
+			cells:   [][]string{{}, {"Sum"}},
+			wantErr: false,
+		},
+		{
+			name:    "more cells",
+			cells:   [][]string{{"Sum", "10,00€"}, {"x", "y", "z"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columnWidths := []float64{20, 40}
+			columnAlignStrings := []string{"LM", "RM"}
+
+			for _, method := range []string{"PrintTableBody", "PrintTableFooter"} {
+				core, err := NewPDFGenerator(_defaultMetaData, false, &_logger, func() {}, func(isLastPage bool) {})
+				if err != nil {
+					t.Fatalf("init core error\n%s", err.Error())
+				}
+				core.NewPage()
+
+				if method == "PrintTableBody" {
+					core.PrintTableBody(tt.cells, columnWidths, columnAlignStrings)
+				} else {
+					core.PrintTableFooter(tt.cells, columnWidths, columnAlignStrings)
+				}
+				if core.pdf.Err() != tt.wantErr {
+					t.Errorf("%s() set a error = %v, want %v", method, core.pdf.Error(), tt.wantErr)
+				}
+
+				rec, err := NewRecordingGenerator(_defaultMetaData, false, &_logger, func() {}, func(isLastPage bool) {})
+				if err != nil {
+					t.Fatalf("init recorder error\n%s", err.Error())
+				}
+				rec.NewPage()
+				if method == "PrintTableBody" {
+					rec.PrintTableBody(tt.cells, columnWidths, columnAlignStrings)
+				} else {
+					rec.PrintTableFooter(tt.cells, columnWidths, columnAlignStrings)
+				}
+				if gotErr := rec.GetError() != nil; gotErr != tt.wantErr {
+					t.Errorf("%s() of the recorder set a error = %v, want %v", method, rec.GetError(), tt.wantErr)
+				}
+			}
+		})
+	}
+}
+
 func TestPDFGenerator_PrintTableHeader(t *testing.T) {
 	type fields struct {
 		pdf                 *gofpdf.Fpdf
@@ -616,6 +854,7 @@ func TestPDFGenerator_printTableBodyRow(t *testing.T) {
 		extractedLines [][]string
 		currentLine    int
 		maxItems       int
+		stripe         bool
 		alignStrings   []string
 		newlineHeight  float64
 		columnWidth    []float64
@@ -637,7 +876,7 @@ func TestPDFGenerator_printTableBodyRow(t *testing.T) {
 				maxSaveY:            tt.fields.maxSaveY,
 				strictErrorHandling: tt.fields.strictErrorHandling,
 			}
-			core.printTableBodyRow(tt.args.extractedLines, tt.args.currentLine, tt.args.maxItems, tt.args.alignStrings, tt.args.newlineHeight, tt.args.columnWidth, tt.args.referenceX)
+			core.printTableBodyRow(tt.args.extractedLines, tt.args.currentLine, tt.args.maxItems, tt.args.stripe, tt.args.alignStrings, tt.args.newlineHeight, tt.args.columnWidth, tt.args.referenceX)
 		})
 	}
 }
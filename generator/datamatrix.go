@@ -0,0 +1,316 @@
+package generator
+
+import (
+	"fmt"
+	errorsWithStack "github.com/go-errors/errors"
+)
+
+// dataMatrixSymbol is a square ECC 200 symbol size with regions x regions data regions.
+// The codewords are interleaved into blocks, each block has eccCodewords/blocks error correction codewords.
+type dataMatrixSymbol struct {
+	size          int
+	dataCodewords int
+	eccCodewords  int
+	regions       int
+	blocks        int
+}
+
+// dataMatrixSymbols are the square ECC 200 symbol sizes.
+var dataMatrixSymbols = []dataMatrixSymbol{
+	{size: 10, dataCodewords: 3, eccCodewords: 5, regions: 1, blocks: 1},
+	{size: 12, dataCodewords: 5, eccCodewords: 7, regions: 1, blocks: 1},
+	{size: 14, dataCodewords: 8, eccCodewords: 10, regions: 1, blocks: 1},
+	{size: 16, dataCodewords: 12, eccCodewords: 12, regions: 1, blocks: 1},
+	{size: 18, dataCodewords: 18, eccCodewords: 14, regions: 1, blocks: 1},
+	{size: 20, dataCodewords: 22, eccCodewords: 18, regions: 1, blocks: 1},
+	{size: 22, dataCodewords: 30, eccCodewords: 20, regions: 1, blocks: 1},
+	{size: 24, dataCodewords: 36, eccCodewords: 24, regions: 1, blocks: 1},
+	{size: 26, dataCodewords: 44, eccCodewords: 28, regions: 1, blocks: 1},
+	{size: 32, dataCodewords: 62, eccCodewords: 36, regions: 2, blocks: 1},
+	{size: 36, dataCodewords: 86, eccCodewords: 42, regions: 2, blocks: 1},
+	{size: 40, dataCodewords: 114, eccCodewords: 48, regions: 2, blocks: 1},
+	{size: 44, dataCodewords: 144, eccCodewords: 56, regions: 2, blocks: 1},
+	{size: 48, dataCodewords: 174, eccCodewords: 68, regions: 2, blocks: 1},
+	{size: 52, dataCodewords: 204, eccCodewords: 84, regions: 2, blocks: 2},
+	{size: 64, dataCodewords: 280, eccCodewords: 112, regions: 4, blocks: 2},
+	{size: 72, dataCodewords: 368, eccCodewords: 144, regions: 4, blocks: 4},
+	{size: 80, dataCodewords: 456, eccCodewords: 192, regions: 4, blocks: 4},
+	{size: 88, dataCodewords: 576, eccCodewords: 224, regions: 4, blocks: 4},
+	{size: 96, dataCodewords: 696, eccCodewords: 272, regions: 4, blocks: 4},
+	{size: 104, dataCodewords: 816, eccCodewords: 336, regions: 4, blocks: 6},
+	{size: 120, dataCodewords: 1050, eccCodewords: 408, regions: 6, blocks: 6},
+	{size: 132, dataCodewords: 1304, eccCodewords: 496, regions: 6, blocks: 8},
+	{size: 144, dataCodewords: 1558, eccCodewords: 620, regions: 6, blocks: 10},
+}
+
+// encodeDataMatrix returns the modules of the smallest square ECC 200 symbol holding content, without the quiet zone.
+// content is encoded in the ASCII mode, characters up to U+00FF are encoded as ISO 8859-1.
+func encodeDataMatrix(content string) (modules [][]bool, err error) {
+	if content == "" {
+		return nil, errorsWithStack.New(fmt.Sprintf("the content of a DataMatrix code must not be empty"))
+	}
+
+	data, err := dataMatrixASCII(content)
+	if err != nil {
+		return nil, err
+	}
+
+	var symbol *dataMatrixSymbol
+	for i := range dataMatrixSymbols {
+		if dataMatrixSymbols[i].dataCodewords >= len(data) {
+			symbol = &dataMatrixSymbols[i]
+			break
+		}
+	}
+	if symbol == nil {
+		return nil, errorsWithStack.New(fmt.Sprintf("the content of %d codewords is too long for a DataMatrix code", len(data)))
+	}
+
+	// the first pad codeword is 129, the following are randomized by their position
+	if len(data) < symbol.dataCodewords {
+		data = append(data, 129)
+	}
+	for len(data) < symbol.dataCodewords {
+		pad := 129 + (149*(len(data)+1))%253 + 1
+		if pad > 254 {
+			pad -= 254
+		}
+		data = append(data, byte(pad))
+	}
+
+	return symbol.modules(append(data, symbol.errorCorrection(data)...)), nil
+}
+
+// dataMatrixASCII encodes content in the ASCII mode, two digits are encoded in one codeword.
+func dataMatrixASCII(content string) (data []byte, err error) {
+	runes := []rune(content)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case isDigit(r) && i+1 < len(runes) && isDigit(runes[i+1]):
+			data = append(data, byte(130+(r-'0')*10+runes[i+1]-'0'))
+			i++
+		case r < 128:
+			data = append(data, byte(r+1))
+		case r < 256:
+			// upper shift to the extended ASCII characters
+			data = append(data, 235, byte(r-127))
+		default:
+			return nil, errorsWithStack.New(fmt.Sprintf("the character \"%c\" can't be encoded in a DataMatrix code", r))
+		}
+	}
+	return data, nil
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+// errorCorrection returns the Reed-Solomon codewords of data, interleaved like the data codewords.
+func (symbol dataMatrixSymbol) errorCorrection(data []byte) []byte {
+	eccPerBlock := symbol.eccCodewords / symbol.blocks
+	generator := reedSolomonGenerator(eccPerBlock)
+
+	ecc := make([]byte, symbol.eccCodewords)
+	for block := 0; block < symbol.blocks; block++ {
+		var blockData []byte
+		for i := block; i < len(data); i += symbol.blocks {
+			blockData = append(blockData, data[i])
+		}
+
+		for i, codeword := range reedSolomonRemainder(blockData, generator) {
+			ecc[i*symbol.blocks+block] = codeword
+		}
+	}
+	return ecc
+}
+
+// the logarithm and exponent tables of GF(256) with the prime polynomial 0x12D
+var gfLog, gfExp = func() (log [256]int, exp [255]int) {
+	value := 1
+	for i := range exp {
+		exp[i] = value
+		log[value] = i
+		value <<= 1
+		if value >= 256 {
+			value ^= 0x12D
+		}
+	}
+	return log, exp
+}()
+
+func gfMultiply(a int, b int) int {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExp[(gfLog[a]+gfLog[b])%255]
+}
+
+// reedSolomonGenerator returns the coefficients of the generator polynomial (x - 2^1) ... (x - 2^n),
+// starting with the highest degree.
+func reedSolomonGenerator(n int) []int {
+	polynomial := []int{1}
+	for i := 1; i <= n; i++ {
+		next := make([]int, len(polynomial)+1)
+		for j, coefficient := range polynomial {
+			next[j] ^= coefficient
+			next[j+1] ^= gfMultiply(coefficient, gfExp[i])
+		}
+		polynomial = next
+	}
+	return polynomial
+}
+
+// reedSolomonRemainder returns the remainder of data divided by the generator polynomial.
+func reedSolomonRemainder(data []byte, generator []int) []byte {
+	remainder := make([]int, len(generator)-1)
+	for _, codeword := range data {
+		factor := int(codeword) ^ remainder[0]
+		copy(remainder, remainder[1:])
+		remainder[len(remainder)-1] = 0
+		for i := range remainder {
+			remainder[i] ^= gfMultiply(generator[i+1], factor)
+		}
+	}
+
+	ecc := make([]byte, len(remainder))
+	for i, value := range remainder {
+		ecc[i] = byte(value)
+	}
+	return ecc
+}
+
+// modules places the codewords into the data regions and adds the finder patterns around each region.
+func (symbol dataMatrixSymbol) modules(codewords []byte) [][]bool {
+	regionSize := symbol.size/symbol.regions - 2
+	mapping := dataMatrixPlacement(symbol.regions*regionSize, codewords)
+
+	modules := make([][]bool, symbol.size)
+	for row := range modules {
+		modules[row] = make([]bool, symbol.size)
+		regionRow, r := row/(regionSize+2), row%(regionSize+2)
+		for col := range modules[row] {
+			regionCol, c := col/(regionSize+2), col%(regionSize+2)
+			switch {
+			case c == 0 || r == regionSize+1:
+				// the solid L of the finder pattern on the left and the bottom
+				modules[row][col] = true
+			case r == 0:
+				// the alternating clock track on the top
+				modules[row][col] = c%2 == 0
+			case c == regionSize+1:
+				// the alternating clock track on the right
+				modules[row][col] = r%2 == 1
+			default:
+				modules[row][col] = mapping[regionRow*regionSize+r-1][regionCol*regionSize+c-1]
+			}
+		}
+	}
+	return modules
+}
+
+// dataMatrixPlacement places the bits of the codewords into a size x size matrix
+// in the diagonal "utah" shapes of the ECC 200 placement algorithm.
+func dataMatrixPlacement(size int, codewords []byte) [][]bool {
+	// each module holds the codeword index * 8 + the bit number (1 is the most significant bit),
+	// or -1 for a dark and -2 for a light module of the unused corner
+	positions := make([]int, size*size)
+
+	module := func(row int, col int, codeword int, bit int) {
+		if row < 0 {
+			row += size
+			col += 4 - (size+4)%8
+		}
+		if col < 0 {
+			col += size
+			row += 4 - (size+4)%8
+		}
+		positions[row*size+col] = (codeword-1)*8 + bit
+	}
+	utah := func(row int, col int, codeword int) {
+		module(row-2, col-2, codeword, 1)
+		module(row-2, col-1, codeword, 2)
+		module(row-1, col-2, codeword, 3)
+		module(row-1, col-1, codeword, 4)
+		module(row-1, col, codeword, 5)
+		module(row, col-2, codeword, 6)
+		module(row, col-1, codeword, 7)
+		module(row, col, codeword, 8)
+	}
+	corner := func(codeword int, cells [8][2]int) {
+		for i, cell := range cells {
+			module(cell[0], cell[1], codeword, i+1)
+		}
+	}
+
+	n := size - 1
+	codeword := 1
+	row, col := 4, 0
+	for row < size || col < size {
+		switch {
+		case row == size && col == 0:
+			corner(codeword, [8][2]int{{n, 0}, {n, 1}, {n, 2}, {0, n - 1}, {0, n}, {1, n}, {2, n}, {3, n}})
+			codeword++
+		case row == size-2 && col == 0 && size%4 != 0:
+			corner(codeword, [8][2]int{{n - 2, 0}, {n - 1, 0}, {n, 0}, {0, n - 3}, {0, n - 2}, {0, n - 1}, {0, n}, {1, n}})
+			codeword++
+		case row == size-2 && col == 0 && size%8 == 4:
+			corner(codeword, [8][2]int{{n - 2, 0}, {n - 1, 0}, {n, 0}, {0, n - 1}, {0, n}, {1, n}, {2, n}, {3, n}})
+			codeword++
+		case row == size+4 && col == 2 && size%8 == 0:
+			corner(codeword, [8][2]int{{n, 0}, {n, n}, {0, n - 2}, {0, n - 1}, {0, n}, {1, n - 2}, {1, n - 1}, {1, n}})
+			codeword++
+		}
+
+		// sweep upwards to the right
+		for {
+			if row < size && col >= 0 && positions[row*size+col] == 0 {
+				utah(row, col, codeword)
+				codeword++
+			}
+			row -= 2
+			col += 2
+			if row < 0 || col >= size {
+				break
+			}
+		}
+		row++
+		col += 3
+
+		// sweep downwards to the left
+		for {
+			if row >= 0 && col < size && positions[row*size+col] == 0 {
+				utah(row, col, codeword)
+				codeword++
+			}
+			row += 2
+			col -= 2
+			if row >= size || col < 0 {
+				break
+			}
+		}
+		row += 3
+		col++
+	}
+
+	// the unused bottom right corner of some sizes has a fixed pattern
+	if positions[size*size-1] == 0 {
+		positions[size*size-1] = -1
+		positions[size*size-size-2] = -1
+	}
+
+	matrix := make([][]bool, size)
+	for r := range matrix {
+		matrix[r] = make([]bool, size)
+		for c := range matrix[r] {
+			position := positions[r*size+c]
+			if position <= 0 {
+				matrix[r][c] = position == -1
+				continue
+			}
+			value, bit := codewords[(position-1)/8], (position-1)%8+1
+			matrix[r][c] = value>>(8-bit)&1 == 1
+		}
+	}
+	return matrix
+}
@@ -2,7 +2,9 @@ package generator
 
 import (
 	"github.com/jung-kurt/gofpdf"
+	"github.com/jung-kurt/gofpdf/contrib/gofpdi"
 	"github.com/rs/zerolog"
+	"io"
 	"net/url"
 )
 
@@ -16,6 +18,16 @@ type PDFGenerator struct {
 	strictErrorHandling  bool
 	logger               *zerolog.Logger
 	registeredImageTypes map[string]string
+	registeredSvgImages  map[string]*svgImage
+	display              *displayList
+	transform            *displayTransform
+	pdfImporter          *gofpdi.Importer
+	pdfSources           []*io.ReadSeeker
+	registeredPdfPages   map[string]int
+	fonts                *FontRegistry
+	fontFamily           *fontFamily
+	fallbackFontFamilies []*fontFamily
+	addedFontStyles      map[string]bool
 }
 
 // MetaData sums all necessary inputs for NewPDFGenerator().
@@ -28,6 +40,16 @@ type PDFGenerator struct {
 //	"Symbol" or "ZapfDingbats" for symbolic.
 //	"OpenSans" for TrueType support with utf-8 symbols.
 //
+// Further TrueType font families can be used, if they are registered in the FontRegistry.
+//
+// FontRegistry provides the TrueType font families. If it is nil, DefaultFontRegistry is used.
+//
+// FallbackFontNames are registered font families, which print the characters missing in the font FontName
+// (e.g. CJK characters or symbols). The first fallback font containing a character is used.
+// Fallback fonts require a registered font family as FontName.
+//
+// ImageCache keeps the images downloaded with RegisterMimeImageToPdf. If it is nil, DefaultImageCache is used.
+//
 // FontGapY defines the gap between two text lines in the Unit of measure.
 //
 // FontSize defines the font size measured in points.
@@ -49,52 +71,134 @@ type PDFGenerator struct {
 //	"mm" for millimeter,
 //	"cm" for centimeter, or
 //	"in" for inch.
+//
+// DefaultTextColor defines the color of all printed texts, see SetTextColor. The zero value is black.
+//
+// TableStyle defines the look of the tables, see TableStyle. The zero value is replaced with DefaultTableStyle
+// and the DefaultLineColor as border color.
+//
+// PageSize defines the width and height of the pages in the Unit of measure in portrait orientation.
+// The zero value is a DIN A4 page.
+//
+// Orientation specifies the page orientation. An empty string will be replaced with "P". Specify
+//
+//	"P" for portrait or
+//	"L" for landscape, the width and height of PageSize are swapped.
 type MetaData struct {
-	FontName         string
-	FontGapY         float64
-	FontSize         float64
-	MarginLeft       float64
-	MarginTop        float64
-	MarginRight      float64
-	MarginBottom     float64
-	Unit             string
-	DefaultLineWidth float64
-	DefaultLineColor Color
+	FontName          string
+	FontRegistry      *FontRegistry
+	FallbackFontNames []string
+	ImageCache        *ImageCache
+	FontGapY          float64
+	FontSize          float64
+	MarginLeft        float64
+	MarginTop         float64
+	MarginRight       float64
+	MarginBottom      float64
+	Unit              string
+	DefaultLineWidth  float64
+	DefaultLineColor  Color
+	DefaultTextColor  Color
+	TableStyle        TableStyle
+	PageSize          PageSize
+	Orientation       string
+}
+
+// TableStyle defines the look of the tables printed with PrintTableHeader(), PrintTableBody() and PrintTableFooter().
+//
+// HeaderFillColor is the background of the table header, FooterFillColor the background of the last footer row
+// (e.g. the total sum of an invoice).
+//
+// If Striped is true, every second body row is filled with StripeFillColor.
+//
+// Border specifies the drawn borders in BorderColor:
+//
+//	"horizontal" or "" for lines above and below the header, below each body row and around the last footer row,
+//	"grid" for a border around each cell, or
+//	"none" for no borders.
+type TableStyle struct {
+	HeaderFillColor Color
+	FooterFillColor Color
+	StripeFillColor Color
+	Striped         bool
+	Border          string
+	BorderColor     Color
+}
+
+// DefaultTableStyle is the clean light gray table style used, if MetaData.TableStyle is not set.
+var DefaultTableStyle = TableStyle{
+	HeaderFillColor: Color{R: 239, G: 239, B: 239},
+	FooterFillColor: Color{R: 239, G: 239, B: 239},
+	StripeFillColor: Color{R: 248, G: 248, B: 248},
+	Border:          "horizontal",
+	BorderColor:     Color{R: 162, G: 162, B: 162},
+}
+
+// PageSize is the width and height of a page in the Unit of measure.
+type PageSize struct {
+	Width  float64
+	Height float64
 }
 
 // Generator specify all public methods.
+//
+// PDFGenerator is the default implementation, RecordingGenerator records all draw operations
+// (e.g. to test a layout without parsing a PDF).
+// All document types and letter norms depend only on this interface.
 type Generator interface {
 	PrintPdfText(text string, styleStr string, alignStr string)
 	PrintLnPdfText(text string, styleStr string, alignStr string)
-	DrawLine(x1 float64, y1 float64, x2 float64, y2 float64, color Color, lineWith float64)
+	DrawLine(x1 float64, y1 float64, x2 float64, y2 float64, color Color, lineWidth float64)
+	DrawStyledLine(x1 float64, y1 float64, x2 float64, y2 float64, style LineStyle)
+	DrawRect(x float64, y float64, w float64, h float64, radius float64, style ShapeStyle)
+	DrawEllipse(x float64, y float64, rx float64, ry float64, style ShapeStyle)
+	DrawCircle(x float64, y float64, r float64, style ShapeStyle)
+	DrawPolygon(points []Point, style ShapeStyle)
+	DrawBarcode(kind string, content string, x float64, y float64, w float64, h float64, humanReadable bool)
+	DrawWatermark(mark Watermark)
 	PrintPdfTextFormatted(text string, styleStr string, alignStr string, borderStr string, fill bool, backgroundColor Color, cellHeight float64, cellWidth float64)
 	NewLine(oldX float64)
 	PreviousLine(oldX float64)
 
 	RegisterMimeImageToPdf(cdnUrl *url.URL) (imageNameStr string)
+	RegisterImage(imageNameStr string, imageData []byte)
 	PlaceRegisteredImageOnPage(imageNameStr string, alignStr string, scale float64)
 	GetRegisteredImageExtent(imageNameStr string) (w float64, h float64)
 	ImageIsRegistered(imageNameStr string) bool
 
+	RegisterPdfPage(pageNameStr string, pdfData []byte, pageNumber int)
+	PlaceRegisteredPdfPage(pageNameStr string)
+	PdfPageIsRegistered(pageNameStr string) bool
+
 	PrintTableHeader(cells []string, columnWidth []float64, columnAlignStrings []string)
 	PrintTableBody(cells [][]string, columnWidths []float64, columnAlignStrings []string)
 	PrintTableFooter(cells [][]string, columnWidths []float64, columnAlignStrings []string)
 
-	GetPdf() *gofpdf.Fpdf
+	RenderBoxes(frame Frame, boxes []Box)
+	MeasureBoxes(boxes []Box, width float64) (height float64)
+
+	Output(w io.Writer) error
 	GetError() error
 	SetError(err error)
 	ComputeStringLength(str string) (length float64)
+	SplitText(text string, styleStr string, fontSize float64, width float64) (lines []string)
+	GetLineHeight(fontSize float64, fontGapY float64) float64
 
 	GetFontName() string
 	GetMarginLeft() float64
 	GetMarginTop() float64
 	GetMarginRight() float64
 	GetMarginBottom() float64
+	GetPageSize() (width float64, height float64)
 
 	GetFontGapY() float64
 	SetFontGapY(fontGapY float64)
 	GetFontSize() float64
 	SetFontSize(textSize float64)
+	GetTextColor() Color
+	GetLineColor() Color
+	GetLineWidth() float64
+	SetTextColor(color Color)
 	GetCursor() (x float64, y float64)
 	SetCursor(x float64, y float64)
 	SetUnsafeCursor(x float64, y float64)
@@ -105,6 +209,21 @@ type Generator interface {
 	GoToPage(pageNumber int)
 }
 
+// NewGeneratorFunc constructs a Generator backend. The parameters are the same as in NewPDFGenerator().
+type NewGeneratorFunc func(data MetaData, strictErrorHandling bool, logger *zerolog.Logger, headerFunction func(), footerFunction func(isLastPage bool)) (Generator, error)
+
+// NewPDFGeneratorBackend is a NewGeneratorFunc, which returns a PDFGenerator.
+func NewPDFGeneratorBackend(data MetaData, strictErrorHandling bool, logger *zerolog.Logger, headerFunction func(), footerFunction func(isLastPage bool)) (Generator, error) {
+	gen, err := NewPDFGenerator(data, strictErrorHandling, logger, headerFunction, footerFunction)
+	if err != nil {
+		return nil, err
+	}
+	return gen, nil
+}
+
+var _ Generator = (*PDFGenerator)(nil)
+var _ Generator = (*RecordingGenerator)(nil)
+
 // Color represents a specific color in red, green and blue values, each from 0 to 255
 type Color struct {
 	R uint8
@@ -0,0 +1,207 @@
+package generator
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// displayItem kinds
+const (
+	displayLine = iota
+	displayRect
+	displayText
+	displayImage
+	displayShape
+)
+
+// displayItem is one drawn element of a page. All values are measured in the unit of measure specified in NewPDFGenerator().
+//
+// For displayLine X/Y are the start and X2/Y2 the end point,
+// for displayRect and displayImage X/Y are the top left corner and W/H the size,
+// for displayText X/Y are the start of the baseline,
+// for displayShape Points are the corners of the outline, which is closed if Closed is true.
+// Dash is the dash style of the line, see LineStyle.
+// Transform is the rotation and the opacity of a watermark (see DrawWatermark), nil for all other elements.
+type displayItem struct {
+	kind      int
+	x         float64
+	y         float64
+	x2        float64
+	y2        float64
+	w         float64
+	h         float64
+	fill      bool
+	stroke    bool
+	fillColor Color
+	lineColor Color
+	lineWidth float64
+	dash      string
+	points    []Point
+	closed    bool
+	textColor Color
+	text      string
+	fontName  string
+	styleStr  string
+	fontSize  float64
+	imageName string
+	transform *displayTransform
+}
+
+// displayImageData contains the raw data of a registered image and the parsed svg image.
+type displayImageData struct {
+	data      []byte
+	imageType string
+	svg       *svgImage
+}
+
+// displayList collects all drawing operations of a PDFGenerator per page,
+// to render the document in other output formats than PDF.
+type displayList struct {
+	pages  map[int][]displayItem
+	images map[string]displayImageData
+}
+
+func newDisplayList() *displayList {
+	return &displayList{
+		pages:  map[int][]displayItem{},
+		images: map[string]displayImageData{},
+	}
+}
+
+// record adds a drawn element to the current page.
+func (core *PDFGenerator) record(item displayItem) {
+	if core.display == nil {
+		return
+	}
+
+	item.transform = core.transform
+	page := core.pdf.PageNo()
+	core.display.pages[page] = append(core.display.pages[page], item)
+}
+
+// recordCell records a text cell printed with CellFormat().
+// It must be called after CellFormat() with ln = 0, because a page break may occur inside CellFormat().
+func (core *PDFGenerator) recordCell(text string, styleStr string, alignStr string, borderStr string, fill bool, backgroundColor Color, cellHeight float64, cellWidth float64) {
+	if core.display == nil || core.pdf.Err() {
+		return
+	}
+
+	x, y := core.pdf.GetXY()
+	x -= cellWidth
+	r, g, b := core.pdf.GetDrawColor()
+	lineColor := Color{R: uint8(r), G: uint8(g), B: uint8(b)}
+	lineWidth := core.pdf.GetLineWidth()
+	borderStr = strings.ToUpper(borderStr)
+
+	if fill || borderStr == "1" {
+		core.record(displayItem{kind: displayRect, x: x, y: y, w: cellWidth, h: cellHeight, fill: fill, fillColor: backgroundColor, stroke: borderStr == "1", lineColor: lineColor, lineWidth: lineWidth})
+	}
+
+	if borderStr != "1" {
+		borders := map[string][4]float64{
+			"L": {x, y, x, y + cellHeight},
+			"T": {x, y, x + cellWidth, y},
+			"R": {x + cellWidth, y, x + cellWidth, y + cellHeight},
+			"B": {x, y + cellHeight, x + cellWidth, y + cellHeight},
+		}
+		for _, side := range []string{"L", "T", "R", "B"} {
+			if strings.Contains(borderStr, side) {
+				p := borders[side]
+				core.record(displayItem{kind: displayLine, x: p[0], y: p[1], x2: p[2], y2: p[3], stroke: true, lineColor: lineColor, lineWidth: lineWidth})
+			}
+		}
+	}
+
+	if text == "" {
+		return
+	}
+
+	_, fontSize := core.pdf.GetFontSize()
+	textWidth := core.textWidth(text, styleStr)
+	dx, dy := core.cellTextOffset(textWidth, alignStr, cellWidth, cellHeight)
+
+	runs := core.textRuns(text)
+	if runs == nil {
+		runs = []textRun{{text: text}}
+	}
+
+	tr, tg, tb := core.pdf.GetTextColor()
+	textColor := Color{R: uint8(tr), G: uint8(tg), B: uint8(tb)}
+	baseline := y + dy + .5*cellHeight + .3*fontSize
+	startX := x + dx
+	for _, run := range runs {
+		fontName := core.data.FontName
+		runWidth := textWidth
+		if run.family != nil {
+			fontName = run.family.name
+			runWidth = 0
+			for _, r := range run.text {
+				runWidth += core.runeWidth(run.family, styleStr, r)
+			}
+		}
+
+		core.record(displayItem{
+			kind:      displayText,
+			textColor: textColor,
+			x:         x + dx,
+			y:         baseline,
+			w:         runWidth,
+			text:      run.text,
+			fontName:  fontName,
+			styleStr:  styleStr,
+			fontSize:  fontSize,
+		})
+		dx += runWidth
+	}
+
+	// the underline and strike-through are drawn as lines, like gofpdf does
+	style, _ := ParseFontStyle(styleStr)
+	var lineYs []float64
+	if style.Underline {
+		lineYs = append(lineYs, baseline+.1*fontSize)
+	}
+	if style.StrikeThrough {
+		lineYs = append(lineYs, baseline-.4*fontSize)
+	}
+	for _, lineY := range lineYs {
+		core.record(displayItem{kind: displayLine, x: startX, y: lineY, x2: x + dx, y2: lineY, stroke: true, lineColor: textColor, lineWidth: .05 * fontSize})
+	}
+}
+
+// recordLine records a line drawn in color. lineWidth is the resolved width of the line, see LineStyle.
+func (core *PDFGenerator) recordLine(x1 float64, y1 float64, x2 float64, y2 float64, color Color, lineWidth float64) {
+	if core.display == nil || core.pdf.Err() {
+		return
+	}
+
+	core.record(displayItem{kind: displayLine, x: x1, y: y1, x2: x2, y2: y2, stroke: true, lineColor: color, lineWidth: lineWidth})
+}
+
+// recordShape records a shape drawn with style. Curves are approximated by the points of the outline.
+// lineWidth is the resolved width of the outline, see LineStyle.
+func (core *PDFGenerator) recordShape(points []Point, closed bool, style ShapeStyle, lineWidth float64) {
+	if core.display == nil || core.pdf.Err() {
+		return
+	}
+
+	core.record(displayItem{kind: displayShape, points: points, closed: closed, fill: style.Fill, fillColor: style.FillColor,
+		stroke: style.Stroke, lineColor: style.Line.Color, lineWidth: lineWidth, dash: style.Line.Dash})
+}
+
+// registerImageReader registers an image in the PDF.
+// If a display list is used, the raw image data will be kept to render the image in other output formats.
+func (core *PDFGenerator) registerImageReader(imageNameStr string, imageType string, r io.Reader) {
+	if core.display != nil {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			core.pdf.SetError(err)
+			return
+		}
+		core.display.images[imageNameStr] = displayImageData{data: data, imageType: imageType}
+		r = bytes.NewReader(data)
+	}
+
+	core.pdf.RegisterImageReader(imageNameStr, imageType, r)
+	core.registeredImageTypes[imageNameStr] = imageType
+}
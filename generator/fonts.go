@@ -0,0 +1,522 @@
+package generator
+
+import (
+	"SimpleInvoice/fonts"
+	"fmt"
+	errorsWithStack "github.com/go-errors/errors"
+	"io/fs"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// FontDir is a server directory with further font families of DefaultFontRegistry, see FontRegistry.RegisterDir.
+// The bundled fonts (see package fonts) are always registered.
+var FontDir = ""
+
+// DefaultFontRegistry holds the font families of the server.
+// It is filled with the bundled fonts and the families of FontDir on the first use
+// and used, if MetaData.FontRegistry is not set.
+// Up to 20 further families with 100 MB of font files can be registered.
+var DefaultFontRegistry = &FontRegistry{
+	MaxFamilies: 20,
+	MaxBytes:    100 << 20,
+	families:    map[string]*fontFamily{},
+	load: func(registry *FontRegistry) error {
+		if err := registry.registerFS(fonts.Files); err != nil {
+			return err
+		}
+		if FontDir == "" {
+			return nil
+		}
+		return registry.registerFS(os.DirFS(FontDir))
+	},
+}
+
+// FontStyles maps the file name suffix of a font file to the face style (weight and italic, see ParseFontStyle),
+// e.g. "OpenSans-SemiBoldItalic.ttf" is the face "di".
+var FontStyles = map[string]string{
+	"Light":           "l",
+	"Regular":         "",
+	"Medium":          "m",
+	"SemiBold":        "d",
+	"Bold":            "b",
+	"ExtraBold":       "e",
+	"LightItalic":     "li",
+	"Italic":          "i",
+	"MediumItalic":    "mi",
+	"SemiBoldItalic":  "di",
+	"BoldItalic":      "bi",
+	"ExtraBoldItalic": "ei",
+}
+
+// fontFamilyNamePattern allows names, which are usable as file name and as PDF font name.
+var fontFamilyNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// coreFontNames are the standard PDF fonts, which are always available and can not be registered.
+var coreFontNames = map[string]bool{"courier": true, "helvetica": true, "arial": true, "times": true, "symbol": true, "zapfdingbats": true}
+
+// FontFamily is a named font family with the TrueType font data of its styles.
+//
+// Styles maps the face style (e.g. "", "b" or "bi", see FontStyles) to the font file data.
+// The regular style "" is required, missing faces are replaced by the nearest registered face.
+// OpenType fonts are supported, if they contain TrueType outlines (no CFF outlines).
+type FontFamily struct {
+	Name   string
+	Styles map[string][]byte
+}
+
+// fontFamily is a registered FontFamily with the parsed fonts of each style.
+type fontFamily struct {
+	name  string
+	files map[string][]byte
+	fonts map[string]*trueTypeFont
+}
+
+// FontRegistry holds the font families, which can be used as MetaData.FontName and MetaData.FallbackFontNames.
+//
+// A registry with a parent registry adds families to the parent,
+// e.g. the fonts uploaded with one request to the fonts of the server.
+//
+// MaxFamilies and MaxBytes limit the number and the file size of the families added with Register,
+// the families of the font files (see RegisterDir) are not counted. 0 is no limit.
+//
+// Change the settings before the first use. All methods are safe for concurrent use.
+type FontRegistry struct {
+	MaxFamilies int
+	MaxBytes    int
+
+	mutex         sync.RWMutex
+	parent        *FontRegistry
+	families      map[string]*fontFamily
+	addedFamilies int
+	addedBytes    int
+	load          func(registry *FontRegistry) error
+	loadOnce      sync.Once
+	loadErr       error
+}
+
+// NewFontRegistry returns an empty registry. parent may be nil.
+func NewFontRegistry(parent *FontRegistry) *FontRegistry {
+	return &FontRegistry{parent: parent, families: map[string]*fontFamily{}}
+}
+
+// Register adds a font family. A registered family is never replaced, so the name must neither be registered
+// in the registry nor in its parent, e.g. the bundled OpenSans can not be replaced by an uploaded font.
+// The name is case-insensitive like the font names of the PDF.
+func (registry *FontRegistry) Register(family FontFamily) error {
+	if err := registry.loadFamilies(); err != nil {
+		return err
+	}
+	return registry.register(family, true)
+}
+
+// register adds the font family. A family added by Register (limited is true) counts against MaxFamilies and MaxBytes.
+func (registry *FontRegistry) register(family FontFamily, limited bool) error {
+	// --> validate inputs
+	if !fontFamilyNamePattern.MatchString(family.Name) {
+		return errorsWithStack.New(fmt.Sprintf("the font family name \"%s\" may only contain letters, digits, - and _", family.Name))
+	}
+	if coreFontNames[strings.ToLower(family.Name)] {
+		return errorsWithStack.New(fmt.Sprintf("the font family name \"%s\" is reserved for a standard PDF font", family.Name))
+	}
+	if len(family.Styles[""]) == 0 {
+		return errorsWithStack.New(fmt.Sprintf("the font family \"%s\" has no regular style", family.Name))
+	}
+	if registry.parent != nil && registry.parent.IsRegistered(family.Name) {
+		return errorsWithStack.New(fmt.Sprintf("the font family \"%s\" is already registered", family.Name))
+	}
+	// <--
+
+	size := 0
+	registered := &fontFamily{name: family.Name, files: map[string][]byte{}, fonts: map[string]*trueTypeFont{}}
+	for styleStr, data := range family.Styles {
+		if !isFontStyle(styleStr) {
+			return errorsWithStack.New(fmt.Sprintf("the style \"%s\" of the font family \"%s\" is no face style like \"\", \"b\" or \"bi\"", styleStr, family.Name))
+		}
+		if len(data) == 0 {
+			continue
+		}
+
+		font, err := parseTrueTypeFont(data)
+		if err != nil {
+			return errorsWithStack.New(fmt.Sprintf("the style \"%s\" of the font family \"%s\" is no TrueType font: %s", styleStr, family.Name, err.Error()))
+		}
+		registered.files[styleStr] = data
+		registered.fonts[styleStr] = font
+		size += len(data)
+	}
+
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	// checked with the lock, a concurrent request may register the same family
+	key := strings.ToLower(family.Name)
+	if registry.families[key] != nil {
+		return errorsWithStack.New(fmt.Sprintf("the font family \"%s\" is already registered", family.Name))
+	}
+	if limited {
+		if registry.MaxFamilies > 0 && registry.addedFamilies >= registry.MaxFamilies {
+			return errorsWithStack.New(fmt.Sprintf("the font family \"%s\" exceeds the limit of %d font families", family.Name, registry.MaxFamilies))
+		}
+		if registry.MaxBytes > 0 && registry.addedBytes+size > registry.MaxBytes {
+			return errorsWithStack.New(fmt.Sprintf("the font family \"%s\" exceeds the limit of %d bytes of font files", family.Name, registry.MaxBytes))
+		}
+		registry.addedFamilies++
+		registry.addedBytes += size
+	}
+
+	registry.families[key] = registered
+	return nil
+}
+
+// RegisterDir registers the font files of dir as font families.
+// A file name is the family name and the style (see FontStyles), e.g. "OpenSans-Bold.ttf" or "NotoSansJP-Regular.otf".
+// Other files are ignored.
+func (registry *FontRegistry) RegisterDir(dir string) error {
+	if err := registry.loadFamilies(); err != nil {
+		return err
+	}
+	return registry.registerFS(os.DirFS(dir))
+}
+
+// registerFS registers the font files in the root of fsys, see RegisterDir.
+func (registry *FontRegistry) registerFS(fsys fs.FS) error {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return err
+	}
+
+	families := map[string]*FontFamily{}
+	for _, entry := range entries {
+		extension := path.Ext(entry.Name())
+		if entry.IsDir() || (!strings.EqualFold(extension, ".ttf") && !strings.EqualFold(extension, ".otf")) {
+			continue
+		}
+
+		name, styleName, found := strings.Cut(strings.TrimSuffix(entry.Name(), extension), "-")
+		styleStr, knownStyle := FontStyles[styleName]
+		if !found || !knownStyle {
+			continue
+		}
+
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return err
+		}
+
+		if families[name] == nil {
+			families[name] = &FontFamily{Name: name, Styles: map[string][]byte{}}
+		}
+		families[name].Styles[styleStr] = data
+	}
+
+	for _, family := range families {
+		if err = registry.register(*family, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsRegistered returns true, if the font family is registered in the registry or its parent.
+func (registry *FontRegistry) IsRegistered(name string) bool {
+	family, err := registry.family(name)
+	return err == nil && family != nil
+}
+
+// Families returns the sorted names of all registered font families, including the families of the parent.
+func (registry *FontRegistry) Families() (names []string, err error) {
+	seen := map[string]bool{}
+	for current := registry; current != nil; current = current.parent {
+		if err = current.loadFamilies(); err != nil {
+			return nil, err
+		}
+
+		current.mutex.RLock()
+		for key, family := range current.families {
+			if !seen[key] {
+				seen[key] = true
+				names = append(names, family.name)
+			}
+		}
+		current.mutex.RUnlock()
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+// family returns the registered family of name or nil, if it is not registered.
+func (registry *FontRegistry) family(name string) (*fontFamily, error) {
+	for current := registry; current != nil; current = current.parent {
+		if err := current.loadFamilies(); err != nil {
+			return nil, err
+		}
+
+		current.mutex.RLock()
+		family := current.families[strings.ToLower(name)]
+		current.mutex.RUnlock()
+		if family != nil {
+			return family, nil
+		}
+	}
+	return nil, nil
+}
+
+// loadFamilies fills the registry on the first use, see DefaultFontRegistry.
+func (registry *FontRegistry) loadFamilies() error {
+	if registry.load == nil {
+		return nil
+	}
+	registry.loadOnce.Do(func() {
+		registry.loadErr = registry.load(registry)
+	})
+	return registry.loadErr
+}
+
+func isFontStyle(styleStr string) bool {
+	for _, knownStyle := range FontStyles {
+		if styleStr == knownStyle {
+			return true
+		}
+	}
+	return false
+}
+
+// hasGlyph returns true, if the regular style of the family contains a glyph for r.
+func (family *fontFamily) hasGlyph(r rune) bool {
+	return family.fonts[""].glyphIndex(r) != 0
+}
+
+// setFont sets the font face nearest to the style (see ParseFontStyle) like SetFont of gofpdf.
+// family is nil for the standard PDF fonts.
+// A face of a registered font family is added to the pdf on its first use,
+// so the unused faces are neither parsed nor embedded by gofpdf.
+func (core *PDFGenerator) setFont(family *fontFamily, styleStr string, size float64) {
+	style, _ := ParseFontStyle(styleStr)
+	if family == nil {
+		core.pdf.SetFont(core.data.FontName, style.coreFace()+style.decoration(), size)
+		return
+	}
+
+	face := family.face(style)
+	fontKey := strings.ToLower(family.name) + face
+	if !core.addedFontStyles[fontKey] {
+		core.addedFontStyles[fontKey] = true
+		// gofpdf writes into the font data, while it embeds the font, so each pdf gets a copy of the shared data.
+		// gofpdf keeps its parsed font (with the used characters of the subset) unexported in the pdf,
+		// it cannot be shared between documents, so the face is parsed again for each pdf.
+		fontData := family.files[face]
+		core.pdf.AddUTF8FontFromBytes(family.name, face, append(make([]byte, 0, len(fontData)), fontData...))
+	}
+	core.pdf.SetFont(family.name, face+style.decoration(), size)
+}
+
+// textRun is a part of a text printed with one font family.
+type textRun struct {
+	family *fontFamily
+	text   string
+}
+
+// textRuns splits text into runs of the primary font family and the fallback font families.
+// Each character is printed with the first family containing its glyph.
+// It returns nil, if the whole text is printed with the primary font family.
+func (core *PDFGenerator) textRuns(text string) (runs []textRun) {
+	if core.fontFamily == nil || len(core.fallbackFontFamilies) == 0 {
+		return nil
+	}
+
+	var current *fontFamily
+	var runText strings.Builder
+	for _, r := range text {
+		family := current
+		if family == nil || !unicode.IsSpace(r) {
+			family = core.fontFamily
+			if !family.hasGlyph(r) && !unicode.IsSpace(r) {
+				for _, fallback := range core.fallbackFontFamilies {
+					if fallback.hasGlyph(r) {
+						family = fallback
+						break
+					}
+				}
+			}
+		}
+
+		if family != current && runText.Len() > 0 {
+			runs = append(runs, textRun{family: current, text: runText.String()})
+			runText.Reset()
+		}
+		current = family
+		runText.WriteRune(r)
+	}
+	if runText.Len() > 0 {
+		runs = append(runs, textRun{family: current, text: runText.String()})
+	}
+
+	if len(runs) == 1 && runs[0].family == core.fontFamily {
+		return nil
+	}
+	return runs
+}
+
+// runeWidth returns the width of r printed with the family in the unit of measure.
+func (core *PDFGenerator) runeWidth(family *fontFamily, styleStr string, r rune) float64 {
+	style, _ := ParseFontStyle(styleStr)
+	font := family.fonts[family.face(style)]
+	_, fontSize := core.pdf.GetFontSize()
+	return font.advanceWidth(font.glyphIndex(r)) / font.unitsPerEm * fontSize
+}
+
+// textWidth returns the width of text with the current font size, like GetStringWidth of gofpdf,
+// but includes the characters printed with the fallback fonts.
+func (core *PDFGenerator) textWidth(text string, styleStr string) (width float64) {
+	runs := core.textRuns(text)
+	if runs == nil {
+		return core.pdf.GetStringWidth(text)
+	}
+
+	for _, run := range runs {
+		for _, r := range run.text {
+			width += core.runeWidth(run.family, styleStr, r)
+		}
+	}
+	return width
+}
+
+// cellFormat prints a text cell like CellFormat of gofpdf with ln = 0.
+// The characters missing in the primary font are printed with the fallback fonts
+// and the styles missing in a font family are simulated (see fontFamily.synthetic).
+func (core *PDFGenerator) cellFormat(cellWidth float64, cellHeight float64, text string, styleStr string, borderStr string, alignStr string, fill bool) {
+	style, _ := ParseFontStyle(styleStr)
+	runs := core.textRuns(text)
+	if runs == nil {
+		if syntheticBold, syntheticItalic := core.fontFamily.synthetic(style); !syntheticBold && !syntheticItalic {
+			core.pdf.CellFormat(cellWidth, cellHeight, text, borderStr, 0, alignStr, fill, 0, "")
+			return
+		}
+		runs = []textRun{{family: core.fontFamily, text: text}}
+	}
+
+	// print the border and background first, a page break may occur here
+	core.pdf.CellFormat(cellWidth, cellHeight, "", borderStr, 0, "", fill, 0, "")
+	x, y := core.pdf.GetXY()
+	x -= cellWidth
+	fontSize, fontSizeUnit := core.pdf.GetFontSize()
+	dx, dy := core.cellTextOffset(core.textWidth(text, styleStr), alignStr, cellWidth, cellHeight)
+
+	for _, run := range runs {
+		core.setFont(run.family, styleStr, fontSize)
+		syntheticBold, syntheticItalic := run.family.synthetic(style)
+		core.printText(x+dx, y+dy+.5*cellHeight+.3*fontSizeUnit, run.text, syntheticBold, syntheticItalic)
+		for _, r := range run.text {
+			dx += core.runeWidth(run.family, styleStr, r)
+		}
+	}
+
+	core.setFont(core.fontFamily, styleStr, fontSize)
+	core.pdf.SetXY(x+cellWidth, y)
+}
+
+// printText prints text at the baseline position x, y with the current font.
+// A synthetic bold text is printed with an outline in the text color, a synthetic italic text is skewed.
+func (core *PDFGenerator) printText(x float64, y float64, text string, syntheticBold bool, syntheticItalic bool) {
+	if syntheticItalic {
+		core.pdf.TransformBegin()
+		core.pdf.TransformSkewX(syntheticItalicAngle, x, y)
+		defer core.pdf.TransformEnd()
+	}
+
+	if syntheticBold {
+		_, fontSize := core.pdf.GetFontSize()
+		lineWidth := core.pdf.GetLineWidth()
+		r, g, b := core.pdf.GetDrawColor()
+		core.pdf.SetLineWidth(fontSize * syntheticBoldStroke)
+		core.pdf.SetDrawColor(core.pdf.GetTextColor())
+		core.pdf.SetTextRenderingMode(2)
+		defer func() {
+			core.pdf.SetTextRenderingMode(0)
+			core.pdf.SetLineWidth(lineWidth)
+			core.pdf.SetDrawColor(r, g, b)
+		}()
+	}
+
+	core.pdf.Text(x, y, text)
+}
+
+// cellTextOffset returns the position of a text in a cell relative to the upper left corner of the cell,
+// like CellFormat of gofpdf aligns the text.
+func (core *PDFGenerator) cellTextOffset(textWidth float64, alignStr string, cellWidth float64, cellHeight float64) (dx float64, dy float64) {
+	_, fontSize := core.pdf.GetFontSize()
+	cellMargin := core.pdf.GetCellMargin()
+
+	switch {
+	case strings.Contains(alignStr, "R"):
+		dx = cellWidth - cellMargin - textWidth
+	case strings.Contains(alignStr, "C"):
+		dx = (cellWidth - textWidth) / 2
+	default:
+		dx = cellMargin
+	}
+
+	switch {
+	case strings.Contains(alignStr, "T"):
+		dy = (fontSize - cellHeight) / 2
+	case strings.Contains(alignStr, "B"):
+		dy = (cellHeight - fontSize) / 2
+	}
+	return dx, dy
+}
+
+// splitLine wraps one line at width like SplitText of gofpdf,
+// but includes the characters printed with the fallback fonts.
+func (core *PDFGenerator) splitLine(line string, styleStr string, width float64) (lines []string) {
+	runs := core.textRuns(line)
+	if runs == nil {
+		return core.pdf.SplitText(line, width)
+	}
+
+	var text []rune
+	var widths []float64
+	for _, run := range runs {
+		for _, r := range run.text {
+			text = append(text, r)
+			widths = append(widths, core.runeWidth(run.family, styleStr, r))
+		}
+	}
+
+	// a line is broken after a space or before a chinese character, a word longer than the line is broken anywhere
+	maxWidth := width - 2*core.pdf.GetCellMargin()
+	start, lineEnd, next, lineWidth := 0, -1, -1, 0.
+	for i := 0; i < len(text); {
+		switch {
+		case unicode.IsSpace(text[i]):
+			lineEnd, next = i, i+1
+		case unicode.Is(unicode.Han, text[i]) && i > start:
+			lineEnd, next = i, i
+		}
+
+		lineWidth += widths[i]
+		if lineWidth <= maxWidth {
+			i++
+			continue
+		}
+
+		if lineEnd == -1 {
+			if i == start {
+				i++
+			}
+			lineEnd, next = i, i
+		}
+		lines = append(lines, string(text[start:lineEnd]))
+		start, i, lineEnd, lineWidth = next, next, -1, 0
+	}
+	if start < len(text) {
+		lines = append(lines, string(text[start:]))
+	}
+	return lines
+}
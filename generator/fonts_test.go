@@ -0,0 +1,388 @@
+package generator
+
+import (
+	"bytes"
+	"github.com/jung-kurt/gofpdf"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+// openSansFamily returns the regular style of the bundled OpenSans font.
+func openSansFamily(t *testing.T, name string) FontFamily {
+	data, err := os.ReadFile("../fonts/OpenSans-Regular.ttf")
+	if err != nil {
+		t.Fatalf("read test font error\n%s", err.Error())
+	}
+	return FontFamily{Name: name, Styles: map[string][]byte{"": data}}
+}
+
+// fallbackTestGenerator returns a generator with the font "Primary" without digits and the fallback font "Fallback".
+func fallbackTestGenerator(t *testing.T) *PDFGenerator {
+	registry := NewFontRegistry(nil)
+	for _, name := range []string{"Primary", "Fallback"} {
+		if err := registry.Register(openSansFamily(t, name)); err != nil {
+			t.Fatalf("register test font error\n%s", err.Error())
+		}
+	}
+
+	data := _defaultMetaData
+	data.FontName = "Primary"
+	data.FontRegistry = registry
+	data.FallbackFontNames = []string{"Fallback"}
+	core, err := NewPDFGenerator(data, false, &_logger, func() {}, func(isLastPage bool) {})
+	if err != nil {
+		t.Fatalf("init core error\n%s", err.Error())
+	}
+
+	// the primary font misses the digits
+	fullFont := core.fontFamily.fonts[""]
+	withoutDigits := *fullFont
+	withoutDigits.cmap = func(r rune) int {
+		if unicode.IsDigit(r) {
+			return 0
+		}
+		return fullFont.cmap(r)
+	}
+	core.fontFamily = &fontFamily{name: core.fontFamily.name, files: core.fontFamily.files, fonts: map[string]*trueTypeFont{"": &withoutDigits}}
+
+	return core
+}
+
+func TestFontRegistry_Register(t *testing.T) {
+	openSans := openSansFamily(t, "Custom")
+
+	tests := []struct {
+		name    string
+		family  FontFamily
+		wantErr bool
+	}{
+		{
+			name:    "regular style",
+			family:  openSans,
+			wantErr: false,
+		},
+		{
+			name:    "all styles",
+			family:  FontFamily{Name: "Custom", Styles: map[string][]byte{"": openSans.Styles[""], "l": openSans.Styles[""], "i": openSans.Styles[""], "b": openSans.Styles[""], "m": openSans.Styles[""]}},
+			wantErr: false,
+		},
+		{
+			name:    "invalid name",
+			family:  FontFamily{Name: "../Custom", Styles: openSans.Styles},
+			wantErr: true,
+		},
+		{
+			name:    "standard font name",
+			family:  FontFamily{Name: "Helvetica", Styles: openSans.Styles},
+			wantErr: true,
+		},
+		{
+			name:    "no regular style",
+			family:  FontFamily{Name: "Custom", Styles: map[string][]byte{"b": openSans.Styles[""]}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid style",
+			family:  FontFamily{Name: "Custom", Styles: map[string][]byte{"": openSans.Styles[""], "x": openSans.Styles[""]}},
+			wantErr: true,
+		},
+		{
+			name:    "no font",
+			family:  FontFamily{Name: "Custom", Styles: map[string][]byte{"": []byte("no font")}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewFontRegistry(nil)
+			if err := registry.Register(tt.family); (err != nil) != tt.wantErr {
+				t.Errorf("Register() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotRegistered := registry.IsRegistered("custom"); gotRegistered == tt.wantErr {
+				t.Errorf("IsRegistered() = %v, want %v", gotRegistered, !tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFontRegistry_Register_registered(t *testing.T) {
+	openSans := openSansFamily(t, "Custom")
+	size := len(openSans.Styles[""])
+
+	tests := []struct {
+		name        string
+		maxFamilies int
+		maxBytes    int
+		families    []string
+		wantErr     []bool
+	}{
+		{name: "same name", families: []string{"Custom", "CUSTOM"}, wantErr: []bool{false, true}},
+		{name: "bundled family", families: []string{"OpenSans", "opensans"}, wantErr: []bool{true, true}},
+		{name: "family limit", maxFamilies: 2, families: []string{"A", "B", "C"}, wantErr: []bool{false, false, true}},
+		{name: "bytes limit", maxBytes: 2*size - 1, families: []string{"A", "B"}, wantErr: []bool{false, true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewFontRegistry(DefaultFontRegistry)
+			registry.MaxFamilies, registry.MaxBytes = tt.maxFamilies, tt.maxBytes
+			for i, name := range tt.families {
+				openSans.Name = name
+				if err := registry.Register(openSans); (err != nil) != tt.wantErr[i] {
+					t.Errorf("Register(%s) error = %v, wantErr %v", name, err, tt.wantErr[i])
+				}
+			}
+
+			// a rejected family does not replace the registered family
+			family, _ := registry.family("OpenSans")
+			if family == nil || len(family.fonts) != len(FontStyles) {
+				t.Errorf("the bundled OpenSans is replaced")
+			}
+		})
+	}
+}
+
+func TestFontRegistry_RegisterDir(t *testing.T) {
+	parent := NewFontRegistry(nil)
+	if err := parent.RegisterDir("../fonts"); err != nil {
+		t.Fatalf("RegisterDir() error = %v", err)
+	}
+
+	registry := NewFontRegistry(parent)
+	if err := registry.Register(openSansFamily(t, "Custom")); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	gotFamilies, err := registry.Families()
+	if err != nil {
+		t.Fatalf("Families() error = %v", err)
+	}
+	if wantFamilies := []string{"Custom", "OpenSans"}; !reflect.DeepEqual(gotFamilies, wantFamilies) {
+		t.Errorf("Families() = %v, want %v", gotFamilies, wantFamilies)
+	}
+
+	gotParentFamilies, _ := parent.Families()
+	if wantParentFamilies := []string{"OpenSans"}; !reflect.DeepEqual(gotParentFamilies, wantParentFamilies) {
+		t.Errorf("Families() of the parent = %v, want %v", gotParentFamilies, wantParentFamilies)
+	}
+
+	family, _ := registry.family("OpenSans")
+	for _, styleStr := range FontStyles {
+		if family.fonts[styleStr] == nil {
+			t.Errorf("the style \"%s\" of OpenSans is not registered", styleStr)
+		}
+	}
+
+	if err = registry.RegisterDir("not-existing"); err == nil {
+		t.Errorf("RegisterDir() of a not existing directory returns no error")
+	}
+}
+
+func TestNewPDFGenerator_fallbackFonts(t *testing.T) {
+	registry := NewFontRegistry(nil)
+	if err := registry.Register(openSansFamily(t, "Custom")); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	tests := []struct {
+		name              string
+		fontName          string
+		fallbackFontNames []string
+		wantErr           bool
+	}{
+		{
+			name:              "registered font",
+			fontName:          "Custom",
+			fallbackFontNames: nil,
+			wantErr:           false,
+		},
+		{
+			name:              "registered fallback font",
+			fontName:          "Custom",
+			fallbackFontNames: []string{"custom"},
+			wantErr:           false,
+		},
+		{
+			name:              "fallback font not registered",
+			fontName:          "Custom",
+			fallbackFontNames: []string{"NotoSansJP"},
+			wantErr:           true,
+		},
+		{
+			name:              "fallback font of a standard font",
+			fontName:          "Arial",
+			fallbackFontNames: []string{"Custom"},
+			wantErr:           true,
+		},
+		{
+			name:              "font not registered",
+			fontName:          "NotoSansJP",
+			fallbackFontNames: nil,
+			wantErr:           true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := _defaultMetaData
+			data.FontName = tt.fontName
+			data.FontRegistry = registry
+			data.FallbackFontNames = tt.fallbackFontNames
+
+			_, err := NewPDFGenerator(data, false, &_logger, func() {}, func(isLastPage bool) {})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewPDFGenerator() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPDFGenerator_textRuns(t *testing.T) {
+	core := fallbackTestGenerator(t)
+
+	tests := []struct {
+		name     string
+		text     string
+		wantRuns []string
+	}{
+		{
+			name:     "primary font only",
+			text:     "Invoice",
+			wantRuns: nil,
+		},
+		{
+			name:     "fallback font only",
+			text:     "2023",
+			wantRuns: []string{"Fallback:2023"},
+		},
+		{
+			name:     "mixed",
+			text:     "Invoice 2023-17",
+			wantRuns: []string{"Primary:Invoice ", "Fallback:2023", "Primary:-", "Fallback:17"},
+		},
+		{
+			name:     "spaces keep the font",
+			text:     "1 2 x",
+			wantRuns: []string{"Fallback:1 2 ", "Primary:x"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotRuns []string
+			for _, run := range core.textRuns(tt.text) {
+				gotRuns = append(gotRuns, run.family.name+":"+run.text)
+			}
+			if !reflect.DeepEqual(gotRuns, tt.wantRuns) {
+				t.Errorf("textRuns() = %q, want %q", gotRuns, tt.wantRuns)
+			}
+		})
+	}
+}
+
+func TestPDFGenerator_fallbackFontText(t *testing.T) {
+	core := fallbackTestGenerator(t)
+	core.NewPage()
+	core.SetFontSize(10)
+
+	text := "Invoice 2023-17"
+	if gotWidth, wantWidth := core.ComputeStringLength(text), core.pdf.GetStringWidth(text); gotWidth < wantWidth*0.99 || gotWidth > wantWidth*1.01 {
+		t.Errorf("ComputeStringLength() = %v, want %v", gotWidth, wantWidth)
+	}
+
+	gotLines := core.SplitText(strings.Repeat(text+" ", 10), "", 10, 60)
+	for _, line := range gotLines {
+		if width := core.ComputeStringLength(line); width > 60 {
+			t.Errorf("SplitText() returns the line %q with the width %v, want at most 60", line, width)
+		}
+	}
+	if len(gotLines) < 2 {
+		t.Errorf("SplitText() = %q, want a wrapped text", gotLines)
+	}
+
+	core.PrintPdfText(text, "", "L")
+	core.PrintPdfTextFormatted(text, "b", "R", "1", true, Color{R: 239, G: 239, B: 239}, 10, 80)
+
+	var buffer bytes.Buffer
+	if err := core.Output(&buffer); err != nil {
+		t.Fatalf("Output() error = %v", err)
+	}
+	for _, font := range []string{"utf8primary", "utf8fallback"} {
+		if !bytes.Contains(buffer.Bytes(), []byte(font)) {
+			t.Errorf("the pdf does not contain the font %s", font)
+		}
+	}
+}
+
+func TestDefaultFontRegistry_concurrentGenerators(t *testing.T) {
+	data := _defaultMetaData
+	data.FontName = "OpenSans"
+
+	errs := make(chan error, 8)
+	for i := 0; i < cap(errs); i++ {
+		go func() {
+			core, err := NewPDFGenerator(data, false, &_logger, func() {}, func(isLastPage bool) {})
+			if err != nil {
+				errs <- err
+				return
+			}
+			core.NewPage()
+			core.PrintPdfText("Rechnung 2023-17 über 1.920,00 €", "", "L")
+			core.PrintPdfText("Gesamtbetrag", "b", "L")
+			errs <- core.Output(io.Discard)
+		}()
+	}
+
+	for i := 0; i < cap(errs); i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("concurrent generator error = %v", err)
+		}
+	}
+}
+
+// BenchmarkNewPDFGenerator compares the document generation with the font registry
+// to reading the font files for each document, like it is done without the registry.
+// Both cases add the same faces (regular and bold), gofpdf parses them for each document in both cases,
+// so the difference is the reading of the files.
+func BenchmarkNewPDFGenerator(b *testing.B) {
+	printDocument := func(b *testing.B, pdf *gofpdf.Fpdf) {
+		pdf.AddPage()
+		pdf.SetFont("OpenSans", "", 10)
+		pdf.Cell(40, 10, "Rechnung 2023-17 über 1.920,00 €")
+		pdf.SetFont("OpenSans", "B", 10)
+		pdf.Cell(40, 10, "Gesamtbetrag")
+		if err := pdf.Output(io.Discard); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.Run("font files", func(b *testing.B) {
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				pdf := gofpdf.New("P", "mm", "A4", "")
+				pdf.AddUTF8Font("OpenSans", "", "../fonts/OpenSans-Regular.ttf")
+				pdf.AddUTF8Font("OpenSans", "B", "../fonts/OpenSans-Bold.ttf")
+				printDocument(b, pdf)
+			}
+		})
+	})
+
+	b.Run("font registry", func(b *testing.B) {
+		data := _defaultMetaData
+		data.FontName = "OpenSans"
+
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				core, err := NewPDFGenerator(data, false, &_logger, func() {}, func(isLastPage bool) {})
+				if err != nil {
+					b.Fatal(err)
+				}
+				core.setFont(core.fontFamily, "b", 10)
+				printDocument(b, core.pdf)
+			}
+		})
+	})
+}
@@ -0,0 +1,159 @@
+package generator
+
+import (
+	"fmt"
+	errorsWithStack "github.com/go-errors/errors"
+	"strings"
+)
+
+// FontWeight is the weight of a font face like in CSS, from FontWeightLight to FontWeightExtraBold.
+type FontWeight int
+
+const (
+	FontWeightLight     FontWeight = 300
+	FontWeightRegular   FontWeight = 400
+	FontWeightMedium    FontWeight = 500
+	FontWeightSemiBold  FontWeight = 600
+	FontWeightBold      FontWeight = 700
+	FontWeightExtraBold FontWeight = 800
+)
+
+// fontWeightLetters maps the weight letters of a style string to the font weights.
+var fontWeightLetters = map[rune]FontWeight{
+	'l': FontWeightLight,
+	'm': FontWeightMedium,
+	'd': FontWeightSemiBold,
+	'b': FontWeightBold,
+	'e': FontWeightExtraBold,
+}
+
+// syntheticItalicAngle is the skew angle in degree of a synthetic italic text.
+const syntheticItalicAngle = 12
+
+// syntheticBoldStroke is the outline width of a synthetic bold text relative to the font size.
+const syntheticBoldStroke = 0.03
+
+// FontStyle is the style of a printed text, see ParseFontStyle.
+type FontStyle struct {
+	Weight        FontWeight
+	Italic        bool
+	Underline     bool
+	StrikeThrough bool
+}
+
+// ParseFontStyle parses the style string styleStr of the text print functions.
+// The letters are case-insensitive and combinable in any order, e.g. "bi" for bold italic or "lu" for light underlined:
+//
+//	"l" light,
+//	"m" medium,
+//	"d" semi-bold (demi-bold),
+//	"b" bold,
+//	"e" extra-bold,
+//	"i" italic,
+//	"u" underline and
+//	"s" strike-through.
+//
+// At most one weight is allowed, the empty string is the regular style.
+func ParseFontStyle(styleStr string) (style FontStyle, err error) {
+	style.Weight = FontWeightRegular
+	hasWeight := false
+
+	for _, letter := range strings.ToLower(styleStr) {
+		if weight, ok := fontWeightLetters[letter]; ok {
+			if hasWeight {
+				return FontStyle{Weight: FontWeightRegular}, errorsWithStack.New(fmt.Sprintf("the style \"%s\" has more than one font weight", styleStr))
+			}
+			style.Weight, hasWeight = weight, true
+			continue
+		}
+
+		switch letter {
+		case 'i':
+			style.Italic = true
+		case 'u':
+			style.Underline = true
+		case 's':
+			style.StrikeThrough = true
+		default:
+			return FontStyle{Weight: FontWeightRegular}, errorsWithStack.New(fmt.Sprintf("the style \"%s\" contains the unknown letter \"%c\", use l, m, d, b, e, i, u or s", styleStr, letter))
+		}
+	}
+
+	return style, nil
+}
+
+// String returns the style string of the style, e.g. "bi".
+func (style FontStyle) String() string {
+	return style.face() + style.decoration()
+}
+
+// face returns the style string of the font face: the weight and italic, e.g. "bi" or "".
+func (style FontStyle) face() (face string) {
+	for letter, weight := range fontWeightLetters {
+		if weight == style.Weight {
+			face = string(letter)
+		}
+	}
+	if style.Italic {
+		face += "i"
+	}
+	return face
+}
+
+// decoration returns the underline and strike-through letters of the style in the notation of gofpdf.
+func (style FontStyle) decoration() (decoration string) {
+	if style.Underline {
+		decoration += "U"
+	}
+	if style.StrikeThrough {
+		decoration += "S"
+	}
+	return decoration
+}
+
+// coreFace returns the face of the standard PDF fonts, which only have a bold and an italic face.
+func (style FontStyle) coreFace() (face string) {
+	if style.Weight >= FontWeightSemiBold {
+		face = "B"
+	}
+	if style.Italic {
+		face += "I"
+	}
+	return face
+}
+
+// face returns the registered face, which is the nearest to the style.
+// A face with the same italic style is preferred over the weight.
+// Between two faces with the same weight distance, the heavier face is used for weights from medium on,
+// otherwise the lighter one.
+func (family *fontFamily) face(style FontStyle) (nearest string) {
+	bestDistance := -1
+	for face := range family.files {
+		faceStyle, _ := ParseFontStyle(face)
+		distance := int(faceStyle.Weight - style.Weight)
+		heavier := distance > 0
+		if distance < 0 {
+			distance = -distance
+		}
+		if faceStyle.Italic != style.Italic {
+			distance += 1000
+		}
+
+		preferred := heavier == (style.Weight >= FontWeightMedium)
+		if bestDistance == -1 || distance < bestDistance || (distance == bestDistance && preferred) {
+			nearest, bestDistance = face, distance
+		}
+	}
+	return nearest
+}
+
+// synthetic returns, which parts of the style are simulated, because the family has no matching face.
+// The weight is simulated, if the face is two weights or more lighter than the style.
+func (family *fontFamily) synthetic(style FontStyle) (bold bool, italic bool) {
+	if family == nil {
+		return false, false
+	}
+
+	faceStyle, _ := ParseFontStyle(family.face(style))
+	return style.Weight-faceStyle.Weight >= 200, style.Italic && !faceStyle.Italic
+}
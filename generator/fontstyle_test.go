@@ -0,0 +1,213 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParseFontStyle(t *testing.T) {
+	tests := []struct {
+		name     string
+		styleStr string
+		want     FontStyle
+		wantErr  bool
+	}{
+		{
+			name:     "regular",
+			styleStr: "",
+			want:     FontStyle{Weight: FontWeightRegular},
+			wantErr:  false,
+		},
+		{
+			name:     "semi-bold italic",
+			styleStr: "di",
+			want:     FontStyle{Weight: FontWeightSemiBold, Italic: true},
+			wantErr:  false,
+		},
+		{
+			name:     "upper case bold underline",
+			styleStr: "BU",
+			want:     FontStyle{Weight: FontWeightBold, Underline: true},
+			wantErr:  false,
+		},
+		{
+			name:     "all decorations",
+			styleStr: "suie",
+			want:     FontStyle{Weight: FontWeightExtraBold, Italic: true, Underline: true, StrikeThrough: true},
+			wantErr:  false,
+		},
+		{
+			name:     "two weights",
+			styleStr: "lb",
+			want:     FontStyle{Weight: FontWeightRegular},
+			wantErr:  true,
+		},
+		{
+			name:     "unknown letter",
+			styleStr: "x",
+			want:     FontStyle{Weight: FontWeightRegular},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFontStyle(tt.styleStr)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseFontStyle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseFontStyle() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_fontFamily_face(t *testing.T) {
+	family := &fontFamily{name: "Custom", files: map[string][]byte{"": nil, "l": nil, "b": nil, "i": nil}}
+	regularOnly := &fontFamily{name: "Regular", files: map[string][]byte{"": nil}}
+
+	tests := []struct {
+		name                string
+		family              *fontFamily
+		styleStr            string
+		wantFace            string
+		wantSyntheticBold   bool
+		wantSyntheticItalic bool
+	}{
+		{
+			name:     "registered face",
+			family:   family,
+			styleStr: "b",
+			wantFace: "b",
+		},
+		{
+			name:     "decorations are no face",
+			family:   family,
+			styleStr: "lus",
+			wantFace: "l",
+		},
+		{
+			name:     "medium uses the nearest face",
+			family:   family,
+			styleStr: "m",
+			wantFace: "",
+		},
+		{
+			name:     "semi-bold uses bold",
+			family:   family,
+			styleStr: "d",
+			wantFace: "b",
+		},
+		{
+			name:     "extra-bold uses bold",
+			family:   family,
+			styleStr: "e",
+			wantFace: "b",
+		},
+		{
+			name:     "italic is preferred over the weight",
+			family:   family,
+			styleStr: "li",
+			wantFace: "i",
+		},
+		{
+			name:              "synthetic bold",
+			family:            family,
+			styleStr:          "bi",
+			wantFace:          "i",
+			wantSyntheticBold: true,
+		},
+		{
+			name:                "synthetic bold italic",
+			family:              regularOnly,
+			styleStr:            "di",
+			wantFace:            "",
+			wantSyntheticBold:   true,
+			wantSyntheticItalic: true,
+		},
+		{
+			name:     "medium is not synthetic",
+			family:   regularOnly,
+			styleStr: "m",
+			wantFace: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			style, _ := ParseFontStyle(tt.styleStr)
+			if gotFace := tt.family.face(style); gotFace != tt.wantFace {
+				t.Errorf("face() = %q, want %q", gotFace, tt.wantFace)
+			}
+			gotBold, gotItalic := tt.family.synthetic(style)
+			if gotBold != tt.wantSyntheticBold || gotItalic != tt.wantSyntheticItalic {
+				t.Errorf("synthetic() = %v, %v, want %v, %v", gotBold, gotItalic, tt.wantSyntheticBold, tt.wantSyntheticItalic)
+			}
+		})
+	}
+}
+
+func TestPDFGenerator_PrintPdfText_fontStyles(t *testing.T) {
+	registry := NewFontRegistry(DefaultFontRegistry)
+	if err := registry.Register(openSansFamily(t, "Custom")); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		fontName string
+		styleStr string
+		wantErr  bool
+	}{
+		{
+			name:     "registered font with synthetic bold italic",
+			fontName: "Custom",
+			styleStr: "bius",
+			wantErr:  false,
+		},
+		{
+			name:     "OpenSans semi-bold italic",
+			fontName: "OpenSans",
+			styleStr: "di",
+			wantErr:  false,
+		},
+		{
+			name:     "standard font extra-bold",
+			fontName: "Arial",
+			styleStr: "eu",
+			wantErr:  false,
+		},
+		{
+			name:     "two weights",
+			fontName: "OpenSans",
+			styleStr: "bl",
+			wantErr:  true,
+		},
+		{
+			name:     "unknown letter",
+			fontName: "OpenSans",
+			styleStr: "k",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := _defaultMetaData
+			data.FontName = tt.fontName
+			data.FontRegistry = registry
+			core, err := NewPDFGenerator(data, false, &_logger, func() {}, func(isLastPage bool) {})
+			if err != nil {
+				t.Fatalf("init core error\n%s", err.Error())
+			}
+			core.NewPage()
+			core.SetFontSize(10)
+
+			core.PrintLnPdfText("Rechnung 2023-17", tt.styleStr, "L")
+			core.PrintPdfTextFormatted("Gesamtbetrag", tt.styleStr, "R", "1", false, Color{}, 10, 80)
+
+			var buffer bytes.Buffer
+			if err = core.Output(&buffer); (err != nil) != tt.wantErr {
+				t.Errorf("Output() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
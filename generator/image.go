@@ -0,0 +1,80 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	errorsWithStack "github.com/go-errors/errors"
+)
+
+// MaxImageSize is the maximum size in bytes of a registered image.
+var MaxImageSize = 5 << 20
+
+// imageMagicBytes maps the first bytes of the image files to the image types of gofpdf.
+var imageMagicBytes = []struct {
+	prefix    []byte
+	imageType string
+}{
+	{prefix: []byte{0xFF, 0xD8, 0xFF}, imageType: "jpg"},
+	{prefix: []byte("\x89PNG\r\n\x1a\n"), imageType: "png"},
+	{prefix: []byte("GIF87a"), imageType: "gif"},
+	{prefix: []byte("GIF89a"), imageType: "gif"},
+}
+
+// ImageTypeFromData returns the image type "jpg", "png", "gif" or "svg" of the image data, read from its magic bytes.
+// SVG images are recognized as XML with an svg element.
+// It returns an error for other types, for invalid svg images and for images greater than MaxImageSize.
+func ImageTypeFromData(imageData []byte) (imageType string, err error) {
+	if len(imageData) > MaxImageSize {
+		return "", errorsWithStack.New(fmt.Sprintf("the image has %d bytes, only %d bytes are allowed", len(imageData), MaxImageSize))
+	}
+
+	for _, magic := range imageMagicBytes {
+		if bytes.HasPrefix(imageData, magic.prefix) {
+			return magic.imageType, nil
+		}
+	}
+
+	xmlData := bytes.TrimLeft(bytes.TrimPrefix(imageData, []byte("\xEF\xBB\xBF")), " \t\r\n")
+	if bytes.HasPrefix(xmlData, []byte("<")) && bytes.Contains(xmlData, []byte("<svg")) {
+		if _, err = parseSVGImage(imageData); err != nil {
+			return "", err
+		}
+		return "svg", nil
+	}
+
+	return "", errorsWithStack.New(fmt.Sprintf("the image type is not supported, use a JPEG, PNG, GIF or SVG image"))
+}
+
+// RegisterImage registers a JPEG, PNG, GIF or SVG image, e.g. uploaded with the request.
+// An SVG image is drawn as vector graphic, see svgImage for the supported SVG elements.
+// The image will be registered in the PDF but not place on a page!
+// Use PlaceRegisteredImageOnPage to place the image on a page.
+//
+// imageNameStr specifies the image identifier for placing the image on a pdf page.
+//
+// imageData is the image file, the image type is read from its first bytes (see ImageTypeFromData).
+func (core *PDFGenerator) RegisterImage(imageNameStr string, imageData []byte) {
+	if core.strictErrorHandling == true && core.pdf.Err() {
+		return
+	}
+
+	// --> validate inputs
+	imageType, err := ImageTypeFromData(imageData)
+	if err != nil {
+		core.pdf.SetError(errorsWithStack.New(fmt.Sprintf("The image \"%s\" can't be registered: %s", imageNameStr, err.Error())))
+		return
+	}
+
+	if core.ImageIsRegistered(imageNameStr) {
+		core.pdf.SetError(errorsWithStack.New(fmt.Sprintf("The image \"%s\" is already registered.", imageNameStr)))
+		return
+	}
+	// <--
+
+	if imageType == "svg" {
+		core.registerSvgImage(imageNameStr, imageData)
+		return
+	}
+
+	core.registerImageReader(imageNameStr, imageType, bytes.NewReader(imageData))
+}
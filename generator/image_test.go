@@ -0,0 +1,140 @@
+package generator
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// testPng returns a small PNG image, e.g. a logo.
+func testPng(t *testing.T) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	img.Set(1, 1, color.RGBA{R: 26, G: 77, B: 143, A: 255})
+
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, img); err != nil {
+		t.Fatalf("create test image error\n%s", err.Error())
+	}
+	return buffer.Bytes()
+}
+
+func TestPDFGenerator_RegisterImage(t *testing.T) {
+	pngData := testPng(t)
+
+	tests := []struct {
+		name      string
+		imageData []byte
+		wantType  string
+		wantErr   bool
+	}{
+		{
+			name:      "png",
+			imageData: pngData,
+			wantType:  "png",
+			wantErr:   false,
+		},
+		{
+			name:      "no image",
+			imageData: []byte("<svg></svg>"),
+			wantErr:   true,
+		},
+		{
+			name:      "empty",
+			imageData: nil,
+			wantErr:   true,
+		},
+		{
+			name:      "too large",
+			imageData: append(append([]byte{}, pngData...), make([]byte, MaxImageSize)...),
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotType, err := ImageTypeFromData(tt.imageData)
+			if (err != nil) != tt.wantErr || gotType != tt.wantType {
+				t.Errorf("ImageTypeFromData() = %v, %v, want %v, wantErr %v", gotType, err, tt.wantType, tt.wantErr)
+			}
+
+			core, err := NewPDFGenerator(_defaultMetaData, false, &_logger, func() {}, func(isLastPage bool) {})
+			if err != nil {
+				t.Fatalf("init core error\n%s", err.Error())
+			}
+
+			core.RegisterImage("logo", tt.imageData)
+			if gotErr := core.pdf.Err(); gotErr != tt.wantErr {
+				t.Errorf("RegisterImage() set a error = %v, want %v", core.pdf.Error(), tt.wantErr)
+			}
+			if gotRegistered := core.ImageIsRegistered("logo"); gotRegistered == tt.wantErr {
+				t.Errorf("ImageIsRegistered() = %v, want %v", gotRegistered, !tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPDFGenerator_RegisterMimeImageToPdf(t *testing.T) {
+	pngData := testPng(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/logo.png":
+			// no Content-Type header, the image type is read from the data
+			w.Header()["Content-Type"] = nil
+			_, _ = w.Write(pngData)
+		case "/logo.txt":
+			_, _ = w.Write([]byte("no image"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{
+			name:    "image without content type",
+			path:    "/logo.png",
+			wantErr: false,
+		},
+		{
+			name:    "no image",
+			path:    "/logo.txt",
+			wantErr: true,
+		},
+		{
+			name:    "not found",
+			path:    "/missing.png",
+			wantErr: true,
+		},
+	}
+	// the test server is a loopback address
+	data := _defaultMetaData
+	data.ImageCache = NewImageCache()
+	data.ImageCache.AllowPrivateNetworks = true
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, err := NewPDFGenerator(data, false, &_logger, func() {}, func(isLastPage bool) {})
+			if err != nil {
+				t.Fatalf("init core error\n%s", err.Error())
+			}
+
+			imageUrl, _ := url.Parse(server.URL + tt.path)
+			gotName := core.RegisterMimeImageToPdf(imageUrl)
+			if gotErr := core.pdf.Err(); gotErr != tt.wantErr {
+				t.Errorf("RegisterMimeImageToPdf() set a error = %v, want %v", core.pdf.Error(), tt.wantErr)
+			}
+			if gotRegistered := core.ImageIsRegistered(gotName); gotRegistered == tt.wantErr {
+				t.Errorf("ImageIsRegistered() = %v, want %v", gotRegistered, !tt.wantErr)
+			}
+		})
+	}
+}
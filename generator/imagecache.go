@@ -0,0 +1,286 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	errorsWithStack "github.com/go-errors/errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// DefaultImageCache holds the downloaded images of the server, it is used if MetaData.ImageCache is not set.
+var DefaultImageCache = NewImageCache()
+
+// ImageCache keeps downloaded images by their URL, so e.g. the logo of each invoice is downloaded only once.
+//
+// A cached image is used for TTL. Afterwards it is revalidated with its ETag and Last-Modified header,
+// so an unchanged image is not downloaded again. If the server of the image is unreachable or fails,
+// the last good copy is used.
+//
+// MaxBytes limits the size of all cached images, the least recently used images are removed first.
+//
+// Timeout limits each download, a download is limited to MaxImageSize bytes.
+//
+// AllowedHosts are the host names the images may be downloaded from, e.g. "cdn.example.com",
+// or with a leading "*." all sub domains, e.g. "*.example.com". Without allowed hosts, all hosts are allowed.
+// Redirects to other hosts are checked too.
+//
+// Images are not downloaded from loopback, private and link-local addresses (e.g. 127.0.0.1, 10.0.0.1 or the
+// cloud metadata address 169.254.169.254), unless AllowPrivateNetworks is true. The address is checked after
+// the host name is resolved, for each connection and each redirect, so a host name can't be resolved
+// to an internal address later. The images are downloaded without the proxy of the environment.
+//
+// Use NewImageCache and change the settings before the first download. All methods are safe for concurrent use.
+type ImageCache struct {
+	TTL                  time.Duration
+	MaxBytes             int
+	Timeout              time.Duration
+	AllowedHosts         []string
+	AllowPrivateNetworks bool
+
+	mutex     sync.Mutex
+	entries   map[string]*cachedImage
+	size      int
+	now       func() time.Time
+	transport *http.Transport
+}
+
+// errAddressNotAllowed is returned by the dialer of the cache, if the resolved address of a host is not allowed.
+var errAddressNotAllowed = errors.New("the image address is not allowed")
+
+// cachedImage is a downloaded image with the validators of its HTTP response.
+type cachedImage struct {
+	data         []byte
+	etag         string
+	lastModified string
+	expires      time.Time
+	lastUsed     time.Time
+}
+
+// NewImageCache returns an empty cache, which keeps up to 50 MB of images for 10 minutes
+// and downloads each image within 10 seconds.
+func NewImageCache() *ImageCache {
+	cache := &ImageCache{
+		TTL:      10 * time.Minute,
+		MaxBytes: 50 << 20,
+		Timeout:  10 * time.Second,
+		entries:  map[string]*cachedImage{},
+		now:      time.Now,
+	}
+
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: cache.checkAddress}
+	cache.transport = &http.Transport{
+		DialContext:         dialer.DialContext,
+		MaxIdleConns:        100,
+		IdleConnTimeout:     90 * time.Second,
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+	return cache
+}
+
+// checkAddress rejects the connection to a loopback, private or link-local address, see AllowPrivateNetworks.
+// It is called by the dialer with the resolved address, e.g. "10.0.0.1:80".
+func (cache *ImageCache) checkAddress(_ string, address string, _ syscall.RawConn) error {
+	if cache.AllowPrivateNetworks {
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsUnspecified() || ip.IsMulticast() {
+		return fmt.Errorf("%w: %s", errAddressNotAllowed, host)
+	}
+	return nil
+}
+
+// Get returns the image data of imageUrl from the cache or downloads it.
+// The image type and size are checked with ImageTypeFromData, before the image is cached.
+func (cache *ImageCache) Get(imageUrl *url.URL) (imageData []byte, err error) {
+	// --> validate inputs
+	if imageUrl.Scheme != "http" && imageUrl.Scheme != "https" {
+		return nil, errorsWithStack.New(fmt.Sprintf("the image URL \"%s\" is no http or https URL", imageUrl.String()))
+	}
+
+	if !cache.hostAllowed(imageUrl.Hostname()) {
+		return nil, errorsWithStack.New(fmt.Sprintf("the image host \"%s\" is not allowed", imageUrl.Hostname()))
+	}
+	// <--
+
+	key := imageUrl.String()
+	cache.mutex.Lock()
+	entry := cache.entries[key]
+	if entry != nil {
+		entry.lastUsed = cache.now()
+		if entry.lastUsed.Before(entry.expires) {
+			cache.mutex.Unlock()
+			return entry.data, nil
+		}
+	}
+	cache.mutex.Unlock()
+
+	downloaded, notModified, err := cache.download(imageUrl, entry)
+	if err != nil {
+		if entry != nil && isUnreachable(err) {
+			return entry.data, nil
+		}
+		cache.remove(key)
+		return nil, err
+	}
+
+	if notModified {
+		cache.mutex.Lock()
+		entry.expires = cache.now().Add(cache.TTL)
+		cache.mutex.Unlock()
+		return entry.data, nil
+	}
+
+	if _, err = ImageTypeFromData(downloaded.data); err != nil {
+		cache.remove(key)
+		return nil, err
+	}
+
+	cache.store(key, downloaded)
+	return downloaded.data, nil
+}
+
+// imageOriginError is a failed download, which falls back to the cached image.
+type imageOriginError struct {
+	err error
+}
+
+func (e imageOriginError) Error() string {
+	return e.err.Error()
+}
+
+// isUnreachable returns true, if the server of the image is unreachable or has failed.
+func isUnreachable(err error) bool {
+	var originErr imageOriginError
+	return errors.As(err, &originErr)
+}
+
+// download requests the image, conditionally to the validators of the cached entry.
+// notModified is true, if the server confirms the cached image.
+func (cache *ImageCache) download(imageUrl *url.URL, entry *cachedImage) (downloaded *cachedImage, notModified bool, err error) {
+	request, err := http.NewRequest(http.MethodGet, imageUrl.String(), nil)
+	if err != nil {
+		return nil, false, err
+	}
+	if entry != nil && entry.etag != "" {
+		request.Header.Set("If-None-Match", entry.etag)
+	}
+	if entry != nil && entry.lastModified != "" {
+		request.Header.Set("If-Modified-Since", entry.lastModified)
+	}
+
+	redirectDenied := false
+	client := http.Client{
+		Transport: cache.transport,
+		Timeout:   cache.Timeout,
+		CheckRedirect: func(redirect *http.Request, via []*http.Request) error {
+			redirectDenied = true
+			if len(via) >= 10 {
+				return errorsWithStack.New(fmt.Sprintf("the image URL has too many redirects"))
+			}
+			if !cache.hostAllowed(redirect.URL.Hostname()) {
+				return errorsWithStack.New(fmt.Sprintf("the image redirect host \"%s\" is not allowed", redirect.URL.Hostname()))
+			}
+			redirectDenied = false
+			return nil
+		},
+	}
+	rsp, err := client.Do(request)
+	if err != nil {
+		if redirectDenied || errors.Is(err, errAddressNotAllowed) {
+			return nil, false, err
+		}
+		return nil, false, imageOriginError{err: err}
+	}
+	defer func() {
+		_ = rsp.Body.Close()
+	}()
+
+	switch {
+	case rsp.StatusCode == http.StatusNotModified && entry != nil:
+		return nil, true, nil
+	case rsp.StatusCode >= 500:
+		return nil, false, imageOriginError{err: errorsWithStack.New(fmt.Sprintf("the download of the image \"%s\" failed with the status %s", imageUrl.String(), rsp.Status))}
+	case rsp.StatusCode != http.StatusOK:
+		return nil, false, errorsWithStack.New(fmt.Sprintf("the download of the image \"%s\" failed with the status %s", imageUrl.String(), rsp.Status))
+	}
+
+	// read one byte more than allowed, so ImageTypeFromData rejects too large images
+	data, err := io.ReadAll(io.LimitReader(rsp.Body, int64(MaxImageSize)+1))
+	if err != nil {
+		return nil, false, imageOriginError{err: err}
+	}
+
+	return &cachedImage{data: data, etag: rsp.Header.Get("ETag"), lastModified: rsp.Header.Get("Last-Modified")}, false, nil
+}
+
+// store adds or replaces the image and removes the least recently used images above MaxBytes.
+func (cache *ImageCache) store(key string, image *cachedImage) {
+	if len(image.data) > cache.MaxBytes {
+		cache.remove(key)
+		return
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if old := cache.entries[key]; old != nil {
+		cache.size -= len(old.data)
+	}
+	image.lastUsed = cache.now()
+	image.expires = image.lastUsed.Add(cache.TTL)
+	cache.entries[key] = image
+	cache.size += len(image.data)
+
+	for cache.size > cache.MaxBytes {
+		var oldestKey string
+		var oldest *cachedImage
+		for entryKey, entry := range cache.entries {
+			if entryKey != key && (oldest == nil || entry.lastUsed.Before(oldest.lastUsed)) {
+				oldestKey, oldest = entryKey, entry
+			}
+		}
+		cache.size -= len(oldest.data)
+		delete(cache.entries, oldestKey)
+	}
+}
+
+// remove removes the image of key from the cache.
+func (cache *ImageCache) remove(key string) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if entry := cache.entries[key]; entry != nil {
+		cache.size -= len(entry.data)
+		delete(cache.entries, key)
+	}
+}
+
+// hostAllowed returns true, if images may be downloaded from host, see AllowedHosts.
+func (cache *ImageCache) hostAllowed(host string) bool {
+	if len(cache.AllowedHosts) == 0 {
+		return true
+	}
+
+	host = strings.ToLower(host)
+	for _, allowed := range cache.AllowedHosts {
+		allowed = strings.ToLower(allowed)
+		if host == allowed || (strings.HasPrefix(allowed, "*.") && strings.HasSuffix(host, allowed[1:])) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,234 @@
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+// imageServer serves testPng as "/logo.png" with an ETag and counts the downloads of the image data.
+type imageServer struct {
+	*httptest.Server
+	mutex     sync.Mutex
+	downloads int
+	fail      bool
+}
+
+func newImageServer(t *testing.T) *imageServer {
+	pngData := testPng(t)
+	server := &imageServer{}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.mutex.Lock()
+		defer server.mutex.Unlock()
+
+		switch {
+		case server.fail:
+			http.Error(w, "maintenance", http.StatusServiceUnavailable)
+		case r.URL.Path == "/redirect.png":
+			http.Redirect(w, r, "http://localhost:1/logo.png", http.StatusFound)
+		case r.URL.Path != "/logo.png":
+			http.NotFound(w, r)
+		case r.Header.Get("If-None-Match") == `"v1"`:
+			w.WriteHeader(http.StatusNotModified)
+		default:
+			server.downloads++
+			w.Header().Set("ETag", `"v1"`)
+			_, _ = w.Write(pngData)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// count returns the number of downloads.
+func (server *imageServer) count() int {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	return server.downloads
+}
+
+// setFail lets the server fail all requests.
+func (server *imageServer) setFail() {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.fail = true
+}
+
+func (server *imageServer) url(path string) *url.URL {
+	imageUrl, _ := url.Parse(server.URL + path)
+	return imageUrl
+}
+
+func TestImageCache_Get(t *testing.T) {
+	server := newImageServer(t)
+
+	now := time.Now()
+	cache := NewImageCache()
+	cache.AllowPrivateNetworks = true
+	cache.now = func() time.Time {
+		return now
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := cache.Get(server.url("/logo.png")); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+	}
+	if downloads := server.count(); downloads != 1 {
+		t.Errorf("the image is downloaded %d times, want 1", downloads)
+	}
+
+	// after the TTL the image is revalidated with its ETag and not downloaded again
+	now = now.Add(cache.TTL)
+	if _, err := cache.Get(server.url("/logo.png")); err != nil {
+		t.Fatalf("Get() after the TTL error = %v", err)
+	}
+	if downloads := server.count(); downloads != 1 {
+		t.Errorf("the revalidated image is downloaded %d times, want 1", downloads)
+	}
+
+	// the last good copy is used, if the server fails or is unreachable
+	now = now.Add(cache.TTL)
+	server.setFail()
+	if gotData, err := cache.Get(server.url("/logo.png")); err != nil || !bytes.Equal(gotData, testPng(t)) {
+		t.Errorf("Get() of a failed server = %v, want the cached image", err)
+	}
+	server.Close()
+	if gotData, err := cache.Get(server.url("/logo.png")); err != nil || !bytes.Equal(gotData, testPng(t)) {
+		t.Errorf("Get() of an unreachable server = %v, want the cached image", err)
+	}
+
+	// without a cached copy the error is returned
+	if _, err := cache.Get(server.url("/other.png")); err == nil {
+		t.Errorf("Get() of an unreachable server without a cached image, want an error")
+	}
+}
+
+func TestImageCache_Get_errors(t *testing.T) {
+	server := newImageServer(t)
+
+	tests := []struct {
+		name         string
+		imageUrl     *url.URL
+		allowedHosts []string
+		private      bool
+		wantErr      bool
+		// wantBlocked is true, if the connection is rejected by the address check
+		wantBlocked bool
+	}{
+		{
+			name:         "allowed host",
+			imageUrl:     server.url("/logo.png"),
+			allowedHosts: []string{"example.com", "127.0.0.1"},
+			private:      true,
+			wantErr:      false,
+		},
+		{
+			name:        "loopback address",
+			imageUrl:    server.url("/logo.png"),
+			wantErr:     true,
+			wantBlocked: true,
+		},
+		{
+			name:         "allowed loopback address",
+			imageUrl:     server.url("/logo.png"),
+			allowedHosts: []string{"127.0.0.1"},
+			wantErr:      true,
+			wantBlocked:  true,
+		},
+		{
+			name:        "host name of a loopback address",
+			imageUrl:    &url.URL{Scheme: "http", Host: "localhost:" + server.url("").Port(), Path: "/logo.png"},
+			wantErr:     true,
+			wantBlocked: true,
+		},
+		{
+			name:        "link-local address",
+			imageUrl:    &url.URL{Scheme: "http", Host: "169.254.169.254", Path: "/latest/meta-data/"},
+			wantErr:     true,
+			wantBlocked: true,
+		},
+		{
+			name:        "private address",
+			imageUrl:    &url.URL{Scheme: "http", Host: "10.0.0.1", Path: "/logo.png"},
+			wantErr:     true,
+			wantBlocked: true,
+		},
+		{
+			name:         "host not allowed",
+			imageUrl:     server.url("/logo.png"),
+			allowedHosts: []string{"example.com"},
+			wantErr:      true,
+		},
+		{
+			name:         "other sub domains allowed",
+			imageUrl:     &url.URL{Scheme: "https", Host: "cdn.example.com", Path: "/logo.png"},
+			allowedHosts: []string{"*.other.com"},
+			wantErr:      true,
+		},
+		{
+			name:         "redirect to a host not allowed",
+			imageUrl:     server.url("/redirect.png"),
+			allowedHosts: []string{"127.0.0.1"},
+			private:      true,
+			wantErr:      true,
+		},
+		{
+			name:     "not found",
+			imageUrl: server.url("/missing.png"),
+			private:  true,
+			wantErr:  true,
+		},
+		{
+			name:     "no http url",
+			imageUrl: &url.URL{Scheme: "file", Path: "/etc/passwd"},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := NewImageCache()
+			cache.AllowedHosts = tt.allowedHosts
+			cache.AllowPrivateNetworks = tt.private
+			cache.Timeout = time.Second
+
+			_, err := cache.Get(tt.imageUrl)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotBlocked := errors.Is(err, errAddressNotAllowed); gotBlocked != tt.wantBlocked {
+				t.Errorf("Get() error = %v, want the address check %v", err, tt.wantBlocked)
+			}
+		})
+	}
+}
+
+func TestImageCache_store(t *testing.T) {
+	now := time.Now()
+	cache := NewImageCache()
+	cache.MaxBytes = 10
+	cache.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	cache.store("a", &cachedImage{data: make([]byte, 4)})
+	cache.store("b", &cachedImage{data: make([]byte, 4)})
+	cache.store("c", &cachedImage{data: make([]byte, 4)})
+	cache.store("d", &cachedImage{data: make([]byte, 11)})
+
+	if _, ok := cache.entries["a"]; ok {
+		t.Errorf("the least recently used image is not removed")
+	}
+	if _, ok := cache.entries["d"]; ok {
+		t.Errorf("an image greater than MaxBytes is cached")
+	}
+	if cache.size != 8 || len(cache.entries) != 2 {
+		t.Errorf("the cache has %d images with %d bytes, want 2 images with 8 bytes", len(cache.entries), cache.size)
+	}
+}
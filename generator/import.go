@@ -0,0 +1,81 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	errorsWithStack "github.com/go-errors/errors"
+	"github.com/jung-kurt/gofpdf/contrib/gofpdi"
+	"io"
+)
+
+// RegisterPdfPage imports the page pageNumber of the PDF pdfData, e.g. a letterhead,
+// to place it with PlaceRegisteredPdfPage() as background of a page.
+//
+// pageNameStr specifies the identifier of the imported page.
+//
+// pageNumber starts at 1.
+func (core *PDFGenerator) RegisterPdfPage(pageNameStr string, pdfData []byte, pageNumber int) {
+	if core.strictErrorHandling == true && core.pdf.Err() {
+		return
+	}
+
+	// --> validate inputs
+	if pageNumber < 1 {
+		core.pdf.SetError(errorsWithStack.New(fmt.Sprintf("The page number %d must be grater then 0.", pageNumber)))
+		return
+	}
+
+	if len(pdfData) == 0 {
+		core.pdf.SetError(errorsWithStack.New(fmt.Sprintf("No PDF to import the page \"%s\" from.", pageNameStr)))
+		return
+	}
+
+	if core.PdfPageIsRegistered(pageNameStr) {
+		core.pdf.SetError(errorsWithStack.New(fmt.Sprintf("The PDF page \"%s\" is already registered.", pageNameStr)))
+		return
+	}
+	// <--
+
+	// gofpdi panics, if the PDF can't be parsed or the page does not exist
+	defer func() {
+		if r := recover(); r != nil {
+			core.pdf.SetError(errorsWithStack.New(fmt.Sprintf("The page %d of the PDF \"%s\" can't be imported: %v", pageNumber, pageNameStr, r)))
+		}
+	}()
+
+	if core.pdfImporter == nil {
+		core.pdfImporter = gofpdi.NewImporter()
+	}
+
+	// gofpdi identifies the source by the address of the stream, so each stream is kept until the PDF is written
+	source := io.ReadSeeker(bytes.NewReader(pdfData))
+	core.pdfSources = append(core.pdfSources, &source)
+
+	core.registeredPdfPages[pageNameStr] = core.pdfImporter.ImportPageFromStream(core.pdf, &source, pageNumber, "/MediaBox")
+}
+
+// PlaceRegisteredPdfPage places a registered PDF page (see RegisterPdfPage) on the current page.
+// The imported page is scaled to the size of the current page. Place it first, so all other content is printed on top.
+//
+// The SVG and PNG backends do not show imported PDF pages.
+func (core *PDFGenerator) PlaceRegisteredPdfPage(pageNameStr string) {
+	if core.strictErrorHandling == true && core.pdf.Err() {
+		return
+	}
+
+	// --> validate inputs
+	if !core.PdfPageIsRegistered(pageNameStr) {
+		core.pdf.SetError(errorsWithStack.New(fmt.Sprintf("The PDF page \"%s\" is not registerd.", pageNameStr)))
+		return
+	}
+	// <--
+
+	pageWidth, pageHeight := core.GetPageSize()
+	core.pdfImporter.UseImportedTemplate(core.pdf, core.registeredPdfPages[pageNameStr], 0, 0, pageWidth, pageHeight)
+}
+
+// PdfPageIsRegistered returns true, if a PDF page with the identifier pageNameStr is registered (see RegisterPdfPage).
+func (core *PDFGenerator) PdfPageIsRegistered(pageNameStr string) bool {
+	_, ok := core.registeredPdfPages[pageNameStr]
+	return ok
+}
@@ -0,0 +1,112 @@
+package generator
+
+import (
+	"bytes"
+	"github.com/jung-kurt/gofpdf"
+	"testing"
+)
+
+// twoPagePdf returns a PDF with two pages, e.g. a letterhead with a follow-up page.
+func twoPagePdf(t *testing.T) []byte {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetFont("Arial", "", 12)
+	for _, text := range []string{"first page", "follow-up page"} {
+		pdf.AddPage()
+		pdf.Cell(40, 10, text)
+	}
+
+	var buffer bytes.Buffer
+	if err := pdf.Output(&buffer); err != nil {
+		t.Fatalf("create test pdf error\n%s", err.Error())
+	}
+	return buffer.Bytes()
+}
+
+func TestPDFGenerator_RegisterPdfPage(t *testing.T) {
+	pdfData := twoPagePdf(t)
+
+	type args struct {
+		pdfData    []byte
+		pageNumber int
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name:    "first page",
+			args:    args{pdfData: pdfData, pageNumber: 1},
+			wantErr: false,
+		},
+		{
+			name:    "second page",
+			args:    args{pdfData: pdfData, pageNumber: 2},
+			wantErr: false,
+		},
+		{
+			name:    "page does not exist",
+			args:    args{pdfData: pdfData, pageNumber: 3},
+			wantErr: true,
+		},
+		{
+			name:    "page number 0",
+			args:    args{pdfData: pdfData, pageNumber: 0},
+			wantErr: true,
+		},
+		{
+			name:    "no pdf",
+			args:    args{pdfData: nil, pageNumber: 1},
+			wantErr: true,
+		},
+		{
+			name:    "invalid pdf",
+			args:    args{pdfData: []byte("no pdf"), pageNumber: 1},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, err := NewPDFGenerator(_defaultMetaData, false, &_logger, func() {}, func(isLastPage bool) {})
+			if err != nil {
+				t.Errorf("init core error\n%s", err.Error())
+				return
+			}
+
+			core.RegisterPdfPage("letterhead", tt.args.pdfData, tt.args.pageNumber)
+
+			if gotErr := core.pdf.Err(); gotErr != tt.wantErr {
+				t.Errorf("RegisterPdfPage() set a error = %v, want %v", core.pdf.Error(), tt.wantErr)
+			}
+			if gotRegistered := core.PdfPageIsRegistered("letterhead"); gotRegistered == tt.wantErr {
+				t.Errorf("PdfPageIsRegistered() = %v, want %v", gotRegistered, !tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			core.NewPage()
+			core.PlaceRegisteredPdfPage("letterhead")
+
+			var buffer bytes.Buffer
+			if err = core.Output(&buffer); err != nil {
+				t.Errorf("Output() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestPDFGenerator_PlaceRegisteredPdfPage(t *testing.T) {
+	core, err := NewPDFGenerator(_defaultMetaData, false, &_logger, func() {}, func(isLastPage bool) {})
+	if err != nil {
+		t.Errorf("init core error\n%s", err.Error())
+		return
+	}
+
+	core.NewPage()
+	core.PlaceRegisteredPdfPage("not registered")
+
+	if !core.pdf.Err() {
+		t.Errorf("PlaceRegisteredPdfPage() set no error for a not registered page")
+	}
+}
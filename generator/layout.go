@@ -0,0 +1,532 @@
+package generator
+
+import (
+	"fmt"
+	errorsWithStack "github.com/go-errors/errors"
+	"github.com/rs/zerolog"
+	"strings"
+)
+
+// Box is a measurable and printable element of the layout tree.
+//
+// Boxes are first measured and then rendered by RenderBoxes,
+// which uses the measurements to decide about page breaks before anything is drawn.
+type Box interface {
+	// Measure returns the height of the box in the unit of measure specified in NewPDFGenerator(),
+	// if the box is rendered with the given width. Measure must not print anything.
+	Measure(core Generator, width float64) (height float64)
+	// Render prints the box with the top left corner at (x, y).
+	Render(core Generator, x float64, y float64, width float64)
+	// Constraints returns the page break constraints of the box.
+	Constraints() BoxConstraints
+}
+
+// splittableBox is a Box, which can be split in two parts on a page break.
+type splittableBox interface {
+	Box
+	// split returns a head, which fits in availableHeight, and the remaining tail.
+	// If nothing fits, head is nil.
+	split(core Generator, width float64, availableHeight float64) (head Box, tail Box)
+}
+
+// BoxConstraints defines how a Box behaves on page breaks.
+//
+// KeepTogether prevents the box from being split over two pages.
+// The whole box is moved to the next page if it does not fit on the current one.
+//
+// KeepWithNext places the box on the same page as the following box
+// (e.g. a headline and the first paragraph).
+type BoxConstraints struct {
+	KeepTogether bool
+	KeepWithNext bool
+}
+
+// Constraints returns the page break constraints of the box.
+func (c BoxConstraints) Constraints() BoxConstraints {
+	return c
+}
+
+// Frame defines the printable body area of the pages in the unit of measure specified in NewPDFGenerator().
+//
+// StartX and Width define the horizontal extent of all boxes.
+//
+// NextPageStartY defines the top of the body area on every page added by RenderBoxes.
+// The first box starts at the current cursor position.
+//
+// StopY defines the bottom of the body area. Boxes are never rendered below StopY,
+// except a KeepTogether box is higher than the whole body area.
+type Frame struct {
+	StartX         float64
+	Width          float64
+	NextPageStartY float64
+	StopY          float64
+}
+
+// RenderBoxes measures and prints all boxes one below the other, starting at the current cursor position.
+// New pages are added, if a box does not fit in the remaining space of the frame.
+// At the end, the cursor is set below the last box.
+func (core *PDFGenerator) RenderBoxes(frame Frame, boxes []Box) {
+	if core.strictErrorHandling == true && core.pdf.Err() {
+		return
+	}
+
+	renderBoxes(core, core.logger, frame, boxes)
+}
+
+// MeasureBoxes returns the overall height of all boxes placed one below the other with the given width.
+func (core *PDFGenerator) MeasureBoxes(boxes []Box, width float64) (height float64) {
+	return measureBoxes(core, boxes, width)
+}
+
+// renderBoxes implements RenderBoxes for every Generator.
+func renderBoxes(core Generator, logger *zerolog.Logger, frame Frame, boxes []Box) {
+	// --> validate inputs
+	if frame.Width <= 0 {
+		core.SetError(errorsWithStack.New(fmt.Sprintf("A negative or zero frame width is not allowed.")))
+		return
+	}
+
+	if frame.StopY <= frame.NextPageStartY {
+		core.SetError(errorsWithStack.New(fmt.Sprintf("The frame StopY (%f) must be grater then NextPageStartY (%f).", frame.StopY, frame.NextPageStartY)))
+		return
+	}
+	// <--
+
+	_, y := core.GetCursor()
+	maxPageHeight := frame.StopY - frame.NextPageStartY
+	boxes = append([]Box(nil), boxes...)
+
+	for i := 0; i < len(boxes); i++ {
+		box := boxes[i]
+
+		if _, ok := box.(PageBreakBox); ok {
+			core.NewPage()
+			y = frame.NextPageStartY
+			continue
+		}
+
+		height := box.Measure(core, frame.Width)
+
+		// sum up the height of all boxes, which must be placed on the same page
+		neededHeight := height
+		for j := i; j+1 < len(boxes) && boxes[j].Constraints().KeepWithNext; j++ {
+			neededHeight += boxes[j+1].Measure(core, frame.Width)
+		}
+		if neededHeight > maxPageHeight {
+			neededHeight = height
+		}
+
+		if y+neededHeight > frame.StopY {
+			if s, ok := box.(splittableBox); ok && !box.Constraints().KeepTogether && neededHeight == height {
+				head, tail := s.split(core, frame.Width, frame.StopY-y)
+
+				if head != nil {
+					head.Render(core, frame.StartX, y, frame.Width)
+					core.NewPage()
+					y = frame.NextPageStartY
+					boxes[i] = tail
+					i--
+					continue
+				}
+			}
+
+			// try again on a new page
+			if y > frame.NextPageStartY {
+				core.NewPage()
+				y = frame.NextPageStartY
+				i--
+				continue
+			}
+
+			logger.Warn().Msgf("box with height %.2f does not fit on one page (%.2f)", height, maxPageHeight)
+		}
+
+		box.Render(core, frame.StartX, y, frame.Width)
+		y += height
+	}
+
+	core.SetUnsafeCursor(frame.StartX, y)
+}
+
+func measureBoxes(core Generator, boxes []Box, width float64) (height float64) {
+	for _, box := range boxes {
+		height += box.Measure(core, width)
+	}
+	return height
+}
+
+// GetLineHeight returns the height of one text line in the unit of measure specified in NewPDFGenerator(),
+// including the gap between two lines.
+//
+// fontSize is measured in points and fontGapY in the unit of measure specified in NewPDFGenerator().
+func (core *PDFGenerator) GetLineHeight(fontSize float64, fontGapY float64) float64 {
+	return core.pdf.PointConvert(fontSize) + fontGapY
+}
+
+// SplitText splits a text on newline characters (\n) and wraps each line at the given width
+// using the font style styleStr and the font size fontSize in points.
+// Prefixing whitespaces are removed like in PrintLnPdfText().
+func (core *PDFGenerator) SplitText(text string, styleStr string, fontSize float64, width float64) (lines []string) {
+	if text == "" {
+		return nil
+	}
+
+	core.setFont(core.fontFamily, styleStr, fontSize)
+	for _, line := range core.extractLinesFromText(text) {
+		if line == "" {
+			lines = append(lines, "")
+			continue
+		}
+		lines = append(lines, core.splitLine(line, styleStr, width)...)
+	}
+	return lines
+}
+
+// syncFont sets the current font size as pdf font,
+// because the table functions use the size of the pdf font as line height.
+func (core *PDFGenerator) syncFont() {
+	core.setFont(core.fontFamily, "", core.GetFontSize())
+}
+
+// fontSyncer is implemented by generators, which must sync the font before printing a table.
+type fontSyncer interface {
+	syncFont()
+}
+
+// ParagraphBox is a text block, which is wrapped automatically at the box width.
+//
+// Use \n escape character to force a new line.
+// FontSize is measured in points and FontGapY in the unit of measure specified in NewPDFGenerator().
+// If FontSize is 0, the current font size of the generator is used.
+// StyleStr and AlignStr are used like in PrintPdfText().
+// If TextColor is nil, the current text color of the generator is used.
+type ParagraphBox struct {
+	BoxConstraints
+	Text      string
+	StyleStr  string
+	AlignStr  string
+	FontSize  float64
+	FontGapY  float64
+	TextColor *Color
+}
+
+func (p ParagraphBox) fontSize(core Generator) float64 {
+	if p.FontSize > 0 {
+		return p.FontSize
+	}
+	return core.GetFontSize()
+}
+
+func (p ParagraphBox) lines(core Generator, width float64) []string {
+	return core.SplitText(p.Text, p.StyleStr, p.fontSize(core), width)
+}
+
+// Measure returns the height of all wrapped text lines.
+func (p ParagraphBox) Measure(core Generator, width float64) (height float64) {
+	return float64(len(p.lines(core, width))) * core.GetLineHeight(p.fontSize(core), p.FontGapY)
+}
+
+// Render prints all wrapped text lines.
+func (p ParagraphBox) Render(core Generator, x float64, y float64, width float64) {
+	p.renderLines(core, p.lines(core, width), x, y, width)
+}
+
+func (p ParagraphBox) renderLines(core Generator, lines []string, x float64, y float64, width float64) {
+	fontSize := p.fontSize(core)
+	lineX := x
+	switch p.AlignStr {
+	case "R":
+		lineX = x + width
+	case "C":
+		lineX = x + width/2
+	}
+
+	if p.TextColor != nil {
+		textColor := core.GetTextColor()
+		core.SetTextColor(*p.TextColor)
+		defer core.SetTextColor(textColor)
+	}
+
+	core.SetFontSize(fontSize)
+	core.SetFontGapY(p.FontGapY)
+	for _, line := range lines {
+		core.SetUnsafeCursor(lineX, y)
+		if line != "" {
+			core.PrintPdfText(line, p.StyleStr, p.AlignStr)
+		}
+		y += core.GetLineHeight(fontSize, p.FontGapY)
+	}
+}
+
+func (p ParagraphBox) split(core Generator, width float64, availableHeight float64) (head Box, tail Box) {
+	lines := p.lines(core, width)
+	fitting := int(availableHeight / core.GetLineHeight(p.fontSize(core), p.FontGapY))
+	if fitting <= 0 {
+		return nil, p
+	}
+	if fitting >= len(lines) {
+		return p, SpacerBox{}
+	}
+
+	tailBox := p
+	tailBox.Text = strings.Join(lines[fitting:], "\n")
+	return paragraphLinesBox{paragraph: p, lines: lines[:fitting]}, tailBox
+}
+
+// paragraphLinesBox is the already wrapped head of a split ParagraphBox.
+type paragraphLinesBox struct {
+	BoxConstraints
+	paragraph ParagraphBox
+	lines     []string
+}
+
+func (p paragraphLinesBox) Measure(core Generator, _ float64) (height float64) {
+	return float64(len(p.lines)) * core.GetLineHeight(p.paragraph.fontSize(core), p.paragraph.FontGapY)
+}
+
+func (p paragraphLinesBox) Render(core Generator, x float64, y float64, width float64) {
+	p.paragraph.renderLines(core, p.lines, x, y, width)
+}
+
+// TableBox is a table printed with PrintTableHeader(), PrintTableBody() and PrintTableFooter().
+//
+// If the table is split over two pages, the header is repeated and the footer is printed on the last page only.
+// Header and Footer are optional. If FooterColumnWidths is nil, ColumnWidths is used for the footer.
+// FontSize is measured in points and FontGapY in the unit of measure specified in NewPDFGenerator().
+type TableBox struct {
+	BoxConstraints
+	Header             []string
+	Body               [][]string
+	Footer             [][]string
+	ColumnWidths       []float64
+	FooterColumnWidths []float64
+	HeaderAlign        []string
+	BodyAlign          []string
+	FooterAlign        []string
+	FontSize           float64
+	FontGapY           float64
+}
+
+func (t TableBox) rowHeight(core Generator) float64 {
+	fontSize := t.FontSize
+	if fontSize <= 0 {
+		fontSize = core.GetFontSize()
+	}
+	return core.GetLineHeight(fontSize, t.FontGapY*2)
+}
+
+func (t TableBox) bodyRowHeight(core Generator, row []string) float64 {
+	var maxLines = 0
+	for _, cell := range row {
+		if n := strings.Count(cell, "\n") + 1; n > maxLines {
+			maxLines = n
+		}
+	}
+	return float64(maxLines) * t.rowHeight(core)
+}
+
+func (t TableBox) headerHeight(core Generator) float64 {
+	if len(t.Header) == 0 {
+		return 0
+	}
+	return t.rowHeight(core)
+}
+
+// Measure returns the overall table height.
+func (t TableBox) Measure(core Generator, _ float64) (height float64) {
+	height = t.headerHeight(core)
+	for _, row := range t.Body {
+		height += t.bodyRowHeight(core, row)
+	}
+	height += float64(len(t.Footer)) * t.rowHeight(core)
+	return height
+}
+
+// Render prints header, body and footer of the table.
+func (t TableBox) Render(core Generator, x float64, y float64, _ float64) {
+	if t.FontSize > 0 {
+		core.SetFontSize(t.FontSize)
+	}
+	core.SetFontGapY(t.FontGapY)
+	if f, ok := core.(fontSyncer); ok {
+		f.syncFont()
+	}
+	core.SetUnsafeCursor(x, y)
+
+	if len(t.Header) > 0 {
+		core.PrintTableHeader(t.Header, t.ColumnWidths, t.HeaderAlign)
+	}
+	if len(t.Body) > 0 {
+		core.PrintTableBody(t.Body, t.ColumnWidths, t.BodyAlign)
+	}
+	if len(t.Footer) > 0 {
+		footerColumnWidths := t.FooterColumnWidths
+		if footerColumnWidths == nil {
+			footerColumnWidths = t.ColumnWidths
+		}
+		core.PrintTableFooter(t.Footer, footerColumnWidths, t.FooterAlign)
+	}
+}
+
+func (t TableBox) split(core Generator, _ float64, availableHeight float64) (head Box, tail Box) {
+	height := t.headerHeight(core)
+	fitting := 0
+	for _, row := range t.Body {
+		rowHeight := t.bodyRowHeight(core, row)
+		if height+rowHeight > availableHeight {
+			break
+		}
+		height += rowHeight
+		fitting++
+	}
+
+	if fitting == 0 {
+		return nil, t
+	}
+
+	headTable := t
+	headTable.Body = t.Body[:fitting]
+	headTable.Footer = nil
+
+	tailTable := t
+	tailTable.Body = t.Body[fitting:]
+	if len(tailTable.Body) == 0 {
+		tailTable.Header = nil
+	}
+	return headTable, tailTable
+}
+
+// ImageBox places an image registered with RegisterMimeImageToPdf().
+//
+// Scale is used like in PlaceRegisteredImageOnPage().
+// AlignStr defines the horizontal position inside the box:
+// "L" for the left side, "R" for the right side and "C" for the center.
+type ImageBox struct {
+	BoxConstraints
+	ImageNameStr string
+	Scale        float64
+	AlignStr     string
+}
+
+// Measure returns the scaled image height.
+func (i ImageBox) Measure(core Generator, _ float64) (height float64) {
+	if !core.ImageIsRegistered(i.ImageNameStr) {
+		return 0
+	}
+	_, height = core.GetRegisteredImageExtent(i.ImageNameStr)
+	return height * i.Scale
+}
+
+// Render places the image on the current page.
+func (i ImageBox) Render(core Generator, x float64, y float64, width float64) {
+	switch i.AlignStr {
+	case "R":
+		x += width
+	case "C":
+		x += width / 2
+	}
+	core.SetUnsafeCursor(x, y)
+	core.PlaceRegisteredImageOnPage(i.ImageNameStr, i.AlignStr, i.Scale)
+}
+
+// SpacerBox is an empty vertical space.
+type SpacerBox struct {
+	BoxConstraints
+	Height float64
+}
+
+// Measure returns the spacer height.
+func (s SpacerBox) Measure(_ Generator, _ float64) (height float64) {
+	return s.Height
+}
+
+// Render does nothing.
+func (s SpacerBox) Render(_ Generator, _ float64, _ float64, _ float64) {}
+
+// GroupBox combines several boxes placed one below the other.
+// Use KeepTogether to print all boxes on one page.
+type GroupBox struct {
+	BoxConstraints
+	Boxes []Box
+}
+
+// Measure returns the overall height of all child boxes.
+func (g GroupBox) Measure(core Generator, width float64) (height float64) {
+	return measureBoxes(core, g.Boxes, width)
+}
+
+// Render prints all child boxes.
+func (g GroupBox) Render(core Generator, x float64, y float64, width float64) {
+	for _, box := range g.Boxes {
+		box.Render(core, x, y, width)
+		y += box.Measure(core, width)
+	}
+}
+
+// split returns the fitting child boxes as head. The first child box, which does not fit,
+// is split too, so e.g. a long table at the start of a group does not overflow the page.
+func (g GroupBox) split(core Generator, width float64, availableHeight float64) (head Box, tail Box) {
+	var height float64
+	fitting := 0
+	for _, box := range g.Boxes {
+		boxHeight := box.Measure(core, width)
+		if height+boxHeight > availableHeight {
+			break
+		}
+		height += boxHeight
+		fitting++
+	}
+
+	headBoxes := append([]Box(nil), g.Boxes[:fitting]...)
+	tailBoxes := append([]Box(nil), g.Boxes[fitting:]...)
+
+	if len(tailBoxes) > 0 {
+		if s, ok := tailBoxes[0].(splittableBox); ok && !s.Constraints().KeepTogether {
+			if childHead, childTail := s.split(core, width, availableHeight-height); childHead != nil {
+				headBoxes = append(headBoxes, childHead)
+				tailBoxes[0] = childTail
+			}
+		}
+	}
+
+	if len(headBoxes) == 0 {
+		return nil, g
+	}
+
+	return GroupBox{Boxes: headBoxes}, GroupBox{BoxConstraints: g.BoxConstraints, Boxes: tailBoxes}
+}
+
+// CanvasBox is a box with a fixed height and custom drawing operations,
+// e.g. lines of a signature field.
+//
+// Draw is called with the top left corner and the width of the box.
+type CanvasBox struct {
+	BoxConstraints
+	Height float64
+	Draw   func(x float64, y float64, width float64)
+}
+
+// Measure returns the fixed height.
+func (c CanvasBox) Measure(_ Generator, _ float64) (height float64) {
+	return c.Height
+}
+
+// Render calls the custom draw function.
+func (c CanvasBox) Render(_ Generator, x float64, y float64, width float64) {
+	if c.Draw != nil {
+		c.Draw(x, y, width)
+	}
+}
+
+// PageBreakBox forces RenderBoxes to continue with the next box on a new page.
+type PageBreakBox struct {
+	BoxConstraints
+}
+
+// Measure returns always 0.
+func (p PageBreakBox) Measure(_ Generator, _ float64) (height float64) {
+	return 0
+}
+
+// Render does nothing, the page break is done by RenderBoxes.
+func (p PageBreakBox) Render(_ Generator, _ float64, _ float64, _ float64) {}
@@ -0,0 +1,216 @@
+package generator
+
+import (
+	"testing"
+)
+
+func TestPDFGenerator_RenderBoxes(t *testing.T) {
+	var frame = Frame{
+		StartX:         10,
+		Width:          100,
+		NextPageStartY: 10,
+		StopY:          100,
+	}
+
+	tests := []struct {
+		name      string
+		startY    float64
+		boxes     []Box
+		wantPages int
+		wantY     float64
+		wantErr   bool
+	}{
+		{
+			name:   "fits on one page",
+			startY: 10,
+			boxes: []Box{
+				SpacerBox{Height: 20},
+				SpacerBox{Height: 30},
+			},
+			wantPages: 1,
+			wantY:     60,
+		},
+		{
+			name:   "keep together moves group to next page",
+			startY: 80,
+			boxes: []Box{
+				GroupBox{
+					BoxConstraints: BoxConstraints{KeepTogether: true},
+					Boxes:          []Box{SpacerBox{Height: 15}, SpacerBox{Height: 15}},
+				},
+			},
+			wantPages: 2,
+			wantY:     40,
+		},
+		{
+			name:   "group without keep together is split",
+			startY: 80,
+			boxes: []Box{
+				GroupBox{Boxes: []Box{SpacerBox{Height: 15}, SpacerBox{Height: 15}}},
+			},
+			wantPages: 2,
+			wantY:     25,
+		},
+		{
+			name:   "keep with next moves both boxes to next page",
+			startY: 70,
+			boxes: []Box{
+				SpacerBox{BoxConstraints: BoxConstraints{KeepWithNext: true}, Height: 10},
+				CanvasBox{BoxConstraints: BoxConstraints{KeepTogether: true}, Height: 25},
+			},
+			wantPages: 2,
+			wantY:     45,
+		},
+		{
+			name:    "zero width",
+			startY:  10,
+			boxes:   []Box{SpacerBox{Height: 10}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, err := NewPDFGenerator(_defaultMetaData, false, &_logger, func() {}, func(isLastPage bool) {})
+			if err != nil {
+				t.Errorf("init core error\n%s", err.Error())
+				return
+			}
+			core.NewPage()
+			core.SetCursor(frame.StartX, tt.startY)
+
+			f := frame
+			if tt.wantErr {
+				f.Width = 0
+			}
+
+			core.RenderBoxes(f, tt.boxes)
+			if (core.GetError() != nil) != tt.wantErr {
+				t.Errorf("RenderBoxes() error = %v, wantErr %v", core.GetError(), tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got := core.GetTotalNumber(); got != tt.wantPages {
+				t.Errorf("RenderBoxes() pages = %v, want %v", got, tt.wantPages)
+			}
+
+			if _, gotY := core.GetCursor(); gotY != tt.wantY {
+				t.Errorf("RenderBoxes() y = %v, want %v", gotY, tt.wantY)
+			}
+		})
+	}
+}
+
+func TestTableBox_split(t *testing.T) {
+	core, err := NewPDFGenerator(_defaultMetaData, false, &_logger, func() {}, func(isLastPage bool) {})
+	if err != nil {
+		t.Errorf("init core error\n%s", err.Error())
+		return
+	}
+
+	table := TableBox{
+		Header:       []string{"a", "b"},
+		Body:         [][]string{{"1", "2"}, {"3", "4"}, {"5\n6", "7"}},
+		Footer:       [][]string{{"", "sum"}},
+		ColumnWidths: []float64{10, 10},
+		FontSize:     10,
+		FontGapY:     1,
+	}
+	rowHeight := table.rowHeight(core)
+
+	head, tail := table.split(core, 100, rowHeight*3.5)
+	if head == nil {
+		t.Errorf("split() head is nil")
+		return
+	}
+
+	if got := len(head.(TableBox).Body); got != 2 {
+		t.Errorf("split() head rows = %v, want 2", got)
+	}
+	if got := head.(TableBox).Footer; got != nil {
+		t.Errorf("split() head footer = %v, want nil", got)
+	}
+	if got := tail.(TableBox); len(got.Body) != 1 || len(got.Header) != 2 || len(got.Footer) != 1 {
+		t.Errorf("split() tail = %v, want one row with header and footer", got)
+	}
+	if got, want := table.Measure(core, 100), rowHeight*6; got != want {
+		t.Errorf("Measure() = %v, want %v", got, want)
+	}
+}
+
+func TestGroupBox_split(t *testing.T) {
+	core, err := NewPDFGenerator(_defaultMetaData, false, &_logger, func() {}, func(isLastPage bool) {})
+	if err != nil {
+		t.Errorf("init core error\n%s", err.Error())
+		return
+	}
+
+	table := TableBox{
+		Header:       []string{"a", "b"},
+		Body:         [][]string{{"1", "2"}, {"3", "4"}, {"5", "6"}, {"7", "8"}},
+		ColumnWidths: []float64{10, 10},
+		FontSize:     10,
+		FontGapY:     1,
+	}
+	rowHeight := table.rowHeight(core)
+
+	tests := []struct {
+		name          string
+		group         GroupBox
+		wantHead      bool
+		wantHeadBoxes int
+		wantTailBoxes int
+	}{
+		{
+			name:          "oversized first child is split",
+			group:         GroupBox{Boxes: []Box{table, SpacerBox{Height: 5}}},
+			wantHead:      true,
+			wantHeadBoxes: 1,
+			wantTailBoxes: 2,
+		},
+		{
+			name:          "child after the fitting boxes is split",
+			group:         GroupBox{Boxes: []Box{SpacerBox{Height: rowHeight}, table}},
+			wantHead:      true,
+			wantHeadBoxes: 2,
+			wantTailBoxes: 1,
+		},
+		{
+			name:     "keep together child is not split",
+			group:    GroupBox{Boxes: []Box{TableBox{BoxConstraints: BoxConstraints{KeepTogether: true}, Header: table.Header, Body: table.Body, ColumnWidths: table.ColumnWidths, FontSize: 10, FontGapY: 1}}},
+			wantHead: false,
+		},
+		{
+			name:     "not splittable child",
+			group:    GroupBox{Boxes: []Box{CanvasBox{Height: rowHeight * 10}}},
+			wantHead: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			head, tail := tt.group.split(core, 100, rowHeight*3.5)
+			if (head != nil) != tt.wantHead {
+				t.Fatalf("split() head = %v, want a head %v", head, tt.wantHead)
+			}
+			if !tt.wantHead {
+				return
+			}
+
+			if got := len(head.(GroupBox).Boxes); got != tt.wantHeadBoxes {
+				t.Errorf("split() head boxes = %v, want %v", got, tt.wantHeadBoxes)
+			}
+			if got := len(tail.(GroupBox).Boxes); got != tt.wantTailBoxes {
+				t.Errorf("split() tail boxes = %v, want %v", got, tt.wantTailBoxes)
+			}
+			if got := head.Measure(core, 100); got > rowHeight*3.5 {
+				t.Errorf("split() head height = %v, want at most %v", got, rowHeight*3.5)
+			}
+			if got, want := head.Measure(core, 100)+tail.Measure(core, 100), tt.group.Measure(core, 100); got < want {
+				t.Errorf("split() head and tail height = %v, want at least %v", got, want)
+			}
+		})
+	}
+}
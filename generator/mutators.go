@@ -4,6 +4,7 @@ import (
 	"fmt"
 	errorsWithStack "github.com/go-errors/errors"
 	"github.com/jung-kurt/gofpdf"
+	"io"
 )
 
 // GetPdf returns the full PDF.
@@ -13,6 +14,11 @@ func (core *PDFGenerator) GetPdf() *gofpdf.Fpdf {
 	return core.pdf
 }
 
+// Output writes the PDF to w and closes the document.
+func (core *PDFGenerator) Output(w io.Writer) error {
+	return core.pdf.Output(w)
+}
+
 // GetError returns the internal PDF error; this will be nil if no error has occurred.
 func (core *PDFGenerator) GetError() error {
 	return core.pdf.Error()
@@ -32,6 +38,11 @@ func (core *PDFGenerator) GetFontName() string {
 	return core.data.FontName
 }
 
+// GetPageSize returns the current page width and height in the unit of measure specified in NewPDFGenerator().
+func (core *PDFGenerator) GetPageSize() (width float64, height float64) {
+	return core.pdf.GetPageSize()
+}
+
 // GetMarginLeft returns the specified left margin in the unit of measure specified in NewPDFGenerator().
 func (core *PDFGenerator) GetMarginLeft() float64 {
 	return core.data.MarginLeft
@@ -94,6 +105,33 @@ func (core *PDFGenerator) SetFontSize(textSize float64) {
 	core.data.FontSize = textSize
 }
 
+// GetLineColor returns the color of the lines, i.e. the MetaData.DefaultLineColor.
+func (core *PDFGenerator) GetLineColor() Color {
+	r, g, b := core.pdf.GetDrawColor()
+	return Color{R: uint8(r), G: uint8(g), B: uint8(b)}
+}
+
+// GetLineWidth returns the width of the lines, i.e. the MetaData.DefaultLineWidth.
+func (core *PDFGenerator) GetLineWidth() float64 {
+	return core.pdf.GetLineWidth()
+}
+
+// GetTextColor returns the color of the printed texts.
+func (core *PDFGenerator) GetTextColor() Color {
+	r, g, b := core.pdf.GetTextColor()
+	return Color{R: uint8(r), G: uint8(g), B: uint8(b)}
+}
+
+// SetTextColor change the color of all following printed texts, e.g. to print a headline in the brand color.
+// The color is kept on the following pages.
+func (core *PDFGenerator) SetTextColor(color Color) {
+	if core.strictErrorHandling == true && core.pdf.Err() {
+		return
+	}
+
+	core.pdf.SetTextColor(int(color.R), int(color.G), int(color.B))
+}
+
 // GetCursor returns the abscissa (x) and ordinate (y) cursor point
 func (core *PDFGenerator) GetCursor() (x float64, y float64) {
 	return core.pdf.GetXY()
@@ -147,6 +185,9 @@ func (core *PDFGenerator) SetUnsafeCursor(x float64, y float64) {
 }
 
 func (core *PDFGenerator) GetRegisteredImageExtent(imageNameStr string) (w float64, h float64) {
+	if svg, ok := core.registeredSvgImages[imageNameStr]; ok {
+		return svg.extent(core.pdf.GetConversionRatio())
+	}
 	return core.pdf.GetImageInfo(imageNameStr).Extent()
 }
 
@@ -731,6 +731,43 @@ func TestPDFGenerator_SetFontSize(t *testing.T) {
 	}
 }
 
+func TestPDFGenerator_SetTextColor(t *testing.T) {
+	tests := []struct {
+		name  string
+		color Color
+	}{
+		{
+			name:  "brand color",
+			color: Color{R: 26, G: 77, B: 143},
+		},
+		{
+			name:  "black",
+			color: Color{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := _defaultMetaData
+			data.DefaultTextColor = Color{R: 34, G: 34, B: 34}
+
+			core, err := NewPDFGenerator(data, false, &_logger, func() {}, func(isLastPage bool) {})
+			if err != nil {
+				t.Errorf("init core error\n%s", err.Error())
+				return
+			}
+
+			if got := core.GetTextColor(); got != data.DefaultTextColor {
+				t.Errorf("GetTextColor() = %v, want the default text color %v", got, data.DefaultTextColor)
+			}
+
+			core.SetTextColor(tt.color)
+			if got := core.GetTextColor(); got != tt.color {
+				t.Errorf("GetTextColor() = %v, want %v", got, tt.color)
+			}
+		})
+	}
+}
+
 func TestPDFGenerator_SetUnsafeCursor(t *testing.T) {
 	type args struct {
 		x float64
@@ -0,0 +1,623 @@
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	errorsWithStack "github.com/go-errors/errors"
+	"github.com/rs/zerolog"
+	"image"
+	"image/color"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"math"
+)
+
+// MaxPreviewPixels limits the size of a PNG image, e.g. one DIN A3 page at 600 dpi or 20 DIN A4 pages at 150 dpi.
+// An image with 4 bytes per pixel uses up to 288 MB.
+const MaxPreviewPixels = 72000000
+
+// ErrPreviewTooLarge is returned by PNGGenerator.Output, if the image has more than MaxPreviewPixels pixels.
+var ErrPreviewTooLarge = errors.New("the preview image is too large")
+
+// PNGGenerator is a Generator, which renders the document as PNG preview image instead of PDF.
+//
+// Like the SVGGenerator, the layout is computed by an embedded PDFGenerator.
+// The drawn lines, cells, texts and images are rasterized with the registered TrueType fonts, see FontRegistry.
+type PNGGenerator struct {
+	*PDFGenerator
+	page int
+	dpi  float64
+}
+
+// NewPNGGenerator construct and return a new PNGGenerator instance.
+// The parameters data, strictErrorHandling, logger, headerFunction and footerFunction are used like in NewPDFGenerator().
+//
+// page specifies the page to render, starting at 1. Use 0 to render all pages one below the other.
+//
+// dpi specifies the resolution of the image in dots per inch. The value must be in range [10, 600].
+func NewPNGGenerator(data MetaData, strictErrorHandling bool, logger *zerolog.Logger, headerFunction func(), footerFunction func(isLastPage bool), page int, dpi float64) (gen *PNGGenerator, err error) {
+	// --> validate inputs
+	if page < 0 {
+		return nil, errorsWithStack.New(fmt.Sprintf("A negative page (%d) is not allowed.", page))
+	}
+	if dpi < 10 || dpi > 600 {
+		return nil, errorsWithStack.New(fmt.Sprintf("The dpi (%f) is out of range [10, 600].", dpi))
+	}
+	// <--
+
+	pdfGen, err := NewPDFGenerator(data, strictErrorHandling, logger, headerFunction, footerFunction)
+	if err != nil {
+		return nil, err
+	}
+
+	pdfGen.display = newDisplayList()
+
+	return &PNGGenerator{PDFGenerator: pdfGen, page: page, dpi: dpi}, nil
+}
+
+// NewPNGGeneratorBackend returns a NewGeneratorFunc, which creates a PNGGenerator for the given page and resolution.
+// See NewPNGGenerator() for the parameters.
+func NewPNGGeneratorBackend(page int, dpi float64) NewGeneratorFunc {
+	return func(data MetaData, strictErrorHandling bool, logger *zerolog.Logger, headerFunction func(), footerFunction func(isLastPage bool)) (Generator, error) {
+		gen, err := NewPNGGenerator(data, strictErrorHandling, logger, headerFunction, footerFunction, page, dpi)
+		if err != nil {
+			return nil, err
+		}
+		return gen, nil
+	}
+}
+
+// Output closes the document and writes the selected page or all pages as PNG image to w.
+func (pngGen *PNGGenerator) Output(w io.Writer) error {
+	if pngGen.pdf.PageCount() > 0 {
+		pngGen.pdf.Close()
+	}
+	if pngGen.pdf.Err() {
+		return pngGen.pdf.Error()
+	}
+
+	fromPage, toPage := 1, pngGen.pdf.PageCount()
+	if pngGen.page != 0 {
+		if pngGen.page > toPage {
+			return errorsWithStack.New(fmt.Sprintf("The page %d is out of range [1, %d].", pngGen.page, toPage))
+		}
+		fromPage, toPage = pngGen.page, pngGen.page
+	}
+
+	// the image is allocated at once, so the pages and the resolution are limited before
+	width, height := pngGen.imageSize(fromPage, toPage)
+	if width*height > MaxPreviewPixels {
+		return errorsWithStack.New(fmt.Errorf("%w: %d pages with %.0f dpi have %dx%d pixels, the limit is %d pixels, use a lower dpi or a single page",
+			ErrPreviewTooLarge, toPage-fromPage+1, pngGen.dpi, width, height, MaxPreviewPixels))
+	}
+
+	return png.Encode(w, pngGen.rasterize(fromPage, toPage))
+}
+
+// imageSize returns the size in pixel of the pages fromPage to toPage placed one below the other.
+func (pngGen *PNGGenerator) imageSize(fromPage int, toPage int) (width int, height int) {
+	pixelPerUnit := pngGen.dpi / 72. / pngGen.pdf.PointConvert(1)
+	extentWidth, extentHeight := pngGen.pagesExtent(fromPage, toPage)
+
+	return int(math.Ceil(extentWidth * pixelPerUnit)), int(math.Ceil(extentHeight * pixelPerUnit))
+}
+
+// rasterize draws the pages fromPage to toPage one below the other in a new image.
+func (pngGen *PNGGenerator) rasterize(fromPage int, toPage int) *image.RGBA {
+	pixelPerUnit := pngGen.dpi / 72. / pngGen.pdf.PointConvert(1)
+	pageWidth, pageHeight := pngGen.pdf.GetPageSize()
+	width, height := pngGen.imageSize(fromPage, toPage)
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	fillImage(img, img.Bounds(), color.RGBA{R: 128, G: 128, B: 128, A: 255})
+
+	images := map[string]image.Image{}
+	for page := fromPage; page <= toPage; page++ {
+		canvas := rasterCanvas{
+			img:     img,
+			scale:   pixelPerUnit,
+			offsetY: float64(page-fromPage) * (pageHeight + pngGen.pdf.PointConvert(previewPageGap)),
+		}
+		canvas.fillPolygon([][]rasterPoint{canvas.rect(0, 0, pageWidth, pageHeight)}, Color{R: 255, G: 255, B: 255})
+
+		for _, item := range pngGen.display.pages[page] {
+			canvas.transform = item.transform
+			switch item.kind {
+			case displayLine:
+				canvas.strokeOutline([]Point{{X: item.x, Y: item.y}, {X: item.x2, Y: item.y2}}, false, item.lineWidth, item.lineColor, item.dash)
+			case displayShape:
+				if item.fill {
+					polygon := make([]rasterPoint, len(item.points))
+					for i, point := range item.points {
+						polygon[i] = canvas.point(point.X, point.Y)
+					}
+					canvas.fillPolygon([][]rasterPoint{polygon}, item.fillColor)
+				}
+				if item.stroke {
+					canvas.strokeOutline(item.points, item.closed, item.lineWidth, item.lineColor, item.dash)
+				}
+			case displayRect:
+				if item.fill {
+					canvas.fillPolygon([][]rasterPoint{canvas.rect(item.x, item.y, item.w, item.h)}, item.fillColor)
+				}
+				if item.stroke {
+					canvas.strokeLine(item.x, item.y, item.x+item.w, item.y, item.lineWidth, item.lineColor)
+					canvas.strokeLine(item.x+item.w, item.y, item.x+item.w, item.y+item.h, item.lineWidth, item.lineColor)
+					canvas.strokeLine(item.x+item.w, item.y+item.h, item.x, item.y+item.h, item.lineWidth, item.lineColor)
+					canvas.strokeLine(item.x, item.y+item.h, item.x, item.y, item.lineWidth, item.lineColor)
+				}
+			case displayText:
+				font, err := pngGen.previewFont(item.fontName, item.styleStr)
+				if err != nil {
+					pngGen.logger.Warn().Err(err).Msg("Can not load the preview font, the text is skipped.")
+					continue
+				}
+				if err = canvas.drawText(font, item, item.textColor); err != nil {
+					pngGen.logger.Warn().Err(err).Msg("Can not draw the glyphs of the font, the text is skipped.")
+				}
+			case displayImage:
+				if svg := pngGen.display.images[item.imageName].svg; svg != nil {
+					canvas.drawSvgImage(svg, item.x, item.y, item.w, item.h)
+					continue
+				}
+				src, ok := images[item.imageName]
+				if !ok {
+					data := pngGen.display.images[item.imageName]
+					decoded, _, err := image.Decode(bytes.NewReader(data.data))
+					if err != nil {
+						pngGen.logger.Warn().Err(err).Msg("Can not decode the image, the image is skipped.")
+					}
+					images[item.imageName] = decoded
+					src = decoded
+				}
+				if src != nil {
+					canvas.drawImage(src, item.x, item.y, item.w, item.h)
+				}
+			}
+		}
+	}
+
+	return img
+}
+
+// previewFont returns the registered font matching the font name and style.
+// The standard PDF fonts (e.g. "Helvetica") are approximated by OpenSans in the preview.
+func (core *PDFGenerator) previewFont(fontName string, styleStr string) (*trueTypeFont, error) {
+	family, err := core.fonts.family(fontName)
+	if err == nil && family == nil {
+		family, err = core.fonts.family("OpenSans")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if family == nil {
+		return nil, errorsWithStack.New(fmt.Sprintf("The font %s and the preview font OpenSans are not registered.", fontName))
+	}
+
+	style, _ := ParseFontStyle(styleStr)
+	return family.fonts[family.face(style)], nil
+}
+
+// rasterPoint is a point in pixel.
+type rasterPoint struct {
+	x float64
+	y float64
+}
+
+// rasterCanvas draws anti-aliased shapes in the unit of measure of the PDFGenerator onto an image.
+// The shapes of a watermark are rotated and blended with the opacity of its transform.
+type rasterCanvas struct {
+	img       *image.RGBA
+	scale     float64
+	offsetY   float64
+	transform *displayTransform
+}
+
+// point converts a position of the page into a pixel position.
+func (canvas rasterCanvas) point(x float64, y float64) rasterPoint {
+	x, y = canvas.transform.apply(x, y)
+	return rasterPoint{x: x * canvas.scale, y: (y + canvas.offsetY) * canvas.scale}
+}
+
+func (canvas rasterCanvas) rect(x float64, y float64, w float64, h float64) []rasterPoint {
+	return []rasterPoint{canvas.point(x, y), canvas.point(x+w, y), canvas.point(x+w, y+h), canvas.point(x, y+h)}
+}
+
+// strokeLine draws a line as quad with the given line width.
+func (canvas rasterCanvas) strokeLine(x1 float64, y1 float64, x2 float64, y2 float64, lineWidth float64, c Color) {
+	length := math.Hypot(x2-x1, y2-y1)
+	if length == 0 {
+		return
+	}
+
+	// draw lines at least one pixel wide, to keep thin lines visible in small previews
+	halfWidth := math.Max(lineWidth, 1/canvas.scale) / 2
+	nx, ny := -(y2-y1)/length*halfWidth, (x2-x1)/length*halfWidth
+
+	canvas.fillPolygon([][]rasterPoint{{
+		canvas.point(x1+nx, y1+ny),
+		canvas.point(x2+nx, y2+ny),
+		canvas.point(x2-nx, y2-ny),
+		canvas.point(x1-nx, y1-ny),
+	}}, c)
+}
+
+// strokeOutline draws the edges between the points, a dashed line is split into its dashes (see dashPattern).
+func (canvas rasterCanvas) strokeOutline(points []Point, closed bool, lineWidth float64, c Color, dash string) {
+	if closed {
+		points = append(points[:len(points):len(points)], points[0])
+	}
+
+	pattern := dashPattern(dash, lineWidth)
+	if pattern == nil {
+		for i := 1; i < len(points); i++ {
+			canvas.strokeLine(points[i-1].X, points[i-1].Y, points[i].X, points[i].Y, lineWidth, c)
+		}
+		return
+	}
+
+	// a dot is drawn as a dash of the line width, the dashes continue across the corners
+	period := pattern[0] + pattern[1]
+	dashLength := math.Max(pattern[0], lineWidth)
+	position := 0.
+	for i := 1; i < len(points); i++ {
+		start, end := points[i-1], points[i]
+		length := math.Hypot(end.X-start.X, end.Y-start.Y)
+		for t := 0.; t < length; {
+			phase := math.Mod(position, period)
+			next := math.Min(length, t+period-phase)
+			if phase < dashLength {
+				next = math.Min(length, t+dashLength-phase)
+				canvas.strokeLine(start.X+(end.X-start.X)*t/length, start.Y+(end.Y-start.Y)*t/length,
+					start.X+(end.X-start.X)*next/length, start.Y+(end.Y-start.Y)*next/length, lineWidth, c)
+			}
+			position += next - t
+			t = next
+		}
+	}
+}
+
+// drawText draws the glyphs of a text item. The glyph advances are stretched to the text width computed by gofpdf.
+// Nothing is drawn, if a glyph of the text can't be parsed, see glyphContours.
+func (canvas rasterCanvas) drawText(font *trueTypeFont, item displayItem, c Color) error {
+	var naturalWidth float64
+	glyphs := make([]int, 0, len(item.text))
+	for _, r := range item.text {
+		glyph := font.glyphIndex(r)
+		glyphs = append(glyphs, glyph)
+		naturalWidth += font.advanceWidth(glyph)
+	}
+	if naturalWidth == 0 {
+		return nil
+	}
+
+	fontScale := item.fontSize / font.unitsPerEm
+	advanceScale := fontScale
+	if item.w > 0 {
+		advanceScale = item.w / naturalWidth
+	}
+
+	var contours [][]rasterPoint
+	x := item.x
+	for _, glyph := range glyphs {
+		glyphContours, err := font.glyphContours(glyph)
+		if err != nil {
+			return err
+		}
+		for _, contour := range glyphContours {
+			contours = append(contours, canvas.flattenContour(contour, x, item.y, fontScale))
+		}
+		x += font.advanceWidth(glyph) * advanceScale
+	}
+
+	canvas.fillPolygon(contours, c)
+	return nil
+}
+
+// flattenContour converts a quadratic TrueType contour into a polygon in pixel.
+func (canvas rasterCanvas) flattenContour(contour []fontPoint, originX float64, originY float64, fontScale float64) (polygon []rasterPoint) {
+	if len(contour) == 0 {
+		return nil
+	}
+
+	toPixel := func(p fontPoint) rasterPoint {
+		return canvas.point(originX+p.x*fontScale, originY-p.y*fontScale)
+	}
+	midpoint := func(a, b fontPoint) fontPoint {
+		return fontPoint{x: (a.x + b.x) / 2, y: (a.y + b.y) / 2, onCurve: true}
+	}
+
+	// find an on curve start point
+	start := contour[0]
+	if !start.onCurve {
+		last := contour[len(contour)-1]
+		if last.onCurve {
+			start = last
+		} else {
+			start = midpoint(last, start)
+		}
+	}
+
+	const curveSteps = 6
+	current := start
+	polygon = append(polygon, toPixel(start))
+	var control *fontPoint
+	for i := 0; i <= len(contour); i++ {
+		p := start
+		if i < len(contour) {
+			p = contour[i]
+		}
+
+		if !p.onCurve {
+			if control != nil {
+				mid := midpoint(*control, p)
+				polygon = appendQuadratic(polygon, toPixel(current), toPixel(*control), toPixel(mid), curveSteps)
+				current = mid
+			}
+			cp := p
+			control = &cp
+			continue
+		}
+
+		if control != nil {
+			polygon = appendQuadratic(polygon, toPixel(current), toPixel(*control), toPixel(p), curveSteps)
+			control = nil
+		} else {
+			polygon = append(polygon, toPixel(p))
+		}
+		current = p
+	}
+
+	return polygon
+}
+
+func appendQuadratic(polygon []rasterPoint, p0 rasterPoint, p1 rasterPoint, p2 rasterPoint, steps int) []rasterPoint {
+	for i := 1; i <= steps; i++ {
+		t := float64(i) / float64(steps)
+		mt := 1 - t
+		polygon = append(polygon, rasterPoint{
+			x: mt*mt*p0.x + 2*mt*t*p1.x + t*t*p2.x,
+			y: mt*mt*p0.y + 2*mt*t*p1.y + t*t*p2.y,
+		})
+	}
+	return polygon
+}
+
+// drawImage draws src scaled into the rectangle with nearest neighbour sampling.
+func (canvas rasterCanvas) drawImage(src image.Image, x float64, y float64, w float64, h float64) {
+	if canvas.transform != nil && canvas.transform.angle != 0 {
+		canvas.drawRotatedImage(src, x, y, w, h)
+		return
+	}
+
+	topLeft, bottomRight := canvas.point(x, y), canvas.point(x+w, y+h)
+	target := image.Rect(int(math.Round(topLeft.x)), int(math.Round(topLeft.y)), int(math.Round(bottomRight.x)), int(math.Round(bottomRight.y)))
+	clipped := target.Intersect(canvas.img.Bounds())
+	if clipped.Empty() {
+		return
+	}
+
+	srcBounds := src.Bounds()
+	for py := clipped.Min.Y; py < clipped.Max.Y; py++ {
+		sy := srcBounds.Min.Y + (py-target.Min.Y)*srcBounds.Dy()/target.Dy()
+		for px := clipped.Min.X; px < clipped.Max.X; px++ {
+			sx := srcBounds.Min.X + (px-target.Min.X)*srcBounds.Dx()/target.Dx()
+			r, g, b, a := src.At(sx, sy).RGBA()
+			if a == 0 {
+				continue
+			}
+			coverage := float64(a) / 0xFFFF
+			// the color channels are alpha-premultiplied
+			canvas.blend(px, py, Color{R: uint8(float64(r>>8) / coverage), G: uint8(float64(g>>8) / coverage), B: uint8(float64(b>>8) / coverage)}, coverage)
+		}
+	}
+}
+
+// drawRotatedImage draws src scaled into the rotated rectangle.
+// Each pixel of the rotated bounding box is rotated back onto the image to sample its color.
+func (canvas rasterCanvas) drawRotatedImage(src image.Image, x float64, y float64, w float64, h float64) {
+	corners := canvas.rect(x, y, w, h)
+	minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for _, p := range corners {
+		minX, minY = math.Min(minX, p.x), math.Min(minY, p.y)
+		maxX, maxY = math.Max(maxX, p.x), math.Max(maxY, p.y)
+	}
+	area := image.Rect(int(math.Floor(minX)), int(math.Floor(minY)), int(math.Ceil(maxX)), int(math.Ceil(maxY))).Intersect(canvas.img.Bounds())
+
+	inverse := &displayTransform{angle: -canvas.transform.angle, cx: canvas.transform.cx, cy: canvas.transform.cy}
+	srcBounds := src.Bounds()
+	for py := area.Min.Y; py < area.Max.Y; py++ {
+		for px := area.Min.X; px < area.Max.X; px++ {
+			pageX, pageY := inverse.apply((float64(px)+.5)/canvas.scale, (float64(py)+.5)/canvas.scale-canvas.offsetY)
+			if pageX < x || pageX >= x+w || pageY < y || pageY >= y+h {
+				continue
+			}
+			sx := srcBounds.Min.X + int((pageX-x)/w*float64(srcBounds.Dx()))
+			sy := srcBounds.Min.Y + int((pageY-y)/h*float64(srcBounds.Dy()))
+			r, g, b, a := src.At(sx, sy).RGBA()
+			if a == 0 {
+				continue
+			}
+			coverage := float64(a) / 0xFFFF
+			// the color channels are alpha-premultiplied
+			canvas.blend(px, py, Color{R: uint8(float64(r>>8) / coverage), G: uint8(float64(g>>8) / coverage), B: uint8(float64(b>>8) / coverage)}, coverage)
+		}
+	}
+}
+
+// drawSvgImage draws the shapes of an svg image scaled into the rectangle.
+// The fills use the non-zero winding rule and the opacities are ignored in the preview.
+func (canvas rasterCanvas) drawSvgImage(svg *svgImage, x float64, y float64, w float64, h float64) {
+	strokeScale := math.Sqrt(w / svg.width * h / svg.height)
+	for _, shape := range svg.shapes {
+		polygons := svg.flatten(shape.subpaths, x, y, w, h)
+
+		if shape.fill != nil {
+			rasterPolygons := make([][]rasterPoint, len(polygons))
+			for i, polygon := range polygons {
+				for _, p := range polygon {
+					rasterPolygons[i] = append(rasterPolygons[i], canvas.point(p.x, p.y))
+				}
+			}
+			canvas.fillPolygon(rasterPolygons, *shape.fill)
+		}
+
+		if shape.stroke != nil {
+			for i, polygon := range polygons {
+				for j := 1; j < len(polygon); j++ {
+					canvas.strokeLine(polygon[j-1].x, polygon[j-1].y, polygon[j].x, polygon[j].y, shape.strokeWidth*strokeScale, *shape.stroke)
+				}
+				if shape.subpaths[i].closed {
+					last := polygon[len(polygon)-1]
+					canvas.strokeLine(last.x, last.y, polygon[0].x, polygon[0].y, shape.strokeWidth*strokeScale, *shape.stroke)
+				}
+			}
+		}
+	}
+}
+
+// blend mixes the color c with the given coverage into the pixel at px, py.
+func (canvas rasterCanvas) blend(px int, py int, c Color, coverage float64) {
+	if canvas.transform != nil {
+		coverage *= canvas.transform.opacity
+	}
+	i := canvas.img.PixOffset(px, py)
+	pix := canvas.img.Pix[i : i+4 : i+4]
+	pix[0] = uint8(float64(pix[0])*(1-coverage) + float64(c.R)*coverage + .5)
+	pix[1] = uint8(float64(pix[1])*(1-coverage) + float64(c.G)*coverage + .5)
+	pix[2] = uint8(float64(pix[2])*(1-coverage) + float64(c.B)*coverage + .5)
+	pix[3] = 255
+}
+
+// fillPolygon fills the polygons with the non-zero winding rule and anti-aliased edges.
+//
+// The signed area coverage of each edge is accumulated per pixel and summed up per row,
+// like in the font-rs and stb_truetype rasterizers.
+func (canvas rasterCanvas) fillPolygon(polygons [][]rasterPoint, c Color) {
+	minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for _, polygon := range polygons {
+		for _, p := range polygon {
+			minX, minY = math.Min(minX, p.x), math.Min(minY, p.y)
+			maxX, maxY = math.Max(maxX, p.x), math.Max(maxY, p.y)
+		}
+	}
+
+	bounds := canvas.img.Bounds()
+	area := image.Rect(int(math.Floor(minX)), int(math.Floor(minY)), int(math.Ceil(maxX)), int(math.Ceil(maxY))).Intersect(bounds)
+	if area.Empty() {
+		return
+	}
+
+	// the origin of the accumulation buffer is the left side of the polygons, so no edge is left of the buffer
+	originX := math.Floor(minX)
+	width := int(math.Ceil(maxX)-originX) + 2
+	height := area.Dy()
+	accumulation := make([]float64, width*height)
+
+	for _, polygon := range polygons {
+		for i := range polygon {
+			p0, p1 := polygon[i], polygon[(i+1)%len(polygon)]
+			accumulateEdge(accumulation, width, height,
+				rasterPoint{x: p0.x - originX, y: p0.y - float64(area.Min.Y)},
+				rasterPoint{x: p1.x - originX, y: p1.y - float64(area.Min.Y)})
+		}
+	}
+
+	for row := 0; row < height; row++ {
+		var sum float64
+		for column := 0; column < width; column++ {
+			sum += accumulation[row*width+column]
+			px := int(originX) + column
+			if px < area.Min.X || px >= area.Max.X {
+				continue
+			}
+			coverage := math.Min(math.Abs(sum), 1)
+			if coverage > 0.001 {
+				canvas.blend(px, area.Min.Y+row, c, coverage)
+			}
+		}
+	}
+}
+
+// accumulateEdge adds the signed area of an edge to the accumulation buffer.
+func accumulateEdge(accumulation []float64, width int, height int, p0 rasterPoint, p1 rasterPoint) {
+	if p0.y == p1.y {
+		return
+	}
+
+	direction := 1.
+	if p0.y > p1.y {
+		direction = -1
+		p0, p1 = p1, p0
+	}
+
+	dxdy := (p1.x - p0.x) / (p1.y - p0.y)
+	x := p0.x
+	if p0.y < 0 {
+		x -= p0.y * dxdy
+	}
+
+	add := func(row int, column int, value float64) {
+		if column < 0 {
+			column = 0
+		}
+		if column >= width {
+			return
+		}
+		accumulation[row*width+column] += value
+	}
+
+	for row := int(math.Max(0, math.Floor(p0.y))); row < height && float64(row) < p1.y; row++ {
+		dy := math.Min(float64(row+1), p1.y) - math.Max(float64(row), p0.y)
+		xNext := x + dxdy*dy
+		d := dy * direction
+
+		x0, x1 := x, xNext
+		if x0 > x1 {
+			x0, x1 = x1, x0
+		}
+		x0Floor := math.Floor(x0)
+		x0i := int(x0Floor)
+		x1Ceil := math.Ceil(x1)
+		x1i := int(x1Ceil)
+
+		if x1i <= x0i+1 {
+			xm := .5*(x+xNext) - x0Floor
+			add(row, x0i, d-d*xm)
+			add(row, x0i+1, d*xm)
+		} else {
+			s := 1 / (x1 - x0)
+			x0f := x0 - x0Floor
+			a0 := .5 * s * (1 - x0f) * (1 - x0f)
+			x1f := x1 - x1Ceil + 1
+			am := .5 * s * x1f * x1f
+			add(row, x0i, d*a0)
+			if x1i == x0i+2 {
+				add(row, x0i+1, d*(1-a0-am))
+			} else {
+				a1 := s * (1.5 - x0f)
+				add(row, x0i+1, d*(a1-a0))
+				for column := x0i + 2; column < x1i-1; column++ {
+					add(row, column, d*s)
+				}
+				a2 := a1 + float64(x1i-x0i-3)*s
+				add(row, x1i-1, d*(1-a2-am))
+			}
+			add(row, x1i, d*am)
+		}
+
+		x = xNext
+	}
+}
+
+// fillImage fills the rectangle r of img with a solid color.
+func fillImage(img *image.RGBA, r image.Rectangle, c color.RGBA) {
+	for py := r.Min.Y; py < r.Max.Y; py++ {
+		for px := r.Min.X; px < r.Max.X; px++ {
+			img.SetRGBA(px, py, c)
+		}
+	}
+}
@@ -0,0 +1,842 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	errorsWithStack "github.com/go-errors/errors"
+	"github.com/rs/zerolog"
+	"io"
+	"math"
+	"net/url"
+	"strings"
+)
+
+// RecordingGenerator is a Generator, which does not create any document,
+// but records every draw operation with the current cursor position.
+// Use it to test layouts without parsing a PDF.
+//
+// The cursor is moved like in PDFGenerator.
+// Text widths are approximated with an average character width of half the font size,
+// so measurements are deterministic but not equal to the PDF output.
+type RecordingGenerator struct {
+	data                 MetaData
+	pageWidth            float64
+	pageHeight           float64
+	maxSaveX             float64
+	maxSaveY             float64
+	strictErrorHandling  bool
+	logger               *zerolog.Logger
+	headerFunction       func()
+	footerFunction       func(isLastPage bool)
+	err                  error
+	x                    float64
+	y                    float64
+	currentPage          int
+	totalPages           int
+	closed               bool
+	registeredImages     map[string]bool
+	registeredPdfPages   map[string]bool
+	imageExtents         map[string][2]float64
+	defaultImageExtent   [2]float64
+	operations           []Operation
+	unitsPerPoint        float64
+	averageCharWidthRate float64
+	textColor            Color
+}
+
+// Operation is one recorded draw operation of a RecordingGenerator.
+//
+// Name is the name of the called Generator method, e.g. "PrintPdfText" or "DrawLine".
+// Page, X and Y define the page and the cursor position before the operation.
+// Values contains all numeric arguments (e.g. the points of a line or the cell size),
+// Cells the table content.
+type Operation struct {
+	Name     string     `json:"name"`
+	Page     int        `json:"page"`
+	X        float64    `json:"x"`
+	Y        float64    `json:"y"`
+	Text     string     `json:"text,omitempty"`
+	StyleStr string     `json:"style,omitempty"`
+	AlignStr string     `json:"align,omitempty"`
+	FontSize float64    `json:"fontSize,omitempty"`
+	Values   []float64  `json:"values,omitempty"`
+	Cells    [][]string `json:"cells,omitempty"`
+}
+
+// NewRecordingGenerator construct and return a new RecordingGenerator instance.
+// The parameters are used like in NewPDFGenerator(). The page size is DIN A4, if data.PageSize is not set.
+func NewRecordingGenerator(data MetaData, strictErrorHandling bool, logger *zerolog.Logger, headerFunction func(), footerFunction func(isLastPage bool)) (gen *RecordingGenerator, err error) {
+	// --> validate inputs
+	if data.FontGapY < 0 {
+		return nil, errorsWithStack.New(fmt.Sprintf("A negative FontGapY (%f) is not allowed.", data.FontGapY))
+	}
+	if data.FontSize <= 0 {
+		return nil, errorsWithStack.New(fmt.Sprintf("Text size must be grather or equal then 0."))
+	}
+
+	unitsPerPoint := map[string]float64{"pt": 1, "mm": 25.4 / 72, "cm": 2.54 / 72, "in": 1. / 72}
+	if _, ok := unitsPerPoint[data.Unit]; !ok {
+		return nil, errorsWithStack.New(fmt.Sprintf("The Unit must be pt, mm, cm or in."))
+	}
+
+	if data.MarginLeft < 0 || data.MarginTop < 0 || data.MarginRight < 0 || data.MarginBottom < 0 {
+		return nil, errorsWithStack.New(fmt.Sprintf("Negative margins are not allowed."))
+	}
+
+	if data.PageSize.Width < 0 || data.PageSize.Height < 0 {
+		return nil, errorsWithStack.New(fmt.Sprintf("A negative PageSize (%f x %f) is not allowed.", data.PageSize.Width, data.PageSize.Height))
+	}
+
+	if data.Orientation != "" && data.Orientation != "P" && data.Orientation != "L" {
+		return nil, errorsWithStack.New(fmt.Sprintf("The Orientation must be P or L."))
+	}
+
+	if border := data.TableStyle.Border; border != "" && border != "horizontal" && border != "grid" && border != "none" {
+		return nil, errorsWithStack.New(fmt.Sprintf("The table border \"%s\" must be horizontal, grid or none.", border))
+	}
+	// <--
+
+	gen = new(RecordingGenerator)
+	gen.data = data
+	gen.strictErrorHandling = strictErrorHandling
+	gen.logger = logger
+	gen.headerFunction = headerFunction
+	gen.footerFunction = footerFunction
+	gen.unitsPerPoint = unitsPerPoint[data.Unit]
+	gen.averageCharWidthRate = 0.5
+	gen.pageWidth = 595.28 * gen.unitsPerPoint
+	gen.pageHeight = 841.89 * gen.unitsPerPoint
+	if data.PageSize.Width > 0 && data.PageSize.Height > 0 {
+		gen.pageWidth = data.PageSize.Width
+		gen.pageHeight = data.PageSize.Height
+	}
+	if data.Orientation == "L" {
+		gen.pageWidth, gen.pageHeight = gen.pageHeight, gen.pageWidth
+	}
+	gen.maxSaveX = gen.pageWidth - data.MarginRight
+	gen.maxSaveY = gen.pageHeight - data.MarginBottom
+	gen.registeredImages = map[string]bool{}
+	gen.registeredPdfPages = map[string]bool{}
+	gen.imageExtents = map[string][2]float64{}
+	gen.defaultImageExtent = [2]float64{40, 20}
+	gen.x = data.MarginLeft
+	gen.y = data.MarginTop
+	gen.textColor = data.DefaultTextColor
+
+	return gen, nil
+}
+
+// NewRecordingGeneratorBackend is a NewGeneratorFunc, which returns a RecordingGenerator.
+func NewRecordingGeneratorBackend(data MetaData, strictErrorHandling bool, logger *zerolog.Logger, headerFunction func(), footerFunction func(isLastPage bool)) (Generator, error) {
+	gen, err := NewRecordingGenerator(data, strictErrorHandling, logger, headerFunction, footerFunction)
+	if err != nil {
+		return nil, err
+	}
+	return gen, nil
+}
+
+// Operations returns all recorded operations.
+func (rec *RecordingGenerator) Operations() []Operation {
+	return rec.operations
+}
+
+// OperationsByName returns all recorded operations with the given method name.
+func (rec *RecordingGenerator) OperationsByName(name string) (operations []Operation) {
+	for _, operation := range rec.operations {
+		if operation.Name == name {
+			operations = append(operations, operation)
+		}
+	}
+	return operations
+}
+
+// SetImageExtent defines the extent of an image, which is registered afterwards with RegisterMimeImageToPdf().
+// If no extent is defined, an extent of 40 x 20 in the unit of measure is used.
+func (rec *RecordingGenerator) SetImageExtent(imageNameStr string, w float64, h float64) {
+	rec.imageExtents[imageNameStr] = [2]float64{w, h}
+}
+
+func (rec *RecordingGenerator) record(operation Operation) {
+	operation.Page = rec.currentPage
+	operation.X = rec.x
+	operation.Y = rec.y
+	rec.operations = append(rec.operations, operation)
+}
+
+func (rec *RecordingGenerator) skip() bool {
+	return rec.strictErrorHandling && rec.err != nil
+}
+
+func (rec *RecordingGenerator) PrintPdfText(text string, styleStr string, alignStr string) {
+	if rec.skip() {
+		return
+	}
+
+	valideAlignStrs := map[string]bool{"L": true, "R": true, "C": true}
+	if !valideAlignStrs[alignStr] {
+		rec.err = errorsWithStack.New(fmt.Sprintf("\"%s\" is not a valid alignStr of \"L\", \"R\" or \"C\".", alignStr))
+		return
+	}
+
+	if _, err := ParseFontStyle(styleStr); err != nil {
+		rec.err = errorsWithStack.New(err.Error())
+		return
+	}
+
+	if len(text) == 0 {
+		return
+	}
+
+	rec.record(Operation{Name: "PrintPdfText", Text: text, StyleStr: styleStr, AlignStr: alignStr, FontSize: rec.data.FontSize})
+
+	stringWidth := rec.ComputeStringLength(text) + 2
+	switch alignStr {
+	case "L":
+		rec.x += stringWidth
+	case "C":
+		rec.x += stringWidth / 2
+	}
+}
+
+func (rec *RecordingGenerator) PrintLnPdfText(text string, styleStr string, alignStr string) {
+	if rec.skip() {
+		return
+	}
+
+	if len(text) == 0 {
+		return
+	}
+
+	referenceX := rec.x
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimLeft(line, " ")
+		if line != "" {
+			rec.PrintPdfText(line, styleStr, alignStr)
+		}
+		rec.NewLine(referenceX)
+	}
+}
+
+// DrawLine records the operation "DrawLine" with the points, the line width and the red, green and blue values of the color.
+func (rec *RecordingGenerator) DrawLine(x1 float64, y1 float64, x2 float64, y2 float64, color Color, lineWidth float64) {
+	if rec.skip() {
+		return
+	}
+
+	if err := (LineStyle{Color: color, Width: lineWidth}).validate(); err != nil {
+		rec.err = err
+		return
+	}
+	if !rec.pointsOnPage([]Point{{X: x1, Y: y1}, {X: x2, Y: y2}}) {
+		return
+	}
+
+	rec.record(Operation{Name: "DrawLine", Values: []float64{x1, y1, x2, y2, lineWidth, float64(color.R), float64(color.G), float64(color.B)}})
+}
+
+func (rec *RecordingGenerator) DrawStyledLine(x1 float64, y1 float64, x2 float64, y2 float64, style LineStyle) {
+	if rec.skip() {
+		return
+	}
+
+	if err := style.validate(); err != nil {
+		rec.err = err
+		return
+	}
+	if !rec.pointsOnPage([]Point{{X: x1, Y: y1}, {X: x2, Y: y2}}) {
+		return
+	}
+
+	rec.record(Operation{Name: "DrawStyledLine", StyleStr: style.Dash, Values: []float64{x1, y1, x2, y2, style.Width}})
+}
+
+func (rec *RecordingGenerator) DrawRect(x float64, y float64, w float64, h float64, radius float64, style ShapeStyle) {
+	if rec.skip() {
+		return
+	}
+
+	if w <= 0 || h <= 0 || radius < 0 {
+		rec.err = errorsWithStack.New(fmt.Sprintf("The rectangle size %fx%f must be greater than 0 and the radius (%f) not negative.", w, h, radius))
+		return
+	}
+	if err := style.validate(); err != nil {
+		rec.err = err
+		return
+	}
+	if !rec.pointsOnPage([]Point{{X: x, Y: y}, {X: x + w, Y: y + h}}) {
+		return
+	}
+
+	rec.record(Operation{Name: "DrawRect", StyleStr: style.Line.Dash, Values: []float64{x, y, w, h, radius}})
+}
+
+func (rec *RecordingGenerator) DrawEllipse(x float64, y float64, rx float64, ry float64, style ShapeStyle) {
+	if rec.skip() {
+		return
+	}
+
+	if rx <= 0 || ry <= 0 {
+		rec.err = errorsWithStack.New(fmt.Sprintf("The radii %f and %f must be greater than 0.", rx, ry))
+		return
+	}
+	if err := style.validate(); err != nil {
+		rec.err = err
+		return
+	}
+	if !rec.pointsOnPage([]Point{{X: x - rx, Y: y - ry}, {X: x + rx, Y: y + ry}}) {
+		return
+	}
+
+	rec.record(Operation{Name: "DrawEllipse", StyleStr: style.Line.Dash, Values: []float64{x, y, rx, ry}})
+}
+
+func (rec *RecordingGenerator) DrawCircle(x float64, y float64, r float64, style ShapeStyle) {
+	rec.DrawEllipse(x, y, r, r, style)
+}
+
+// DrawPolygon records the points of the polygon as values x1, y1, x2, y2, ...
+func (rec *RecordingGenerator) DrawPolygon(points []Point, style ShapeStyle) {
+	if rec.skip() {
+		return
+	}
+
+	if len(points) < 3 {
+		rec.err = errorsWithStack.New(fmt.Sprintf("A polygon requires at least 3 points, got %d.", len(points)))
+		return
+	}
+	if err := style.validate(); err != nil {
+		rec.err = err
+		return
+	}
+	if !rec.pointsOnPage(points) {
+		return
+	}
+
+	var values []float64
+	for _, point := range points {
+		values = append(values, point.X, point.Y)
+	}
+	rec.record(Operation{Name: "DrawPolygon", StyleStr: style.Line.Dash, Values: values})
+}
+
+// DrawBarcode records the kind as style and the rectangle as values x, y, w, h.
+func (rec *RecordingGenerator) DrawBarcode(kind string, content string, x float64, y float64, w float64, h float64, humanReadable bool) {
+	if rec.skip() {
+		return
+	}
+
+	if _, err := encodeBarcode(kind, content); err != nil {
+		rec.err = err
+		return
+	}
+	if w <= 0 || h <= 0 {
+		rec.err = errorsWithStack.New(fmt.Sprintf("The barcode size %fx%f must be greater than 0.", w, h))
+		return
+	}
+	if !rec.pointsOnPage([]Point{{X: x, Y: y}, {X: x + w, Y: y + h}}) {
+		return
+	}
+
+	rec.record(Operation{Name: "DrawBarcode", Text: content, StyleStr: kind, Values: []float64{x, y, w, h}})
+}
+
+// DrawWatermark records the lines joined by a new line as text, the image name as style
+// and the values x, y, width, angle and opacity.
+func (rec *RecordingGenerator) DrawWatermark(mark Watermark) {
+	if rec.skip() {
+		return
+	}
+
+	if err := mark.validate(); err != nil {
+		rec.err = err
+		return
+	}
+	if mark.ImageName != "" && !rec.ImageIsRegistered(mark.ImageName) {
+		rec.err = errorsWithStack.New(fmt.Sprintf("The watermark image \"%s\" is not registered.", mark.ImageName))
+		return
+	}
+
+	rec.record(Operation{Name: "DrawWatermark", Text: strings.Join(mark.Lines, "\n"), StyleStr: mark.ImageName,
+		Values: []float64{mark.X, mark.Y, mark.Width, mark.Angle, mark.Opacity}})
+}
+
+// pointsOnPage sets an error and returns false, if a point is outside the page.
+func (rec *RecordingGenerator) pointsOnPage(points []Point) bool {
+	for _, point := range points {
+		if point.X < 0 || point.X > rec.pageWidth {
+			rec.err = errorsWithStack.New(fmt.Sprintf("x (%f) is out of range [%f, %f].", point.X, 0.0, rec.pageWidth))
+			return false
+		}
+		if point.Y < 0 || point.Y > rec.pageHeight {
+			rec.err = errorsWithStack.New(fmt.Sprintf("y (%f) is out of range [%f, %f].", point.Y, 0.0, rec.pageHeight))
+			return false
+		}
+	}
+	return true
+}
+
+func (rec *RecordingGenerator) PrintPdfTextFormatted(text string, styleStr string, alignStr string, borderStr string, fill bool, backgroundColor Color, cellHeight float64, cellWidth float64) {
+	if rec.skip() {
+		return
+	}
+
+	if cellHeight <= 0 || cellWidth <= 0 {
+		rec.err = errorsWithStack.New(fmt.Sprintf("A negative or zero cellHeight or cellWidth is not allowed."))
+		return
+	}
+
+	if _, err := ParseFontStyle(styleStr); err != nil {
+		rec.err = errorsWithStack.New(err.Error())
+		return
+	}
+
+	rec.record(Operation{Name: "PrintPdfTextFormatted", Text: text, StyleStr: styleStr, AlignStr: alignStr, FontSize: rec.data.FontSize, Values: []float64{cellWidth, cellHeight}})
+	rec.x += cellWidth
+}
+
+func (rec *RecordingGenerator) NewLine(oldX float64) {
+	if rec.skip() {
+		return
+	}
+
+	if oldX < 0 {
+		rec.err = errorsWithStack.New(fmt.Sprintf("A negative oldX is not allowed."))
+		return
+	}
+
+	rec.x = oldX
+	rec.y += rec.GetLineHeight(rec.data.FontSize, rec.data.FontGapY)
+}
+
+func (rec *RecordingGenerator) PreviousLine(oldX float64) {
+	if rec.skip() {
+		return
+	}
+
+	if oldX < 0 {
+		rec.err = errorsWithStack.New(fmt.Sprintf("A negative oldX is not allowed."))
+		return
+	}
+
+	rec.x = oldX
+	rec.y -= rec.GetLineHeight(rec.data.FontSize, rec.data.FontGapY)
+}
+
+// RegisterMimeImageToPdf registers the image without downloading it.
+// See SetImageExtent() to define the image size.
+func (rec *RecordingGenerator) RegisterMimeImageToPdf(cdnUrl *url.URL) (imageNameStr string) {
+	if rec.skip() {
+		return
+	}
+
+	imageNameStr = cdnUrl.String()
+	if _, ok := rec.imageExtents[imageNameStr]; !ok {
+		rec.imageExtents[imageNameStr] = rec.defaultImageExtent
+	}
+	rec.registeredImages[imageNameStr] = true
+	rec.record(Operation{Name: "RegisterMimeImageToPdf", Text: imageNameStr})
+
+	return imageNameStr
+}
+
+// RegisterImage registers the image like RegisterMimeImageToPdf, but checks the image type and size.
+// See SetImageExtent() to define the image size, svg images have the extent of the svg document.
+func (rec *RecordingGenerator) RegisterImage(imageNameStr string, imageData []byte) {
+	if rec.skip() {
+		return
+	}
+
+	imageType, err := ImageTypeFromData(imageData)
+	if err != nil {
+		rec.err = errorsWithStack.New(fmt.Sprintf("The image \"%s\" can't be registered: %s", imageNameStr, err.Error()))
+		return
+	}
+
+	if rec.ImageIsRegistered(imageNameStr) {
+		rec.err = errorsWithStack.New(fmt.Sprintf("The image \"%s\" is already registered.", imageNameStr))
+		return
+	}
+
+	if _, ok := rec.imageExtents[imageNameStr]; !ok {
+		rec.imageExtents[imageNameStr] = rec.defaultImageExtent
+		// the size of an svg image is known without decoding pixels
+		if imageType == "svg" {
+			svg, err := parseSVGImage(imageData)
+			if err != nil {
+				rec.err = errorsWithStack.New(fmt.Sprintf("The image \"%s\" can't be registered: %s", imageNameStr, err.Error()))
+				return
+			}
+			w, h := svg.extent(1 / rec.unitsPerPoint)
+			rec.imageExtents[imageNameStr] = [2]float64{w, h}
+		}
+	}
+	rec.registeredImages[imageNameStr] = true
+	rec.record(Operation{Name: "RegisterImage", Text: imageNameStr})
+}
+
+func (rec *RecordingGenerator) PlaceRegisteredImageOnPage(imageNameStr string, alignStr string, scale float64) {
+	if rec.skip() {
+		return
+	}
+
+	if scale == 0 {
+		rec.err = errorsWithStack.New(fmt.Sprintf("Image scale of 0 is not valide."))
+		return
+	}
+
+	if !rec.ImageIsRegistered(imageNameStr) {
+		rec.err = errorsWithStack.New(fmt.Sprintf("The image is not registerd."))
+		return
+	}
+
+	w, h := rec.GetRegisteredImageExtent(imageNameStr)
+	rec.record(Operation{Name: "PlaceRegisteredImageOnPage", Text: imageNameStr, AlignStr: alignStr, Values: []float64{w * scale, h * scale}})
+}
+
+func (rec *RecordingGenerator) GetRegisteredImageExtent(imageNameStr string) (w float64, h float64) {
+	extent := rec.imageExtents[imageNameStr]
+	return extent[0], extent[1]
+}
+
+func (rec *RecordingGenerator) ImageIsRegistered(imageNameStr string) bool {
+	return rec.registeredImages[imageNameStr]
+}
+
+// RegisterPdfPage registers the PDF page without parsing the PDF.
+func (rec *RecordingGenerator) RegisterPdfPage(pageNameStr string, pdfData []byte, pageNumber int) {
+	if rec.skip() {
+		return
+	}
+
+	if pageNumber < 1 {
+		rec.err = errorsWithStack.New(fmt.Sprintf("The page number %d must be grater then 0.", pageNumber))
+		return
+	}
+
+	if len(pdfData) == 0 {
+		rec.err = errorsWithStack.New(fmt.Sprintf("No PDF to import the page \"%s\" from.", pageNameStr))
+		return
+	}
+
+	if rec.PdfPageIsRegistered(pageNameStr) {
+		rec.err = errorsWithStack.New(fmt.Sprintf("The PDF page \"%s\" is already registered.", pageNameStr))
+		return
+	}
+
+	rec.registeredPdfPages[pageNameStr] = true
+	rec.record(Operation{Name: "RegisterPdfPage", Text: pageNameStr, Values: []float64{float64(pageNumber)}})
+}
+
+func (rec *RecordingGenerator) PlaceRegisteredPdfPage(pageNameStr string) {
+	if rec.skip() {
+		return
+	}
+
+	if !rec.PdfPageIsRegistered(pageNameStr) {
+		rec.err = errorsWithStack.New(fmt.Sprintf("The PDF page \"%s\" is not registerd.", pageNameStr))
+		return
+	}
+
+	rec.record(Operation{Name: "PlaceRegisteredPdfPage", Text: pageNameStr, Values: []float64{rec.pageWidth, rec.pageHeight}})
+}
+
+func (rec *RecordingGenerator) PdfPageIsRegistered(pageNameStr string) bool {
+	return rec.registeredPdfPages[pageNameStr]
+}
+
+func (rec *RecordingGenerator) PrintTableHeader(cells []string, columnWidth []float64, columnAlignStrings []string) {
+	if rec.skip() {
+		return
+	}
+
+	if len(cells) != len(columnWidth) {
+		rec.err = errorsWithStack.New(fmt.Sprintf("The length of cells and columnWidth must be equial."))
+		return
+	}
+
+	rec.record(Operation{Name: "PrintTableHeader", FontSize: rec.data.FontSize, Values: columnWidth, Cells: [][]string{cells}})
+	rec.y += rec.tableRowHeight()
+}
+
+func (rec *RecordingGenerator) PrintTableBody(cells [][]string, columnWidths []float64, columnAlignStrings []string) {
+	if rec.skip() {
+		return
+	}
+
+	if len(columnWidths) != len(columnAlignStrings) {
+		rec.err = errorsWithStack.New(fmt.Sprintf("The length of columnWidths and columnAlignStrings must be equial."))
+		return
+	}
+
+	if err := validateTableCells(cells, columnWidths); err != nil {
+		rec.err = err
+		return
+	}
+
+	rec.record(Operation{Name: "PrintTableBody", FontSize: rec.data.FontSize, Values: columnWidths, Cells: cells})
+	for _, row := range cells {
+		var maxLines = 0
+		for _, cell := range row {
+			maxLines = int(math.Max(float64(maxLines), float64(strings.Count(cell, "\n")+1)))
+		}
+		rec.y += float64(maxLines) * rec.tableRowHeight()
+	}
+}
+
+func (rec *RecordingGenerator) PrintTableFooter(cells [][]string, columnWidths []float64, columnAlignStrings []string) {
+	if rec.skip() {
+		return
+	}
+
+	if len(columnWidths) != len(columnAlignStrings) {
+		rec.err = errorsWithStack.New(fmt.Sprintf("The length of columnWidths and columnAlignStrings must be equial."))
+		return
+	}
+
+	if err := validateTableCells(cells, columnWidths); err != nil {
+		rec.err = err
+		return
+	}
+
+	rec.record(Operation{Name: "PrintTableFooter", FontSize: rec.data.FontSize, Values: columnWidths, Cells: cells})
+	rec.y += float64(len(cells)) * rec.tableRowHeight()
+}
+
+func (rec *RecordingGenerator) tableRowHeight() float64 {
+	return rec.GetLineHeight(rec.data.FontSize, rec.data.FontGapY*2)
+}
+
+func (rec *RecordingGenerator) RenderBoxes(frame Frame, boxes []Box) {
+	if rec.skip() {
+		return
+	}
+
+	renderBoxes(rec, rec.logger, frame, boxes)
+}
+
+func (rec *RecordingGenerator) MeasureBoxes(boxes []Box, width float64) (height float64) {
+	return measureBoxes(rec, boxes, width)
+}
+
+// Output closes the document and writes all recorded operations as JSON to w.
+func (rec *RecordingGenerator) Output(w io.Writer) error {
+	if !rec.closed && rec.totalPages > 0 {
+		rec.closed = true
+		rec.currentPage = rec.totalPages
+		rec.footerFunction(true)
+	}
+
+	if rec.err != nil {
+		return rec.err
+	}
+
+	return json.NewEncoder(w).Encode(rec.operations)
+}
+
+func (rec *RecordingGenerator) GetError() error {
+	return rec.err
+}
+
+func (rec *RecordingGenerator) SetError(err error) {
+	if rec.skip() {
+		return
+	}
+
+	rec.err = err
+}
+
+// ComputeStringLength returns the approximated length of str with the current font size.
+func (rec *RecordingGenerator) ComputeStringLength(str string) (length float64) {
+	return float64(len([]rune(str))) * rec.data.FontSize * rec.averageCharWidthRate * rec.unitsPerPoint
+}
+
+// SplitText wraps the text at the given width with the approximated character width.
+func (rec *RecordingGenerator) SplitText(text string, _ string, fontSize float64, width float64) (lines []string) {
+	if text == "" {
+		return nil
+	}
+
+	maxChars := int(width / (fontSize * rec.averageCharWidthRate * rec.unitsPerPoint))
+	if maxChars < 1 {
+		maxChars = 1
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimLeft(line, " ")
+		if line == "" {
+			lines = append(lines, "")
+			continue
+		}
+
+		var current string
+		for _, word := range strings.Fields(line) {
+			switch {
+			case current == "":
+				current = word
+			case len([]rune(current))+1+len([]rune(word)) <= maxChars:
+				current += " " + word
+			default:
+				lines = append(lines, current)
+				current = word
+			}
+		}
+		lines = append(lines, current)
+	}
+
+	return lines
+}
+
+func (rec *RecordingGenerator) GetLineHeight(fontSize float64, fontGapY float64) float64 {
+	return fontSize*rec.unitsPerPoint + fontGapY
+}
+
+func (rec *RecordingGenerator) GetFontName() string {
+	return rec.data.FontName
+}
+
+func (rec *RecordingGenerator) GetMarginLeft() float64 {
+	return rec.data.MarginLeft
+}
+
+func (rec *RecordingGenerator) GetMarginTop() float64 {
+	return rec.data.MarginTop
+}
+
+func (rec *RecordingGenerator) GetMarginRight() float64 {
+	return rec.data.MarginRight
+}
+
+func (rec *RecordingGenerator) GetMarginBottom() float64 {
+	return rec.data.MarginBottom
+}
+
+func (rec *RecordingGenerator) GetPageSize() (width float64, height float64) {
+	return rec.pageWidth, rec.pageHeight
+}
+
+func (rec *RecordingGenerator) GetFontGapY() float64 {
+	return rec.data.FontGapY
+}
+
+func (rec *RecordingGenerator) SetFontGapY(fontGapY float64) {
+	if rec.skip() {
+		return
+	}
+
+	if fontGapY < 0 {
+		rec.err = errorsWithStack.New(fmt.Sprintf("Text size must be grather or equal then 0."))
+		return
+	}
+
+	rec.data.FontGapY = fontGapY
+}
+
+func (rec *RecordingGenerator) GetFontSize() float64 {
+	return rec.data.FontSize
+}
+
+func (rec *RecordingGenerator) SetFontSize(textSize float64) {
+	if rec.skip() {
+		return
+	}
+
+	if textSize <= 0 {
+		rec.err = errorsWithStack.New(fmt.Sprintf("Text size must be grather then 0."))
+		return
+	}
+
+	rec.data.FontSize = textSize
+}
+
+func (rec *RecordingGenerator) GetLineColor() Color {
+	return rec.data.DefaultLineColor
+}
+
+func (rec *RecordingGenerator) GetLineWidth() float64 {
+	return rec.data.DefaultLineWidth
+}
+
+func (rec *RecordingGenerator) GetTextColor() Color {
+	return rec.textColor
+}
+
+// SetTextColor records the operation "SetTextColor" with the red, green and blue values.
+func (rec *RecordingGenerator) SetTextColor(color Color) {
+	if rec.skip() {
+		return
+	}
+
+	rec.textColor = color
+	rec.record(Operation{Name: "SetTextColor", Values: []float64{float64(color.R), float64(color.G), float64(color.B)}})
+}
+
+func (rec *RecordingGenerator) GetCursor() (x float64, y float64) {
+	return rec.x, rec.y
+}
+
+func (rec *RecordingGenerator) SetCursor(x float64, y float64) {
+	if rec.skip() {
+		return
+	}
+
+	if x < rec.data.MarginLeft || x > rec.maxSaveX {
+		rec.err = errorsWithStack.New(fmt.Sprintf("New cursor position x = %f is out of range [%f, %f].", x, rec.data.MarginLeft, rec.maxSaveX))
+		return
+	}
+
+	if y < rec.data.MarginTop || y > rec.maxSaveY {
+		rec.err = errorsWithStack.New(fmt.Sprintf("New cursor position y = %f is out of range [%f, %f].", y, rec.data.MarginTop, rec.maxSaveY))
+		return
+	}
+
+	rec.x, rec.y = x, y
+}
+
+func (rec *RecordingGenerator) SetUnsafeCursor(x float64, y float64) {
+	if rec.skip() {
+		return
+	}
+
+	if x < 0 || x > rec.pageWidth {
+		rec.err = errorsWithStack.New(fmt.Sprintf("New cursor position x = %f is out of range [%f, %f].", x, 0.0, rec.pageWidth))
+		return
+	}
+
+	if y < 0 || y > rec.pageHeight {
+		rec.err = errorsWithStack.New(fmt.Sprintf("New cursor position y = %f is out of range [%f, %f].", y, 0.0, rec.pageHeight))
+		return
+	}
+
+	rec.x, rec.y = x, y
+}
+
+// NewPage calls the footer function for the current page and the header function for the new page.
+func (rec *RecordingGenerator) NewPage() {
+	if rec.totalPages > 0 {
+		rec.footerFunction(false)
+	}
+
+	rec.totalPages++
+	rec.currentPage = rec.totalPages
+	rec.x, rec.y = rec.data.MarginLeft, rec.data.MarginTop
+	rec.record(Operation{Name: "NewPage"})
+	rec.headerFunction()
+	rec.x, rec.y = rec.data.MarginLeft, rec.data.MarginTop
+}
+
+func (rec *RecordingGenerator) GetCurrentPageNumber() int {
+	return rec.currentPage
+}
+
+func (rec *RecordingGenerator) GetTotalNumber() int {
+	return rec.totalPages
+}
+
+func (rec *RecordingGenerator) GoToPage(pageNumber int) {
+	if pageNumber < 1 || pageNumber > rec.totalPages {
+		rec.err = errorsWithStack.New(fmt.Sprintf("Page %d does not exist.", pageNumber))
+		return
+	}
+
+	rec.currentPage = pageNumber
+}
@@ -0,0 +1,89 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestRecordingGenerator_RenderBoxes(t *testing.T) {
+	var headerCalls, footerCalls int
+
+	rec, err := NewRecordingGenerator(_defaultMetaData, false, &_logger, func() { headerCalls++ }, func(isLastPage bool) { footerCalls++ })
+	if err != nil {
+		t.Errorf("init recorder error\n%s", err.Error())
+		return
+	}
+	rec.NewPage()
+	rec.SetCursor(10, 250)
+
+	rec.RenderBoxes(Frame{StartX: 10, Width: 100, NextPageStartY: 10, StopY: 270}, []Box{
+		ParagraphBox{BoxConstraints: BoxConstraints{KeepWithNext: true}, Text: "Headline", AlignStr: "L", FontSize: 20},
+		TableBox{
+			Header:       []string{"a", "b"},
+			Body:         [][]string{{"1", "2"}, {"3", "4"}},
+			ColumnWidths: []float64{50, 50},
+			HeaderAlign:  []string{"LM", "LM"},
+			BodyAlign:    []string{"LM", "LM"},
+			FontSize:     10,
+			FontGapY:     2,
+		},
+	})
+
+	if rec.GetError() != nil {
+		t.Errorf("RenderBoxes() error = %v", rec.GetError())
+		return
+	}
+
+	if got := rec.GetTotalNumber(); got != 2 {
+		t.Errorf("RenderBoxes() pages = %v, want 2", got)
+	}
+
+	texts := rec.OperationsByName("PrintPdfText")
+	if len(texts) != 1 || texts[0].Text != "Headline" || texts[0].Page != 2 || texts[0].Y != 10 {
+		t.Errorf("RenderBoxes() headline = %+v, want on top of page 2", texts)
+	}
+
+	tables := rec.OperationsByName("PrintTableBody")
+	if len(tables) != 1 || tables[0].Page != 2 {
+		t.Errorf("RenderBoxes() table = %+v, want on page 2", tables)
+	}
+
+	var buf bytes.Buffer
+	if err = rec.Output(&buf); err != nil {
+		t.Errorf("Output() error = %v", err)
+		return
+	}
+
+	var operations []Operation
+	if err = json.Unmarshal(buf.Bytes(), &operations); err != nil || len(operations) != len(rec.Operations()) {
+		t.Errorf("Output() got %d operations, want %d (%v)", len(operations), len(rec.Operations()), err)
+	}
+
+	if headerCalls != 2 || footerCalls != 2 {
+		t.Errorf("header and footer calls = %d %d, want 2 2", headerCalls, footerCalls)
+	}
+}
+
+func TestRecordingGenerator_SplitText(t *testing.T) {
+	rec, err := NewRecordingGenerator(_defaultMetaData, false, &_logger, func() {}, func(isLastPage bool) {})
+	if err != nil {
+		t.Errorf("init recorder error\n%s", err.Error())
+		return
+	}
+
+	// 10 pt * 0.5 = 5 pt per character, 36 pt = 7 characters
+	lines := rec.SplitText("aaa bbb ccc\n\n dd", "", 10, 36*rec.unitsPerPoint)
+	want := []string{"aaa bbb", "ccc", "", "dd"}
+
+	if len(lines) != len(want) {
+		t.Errorf("SplitText() = %q, want %q", lines, want)
+		return
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("SplitText() = %q, want %q", lines, want)
+			return
+		}
+	}
+}
@@ -0,0 +1,374 @@
+package generator
+
+import (
+	"fmt"
+	errorsWithStack "github.com/go-errors/errors"
+	"github.com/jung-kurt/gofpdf"
+	"math"
+)
+
+// dash styles of a LineStyle
+const (
+	LineSolid  = "solid"
+	LineDashed = "dashed"
+	LineDotted = "dotted"
+)
+
+// shapeCurveSegments is the number of polygon edges of a full ellipse in the previews, see PNGGenerator and SVGGenerator.
+const shapeCurveSegments = 72
+
+// Point is a position on the page in the unit of measure specified in NewPDFGenerator().
+type Point struct {
+	X float64
+	Y float64
+}
+
+// LineStyle defines the look of a line or of the outline of a shape.
+//
+// Color is the line color.
+//
+// Width is the line width in the unit of measure specified in NewPDFGenerator().
+// A zero width uses the current line width, i.e. the MetaData.DefaultLineWidth.
+//
+// Dash specifies the dash style:
+//
+//	"solid" or "" for a solid line,
+//	"dashed" for a dashed line, or
+//	"dotted" for a dotted line with round dots.
+type LineStyle struct {
+	Color Color
+	Width float64
+	Dash  string
+}
+
+// ShapeStyle defines the look of a shape drawn with DrawRect, DrawEllipse, DrawCircle or DrawPolygon.
+//
+// If Stroke is true, the outline is drawn in the LineStyle Line.
+// If Fill is true, the shape is filled with FillColor.
+type ShapeStyle struct {
+	Stroke    bool
+	Line      LineStyle
+	Fill      bool
+	FillColor Color
+}
+
+// dashPattern returns the lengths of the dashes and the gaps of the dash style for a line of lineWidth.
+// A solid line has no pattern, a dot has the length zero and is drawn with a round line cap.
+// Lines without a width are drawn solid.
+func dashPattern(dash string, lineWidth float64) []float64 {
+	if lineWidth <= 0 {
+		return nil
+	}
+
+	switch dash {
+	case LineDashed:
+		return []float64{4 * lineWidth, 2 * lineWidth}
+	case LineDotted:
+		return []float64{0, 2 * lineWidth}
+	default:
+		return nil
+	}
+}
+
+// validate checks the dash style and the line width.
+func (style LineStyle) validate() error {
+	switch style.Dash {
+	case "", LineSolid, LineDashed, LineDotted:
+	default:
+		return errorsWithStack.New(fmt.Sprintf("The dash style \"%s\" must be solid, dashed or dotted.", style.Dash))
+	}
+
+	if style.Width < 0 {
+		return errorsWithStack.New(fmt.Sprintf("A negative line width (%f) is not allowed.", style.Width))
+	}
+
+	return nil
+}
+
+// validate checks the line style and that the shape is stroked or filled.
+func (style ShapeStyle) validate() error {
+	if !style.Stroke && !style.Fill {
+		return errorsWithStack.New(fmt.Sprintf("The shape is neither stroked nor filled."))
+	}
+
+	if style.Stroke {
+		return style.Line.validate()
+	}
+
+	return nil
+}
+
+// pathStyle returns the gofpdf style string "D", "F" or "FD".
+func (style ShapeStyle) pathStyle() string {
+	styleStr := ""
+	if style.Fill {
+		styleStr += "F"
+	}
+	if style.Stroke {
+		styleStr += "D"
+	}
+	return styleStr
+}
+
+// DrawStyledLine draws a line between two points in its own color, width and dash style.
+// The line style of DrawLine is not changed.
+//
+// x1 and y1 defines the abscissa (x) and ordinate (y) start point.
+//
+// x2 and y2 defines the abscissa (x) and ordinate (y) end point.
+//
+// style specifies the color, the width and the dash style of the line.
+func (core *PDFGenerator) DrawStyledLine(x1 float64, y1 float64, x2 float64, y2 float64, style LineStyle) {
+	if core.strictErrorHandling == true && core.pdf.Err() {
+		return
+	}
+
+	// --> validate inputs
+	if err := style.validate(); err != nil {
+		core.pdf.SetError(err)
+		return
+	}
+
+	if !core.pointsOnPage([]Point{{X: x1, Y: y1}, {X: x2, Y: y2}}) {
+		return
+	}
+	// <--
+
+	restore := core.setLineStyle(style)
+	core.pdf.Line(x1, y1, x2, y2)
+	lineWidth := core.pdf.GetLineWidth()
+	restore()
+
+	core.recordShape([]Point{{X: x1, Y: y1}, {X: x2, Y: y2}}, false, ShapeStyle{Stroke: true, Line: style}, lineWidth)
+}
+
+// DrawRect draws a rectangle, e.g. a box around the payment information.
+//
+// x and y defines the top left corner, w and h the width and the height.
+//
+// radius specifies the radius of the rounded corners, use 0 for sharp corners.
+// The radius is limited to the half of the shorter side.
+//
+// style specifies the outline and the fill of the rectangle.
+func (core *PDFGenerator) DrawRect(x float64, y float64, w float64, h float64, radius float64, style ShapeStyle) {
+	if core.strictErrorHandling == true && core.pdf.Err() {
+		return
+	}
+
+	// --> validate inputs
+	if w <= 0 || h <= 0 {
+		core.pdf.SetError(errorsWithStack.New(fmt.Sprintf("The rectangle size %fx%f must be greater than 0.", w, h)))
+		return
+	}
+
+	if radius < 0 {
+		core.pdf.SetError(errorsWithStack.New(fmt.Sprintf("A negative radius (%f) is not allowed.", radius)))
+		return
+	}
+
+	if err := style.validate(); err != nil {
+		core.pdf.SetError(err)
+		return
+	}
+
+	if !core.pointsOnPage([]Point{{X: x, Y: y}, {X: x + w, Y: y + h}}) {
+		return
+	}
+	// <--
+
+	radius = math.Min(radius, math.Min(w, h)/2)
+
+	restore := core.setShapeStyle(style)
+	if radius > 0 {
+		core.pdf.RoundedRect(x, y, w, h, radius, "1234", style.pathStyle())
+	} else {
+		core.pdf.Rect(x, y, w, h, style.pathStyle())
+	}
+	lineWidth := core.pdf.GetLineWidth()
+	restore()
+
+	core.recordShape(rectOutline(x, y, w, h, radius), true, style, lineWidth)
+}
+
+// DrawEllipse draws an ellipse, e.g. a stamp.
+//
+// x and y defines the center, rx and ry the horizontal and the vertical radius.
+//
+// style specifies the outline and the fill of the ellipse.
+func (core *PDFGenerator) DrawEllipse(x float64, y float64, rx float64, ry float64, style ShapeStyle) {
+	if core.strictErrorHandling == true && core.pdf.Err() {
+		return
+	}
+
+	// --> validate inputs
+	if rx <= 0 || ry <= 0 {
+		core.pdf.SetError(errorsWithStack.New(fmt.Sprintf("The radii %f and %f must be greater than 0.", rx, ry)))
+		return
+	}
+
+	if err := style.validate(); err != nil {
+		core.pdf.SetError(err)
+		return
+	}
+
+	if !core.pointsOnPage([]Point{{X: x - rx, Y: y - ry}, {X: x + rx, Y: y + ry}}) {
+		return
+	}
+	// <--
+
+	restore := core.setShapeStyle(style)
+	core.pdf.Ellipse(x, y, rx, ry, 0, style.pathStyle())
+	lineWidth := core.pdf.GetLineWidth()
+	restore()
+
+	core.recordShape(ellipseOutline(x, y, rx, ry), true, style, lineWidth)
+}
+
+// DrawCircle draws a circle with the center x, y and the radius r, see DrawEllipse.
+func (core *PDFGenerator) DrawCircle(x float64, y float64, r float64, style ShapeStyle) {
+	core.DrawEllipse(x, y, r, r, style)
+}
+
+// DrawPolygon draws a closed polygon through the points, e.g. a triangle or a star.
+//
+// points are the corners of the polygon, at least 3 points are required.
+//
+// style specifies the outline and the fill of the polygon.
+func (core *PDFGenerator) DrawPolygon(points []Point, style ShapeStyle) {
+	if core.strictErrorHandling == true && core.pdf.Err() {
+		return
+	}
+
+	// --> validate inputs
+	if len(points) < 3 {
+		core.pdf.SetError(errorsWithStack.New(fmt.Sprintf("A polygon requires at least 3 points, got %d.", len(points))))
+		return
+	}
+
+	if err := style.validate(); err != nil {
+		core.pdf.SetError(err)
+		return
+	}
+
+	if !core.pointsOnPage(points) {
+		return
+	}
+	// <--
+
+	pdfPoints := make([]gofpdf.PointType, len(points))
+	for i, point := range points {
+		pdfPoints[i] = gofpdf.PointType{X: point.X, Y: point.Y}
+	}
+
+	restore := core.setShapeStyle(style)
+	core.pdf.Polygon(pdfPoints, style.pathStyle())
+	lineWidth := core.pdf.GetLineWidth()
+	restore()
+
+	core.recordShape(points, true, style, lineWidth)
+}
+
+// pointsOnPage sets an error and returns false, if a point is outside the page.
+func (core *PDFGenerator) pointsOnPage(points []Point) bool {
+	pageWidth, pageLength := core.pdf.GetPageSize()
+
+	for _, point := range points {
+		if point.X < 0 || point.X > pageWidth {
+			core.pdf.SetError(errorsWithStack.New(fmt.Sprintf("x (%f) is out of range [%f, %f].", point.X, 0.0, pageWidth)))
+			return false
+		}
+
+		if point.Y < 0 || point.Y > pageLength {
+			core.pdf.SetError(errorsWithStack.New(fmt.Sprintf("y (%f) is out of range [%f, %f].", point.Y, 0.0, pageLength)))
+			return false
+		}
+	}
+
+	return true
+}
+
+// setLineStyle sets the draw color, the line width and the dash pattern of style.
+// The returned function restores the previous line style.
+func (core *PDFGenerator) setLineStyle(style LineStyle) (restore func()) {
+	lineWidth := core.pdf.GetLineWidth()
+	r, g, b := core.pdf.GetDrawColor()
+
+	width := style.Width
+	if width == 0 {
+		width = lineWidth
+	}
+	core.pdf.SetLineWidth(width)
+	core.pdf.SetDrawColor(int(style.Color.R), int(style.Color.G), int(style.Color.B))
+
+	if pattern := dashPattern(style.Dash, width); pattern != nil {
+		if style.Dash == LineDotted {
+			core.pdf.SetLineCapStyle("round")
+		}
+		core.pdf.SetDashPattern(pattern, 0)
+	}
+
+	return func() {
+		if style.Dash == LineDashed || style.Dash == LineDotted {
+			core.pdf.SetDashPattern([]float64{}, 0)
+			core.pdf.SetLineCapStyle("butt")
+		}
+		core.pdf.SetLineWidth(lineWidth)
+		core.pdf.SetDrawColor(r, g, b)
+	}
+}
+
+// setShapeStyle sets the line style and the fill color of style.
+// The returned function restores the previous styles.
+func (core *PDFGenerator) setShapeStyle(style ShapeStyle) (restore func()) {
+	restoreLine := func() {}
+	if style.Stroke {
+		restoreLine = core.setLineStyle(style.Line)
+	}
+
+	r, g, b := core.pdf.GetFillColor()
+	if style.Fill {
+		core.pdf.SetFillColor(int(style.FillColor.R), int(style.FillColor.G), int(style.FillColor.B))
+	}
+
+	return func() {
+		core.pdf.SetFillColor(r, g, b)
+		restoreLine()
+	}
+}
+
+// rectOutline returns the corners of a rectangle, the rounded corners are approximated by polygon edges.
+func rectOutline(x float64, y float64, w float64, h float64, radius float64) []Point {
+	if radius == 0 {
+		return []Point{{X: x, Y: y}, {X: x + w, Y: y}, {X: x + w, Y: y + h}, {X: x, Y: y + h}}
+	}
+
+	// the corner centers clockwise from the top right corner with the start angle of their quarter arc
+	corners := []struct {
+		x, y, angle float64
+	}{
+		{x: x + w - radius, y: y + radius, angle: -math.Pi / 2},
+		{x: x + w - radius, y: y + h - radius, angle: 0},
+		{x: x + radius, y: y + h - radius, angle: math.Pi / 2},
+		{x: x + radius, y: y + radius, angle: math.Pi},
+	}
+
+	var points []Point
+	const steps = shapeCurveSegments / 4
+	for _, corner := range corners {
+		for i := 0; i <= steps; i++ {
+			angle := corner.angle + float64(i)/steps*math.Pi/2
+			points = append(points, Point{X: corner.x + radius*math.Cos(angle), Y: corner.y + radius*math.Sin(angle)})
+		}
+	}
+	return points
+}
+
+// ellipseOutline returns the ellipse approximated by polygon edges.
+func ellipseOutline(x float64, y float64, rx float64, ry float64) []Point {
+	points := make([]Point, shapeCurveSegments)
+	for i := range points {
+		angle := float64(i) / shapeCurveSegments * 2 * math.Pi
+		points[i] = Point{X: x + rx*math.Cos(angle), Y: y + ry*math.Sin(angle)}
+	}
+	return points
+}
@@ -0,0 +1,177 @@
+package generator
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestPDFGenerator_DrawShapes(t *testing.T) {
+	red := Color{R: 200, G: 30, B: 30}
+	box := ShapeStyle{Stroke: true, Line: LineStyle{Color: red, Width: .5}, Fill: true, FillColor: Color{R: 250, G: 240, B: 240}}
+
+	tests := []struct {
+		name    string
+		draw    func(core *PDFGenerator)
+		wantErr bool
+	}{
+		{
+			name: "rounded rectangle",
+			draw: func(core *PDFGenerator) {
+				core.DrawRect(20, 20, 80, 30, 3, box)
+			},
+			wantErr: false,
+		},
+		{
+			name: "dashed line",
+			draw: func(core *PDFGenerator) {
+				core.DrawStyledLine(20, 60, 100, 60, LineStyle{Color: red, Dash: LineDashed})
+			},
+			wantErr: false,
+		},
+		{
+			name: "dotted circle",
+			draw: func(core *PDFGenerator) {
+				core.DrawCircle(150, 40, 20, ShapeStyle{Stroke: true, Line: LineStyle{Color: red, Width: 1, Dash: LineDotted}})
+			},
+			wantErr: false,
+		},
+		{
+			name: "filled polygon",
+			draw: func(core *PDFGenerator) {
+				core.DrawPolygon([]Point{{X: 20, Y: 100}, {X: 60, Y: 100}, {X: 40, Y: 70}}, ShapeStyle{Fill: true, FillColor: red})
+			},
+			wantErr: false,
+		},
+		{
+			name: "polygon with two points",
+			draw: func(core *PDFGenerator) {
+				core.DrawPolygon([]Point{{X: 20, Y: 100}, {X: 60, Y: 100}}, box)
+			},
+			wantErr: true,
+		},
+		{
+			name: "rectangle without height",
+			draw: func(core *PDFGenerator) {
+				core.DrawRect(20, 20, 80, 0, 0, box)
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown dash style",
+			draw: func(core *PDFGenerator) {
+				core.DrawStyledLine(20, 60, 100, 60, LineStyle{Dash: "wavy"})
+			},
+			wantErr: true,
+		},
+		{
+			name: "neither stroked nor filled",
+			draw: func(core *PDFGenerator) {
+				core.DrawEllipse(100, 100, 20, 10, ShapeStyle{})
+			},
+			wantErr: true,
+		},
+		{
+			name: "ellipse outside the page",
+			draw: func(core *PDFGenerator) {
+				core.DrawEllipse(5, 100, 20, 10, box)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, err := NewPDFGenerator(_defaultMetaData, false, &_logger, func() {}, func(isLastPage bool) {})
+			if err != nil {
+				t.Fatalf("init core error\n%s", err.Error())
+			}
+			core.NewPage()
+
+			lineWidth := core.pdf.GetLineWidth()
+			r, g, b := core.pdf.GetDrawColor()
+
+			tt.draw(core)
+			if gotErr := core.pdf.Err(); gotErr != tt.wantErr {
+				t.Fatalf("draw set a error = %v, want %v", core.pdf.Error(), tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			// DrawLine keeps its line style
+			gotR, gotG, gotB := core.pdf.GetDrawColor()
+			if gotWidth := core.pdf.GetLineWidth(); gotWidth != lineWidth || gotR != r || gotG != g || gotB != b {
+				t.Errorf("the line style is not restored, got width %v and color %v %v %v", gotWidth, gotR, gotG, gotB)
+			}
+
+			var buffer bytes.Buffer
+			if err = core.Output(&buffer); err != nil {
+				t.Errorf("Output() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestSVGGenerator_Output_shapes(t *testing.T) {
+	core, err := NewSVGGenerator(_defaultMetaData, false, &_logger, func() {}, func(isLastPage bool) {})
+	if err != nil {
+		t.Fatalf("init core error\n%s", err.Error())
+	}
+
+	core.NewPage()
+	core.DrawRect(20, 20, 80, 30, 3, ShapeStyle{Stroke: true, Line: LineStyle{Width: .5}})
+	core.DrawStyledLine(20, 60, 100, 60, LineStyle{Width: .5, Dash: LineDotted})
+
+	var buffer bytes.Buffer
+	if err = core.Output(&buffer); err != nil {
+		t.Fatalf("Output() error = %v", err)
+	}
+
+	svg := buffer.String()
+	for _, want := range []string{`<polygon points="97.000,20.000 `, `fill="none" stroke="#000000" stroke-width="0.500"/>`,
+		`<polyline points="20.000,60.000 100.000,60.000"`, `stroke-dasharray="0.000 1.000" stroke-linecap="round"`} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("Output() does not contain %s", want)
+		}
+	}
+}
+
+func TestPNGGenerator_Output_lineColor(t *testing.T) {
+	core, err := NewPNGGenerator(_defaultMetaData, false, &_logger, func() {}, func(isLastPage bool) {}, 1, 72)
+	if err != nil {
+		t.Fatalf("init core error\n%s", err.Error())
+	}
+
+	core.NewPage()
+	core.DrawLine(20, 20, 100, 20, Color{R: 255}, 1)
+	core.DrawStyledLine(20, 40, 100, 40, LineStyle{Color: Color{B: 255}, Width: 1})
+
+	var buffer bytes.Buffer
+	if err = core.Output(&buffer); err != nil {
+		t.Fatalf("Output() error = %v", err)
+	}
+	img, err := png.Decode(&buffer)
+	if err != nil {
+		t.Fatalf("Output() is not a png image: %v", err)
+	}
+
+	// the pixels in the middle of the lines at 72 dpi
+	tests := []struct {
+		name      string
+		x         int
+		y         int
+		wantColor Color
+	}{
+		{name: "DrawLine", x: 170, y: 57, wantColor: Color{R: 255}},
+		{name: "DrawStyledLine", x: 170, y: 113, wantColor: Color{B: 255}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, g, b, _ := img.At(tt.x, tt.y).RGBA()
+			if got := (Color{R: uint8(r >> 8), G: uint8(g >> 8), B: uint8(b >> 8)}); got != tt.wantColor {
+				t.Errorf("Output() has the color %v at %d, %d, want %v", got, tt.x, tt.y, tt.wantColor)
+			}
+		})
+	}
+}
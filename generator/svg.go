@@ -0,0 +1,211 @@
+package generator
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	errorsWithStack "github.com/go-errors/errors"
+	"github.com/rs/zerolog"
+	"html"
+	"io"
+	"strings"
+)
+
+// SVGGenerator is a Generator, which renders the document as SVG instead of PDF.
+//
+// The layout is computed by an embedded PDFGenerator, so positions, text widths and page breaks
+// are the same as in the PDF output.
+type SVGGenerator struct {
+	*PDFGenerator
+}
+
+// NewSVGGenerator construct and return a new SVGGenerator instance.
+// The parameters are used like in NewPDFGenerator().
+func NewSVGGenerator(data MetaData, strictErrorHandling bool, logger *zerolog.Logger, headerFunction func(), footerFunction func(isLastPage bool)) (gen *SVGGenerator, err error) {
+	pdfGen, err := NewPDFGenerator(data, strictErrorHandling, logger, headerFunction, footerFunction)
+	if err != nil {
+		return nil, err
+	}
+
+	pdfGen.display = newDisplayList()
+
+	return &SVGGenerator{PDFGenerator: pdfGen}, nil
+}
+
+// NewSVGGeneratorBackend is a NewGeneratorFunc, which returns a SVGGenerator.
+func NewSVGGeneratorBackend(data MetaData, strictErrorHandling bool, logger *zerolog.Logger, headerFunction func(), footerFunction func(isLastPage bool)) (Generator, error) {
+	gen, err := NewSVGGenerator(data, strictErrorHandling, logger, headerFunction, footerFunction)
+	if err != nil {
+		return nil, err
+	}
+	return gen, nil
+}
+
+// Output closes the document and writes all pages one below the other as one SVG image to w.
+func (svg *SVGGenerator) Output(w io.Writer) error {
+	svg.close()
+	if svg.pdf.Err() {
+		return svg.pdf.Error()
+	}
+
+	return svg.writeSVG(w, 1, svg.pdf.PageCount())
+}
+
+// OutputPage closes the document and writes one page as SVG image to w.
+func (svg *SVGGenerator) OutputPage(w io.Writer, pageNumber int) error {
+	svg.close()
+	if svg.pdf.Err() {
+		return svg.pdf.Error()
+	}
+
+	if pageNumber < 1 || pageNumber > svg.pdf.PageCount() {
+		return errorsWithStack.New(fmt.Sprintf("The page %d is out of range [1, %d].", pageNumber, svg.pdf.PageCount()))
+	}
+
+	return svg.writeSVG(w, pageNumber, pageNumber)
+}
+
+// close calls the footer of the last page, like PDFGenerator.Output().
+func (svg *SVGGenerator) close() {
+	if svg.pdf.PageCount() > 0 {
+		svg.pdf.Close()
+	}
+}
+
+// previewPageGap defines the gap in points between two pages placed one below the other.
+const previewPageGap = 12.
+
+// pagesExtent returns the overall size of the pages fromPage to toPage placed one below the other.
+func (core *PDFGenerator) pagesExtent(fromPage int, toPage int) (width float64, height float64) {
+	pageWidth, pageHeight := core.pdf.GetPageSize()
+	pages := float64(toPage - fromPage + 1)
+
+	return pageWidth, pages*pageHeight + (pages-1)*core.pdf.PointConvert(previewPageGap)
+}
+
+func (svg *SVGGenerator) writeSVG(w io.Writer, fromPage int, toPage int) error {
+	unit := svg.data.Unit
+	pageWidth, pageHeight := svg.pdf.GetPageSize()
+	width, height := svg.pagesExtent(fromPage, toPage)
+	gap := svg.pdf.PointConvert(previewPageGap)
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" width="%.2f%s" height="%.2f%s" viewBox="0 0 %.3f %.3f">`+"\n",
+		width, unit, height, unit, width, height)
+
+	for page := fromPage; page <= toPage; page++ {
+		offsetY := float64(page-fromPage) * (pageHeight + gap)
+		fmt.Fprintf(bw, `<g transform="translate(0 %.3f)">`+"\n", offsetY)
+		fmt.Fprintf(bw, `<rect x="0" y="0" width="%.3f" height="%.3f" fill="#ffffff"/>`+"\n", pageWidth, pageHeight)
+
+		for _, item := range svg.display.pages[page] {
+			svg.writeSVGItem(bw, item)
+		}
+
+		fmt.Fprint(bw, "</g>\n")
+	}
+
+	fmt.Fprint(bw, "</svg>\n")
+	return bw.Flush()
+}
+
+func (svg *SVGGenerator) writeSVGItem(w io.Writer, item displayItem) {
+	// the rotation of the pdf is counterclockwise, in svg it is clockwise
+	if t := item.transform; t != nil {
+		fmt.Fprintf(w, `<g transform="rotate(%.3f %.3f %.3f)" opacity="%.3f">`+"\n", -t.angle, t.cx, t.cy, t.opacity)
+		defer fmt.Fprint(w, "</g>\n")
+	}
+
+	switch item.kind {
+	case displayLine:
+		fmt.Fprintf(w, `<line x1="%.3f" y1="%.3f" x2="%.3f" y2="%.3f" stroke="%s" stroke-width="%.3f"%s/>`+"\n",
+			item.x, item.y, item.x2, item.y2, svgColor(item.lineColor), item.lineWidth, svgDash(item.dash, item.lineWidth))
+	case displayRect:
+		fill := "none"
+		if item.fill {
+			fill = svgColor(item.fillColor)
+		}
+		stroke := ""
+		if item.stroke {
+			stroke = fmt.Sprintf(` stroke="%s" stroke-width="%.3f"`, svgColor(item.lineColor), item.lineWidth)
+		}
+		fmt.Fprintf(w, `<rect x="%.3f" y="%.3f" width="%.3f" height="%.3f" fill="%s"%s/>`+"\n",
+			item.x, item.y, item.w, item.h, fill, stroke)
+	case displayShape:
+		element := "polyline"
+		if item.closed {
+			element = "polygon"
+		}
+		var points strings.Builder
+		for i, point := range item.points {
+			if i > 0 {
+				points.WriteByte(' ')
+			}
+			fmt.Fprintf(&points, "%.3f,%.3f", point.X, point.Y)
+		}
+		fill := "none"
+		if item.fill {
+			fill = svgColor(item.fillColor)
+		}
+		stroke := ""
+		if item.stroke {
+			stroke = fmt.Sprintf(` stroke="%s" stroke-width="%.3f"%s`, svgColor(item.lineColor), item.lineWidth, svgDash(item.dash, item.lineWidth))
+		}
+		fmt.Fprintf(w, `<%s points="%s" fill="%s"%s/>`+"\n", element, points.String(), fill, stroke)
+	case displayText:
+		fmt.Fprintf(w, `<text x="%.3f" y="%.3f" fill="%s" font-family="%s" font-size="%.3f"%s textLength="%.3f" lengthAdjust="spacingAndGlyphs" xml:space="preserve">%s</text>`+"\n",
+			item.x, item.y, svgColor(item.textColor), svgFontFamily(item.fontName), item.fontSize, svgFontStyle(item.styleStr), item.w, html.EscapeString(item.text))
+	case displayImage:
+		image, ok := svg.display.images[item.imageName]
+		if !ok {
+			return
+		}
+		mime := map[string]string{"jpg": "image/jpeg", "png": "image/png", "gif": "image/gif", "svg": "image/svg+xml"}[image.imageType]
+		fmt.Fprintf(w, `<image x="%.3f" y="%.3f" width="%.3f" height="%.3f" preserveAspectRatio="none" xlink:href="data:%s;base64,%s"/>`+"\n",
+			item.x, item.y, item.w, item.h, mime, base64.StdEncoding.EncodeToString(image.data))
+	}
+}
+
+func svgColor(c Color) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// svgDash returns the attributes of the dash style, see dashPattern.
+func svgDash(dash string, lineWidth float64) (attributes string) {
+	pattern := dashPattern(dash, lineWidth)
+	if pattern == nil {
+		return ""
+	}
+
+	attributes = fmt.Sprintf(` stroke-dasharray="%.3f %.3f"`, pattern[0], pattern[1])
+	if dash == LineDotted {
+		attributes += ` stroke-linecap="round"`
+	}
+	return attributes
+}
+
+func svgFontFamily(fontName string) string {
+	switch strings.ToLower(fontName) {
+	case "opensans":
+		return "'Open Sans', sans-serif"
+	case "courier":
+		return "Courier, monospace"
+	case "times":
+		return "Times, serif"
+	default:
+		return html.EscapeString(fontName) + ", sans-serif"
+	}
+}
+
+func svgFontStyle(styleStr string) (attributes string) {
+	style, _ := ParseFontStyle(styleStr)
+	if style.Weight != FontWeightRegular {
+		attributes += fmt.Sprintf(` font-weight="%d"`, style.Weight)
+	}
+
+	if style.Italic {
+		attributes += ` font-style="italic"`
+	}
+
+	return attributes
+}
@@ -0,0 +1,191 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	errorsWithStack "github.com/go-errors/errors"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestSVGGenerator_Output(t *testing.T) {
+	tests := []struct {
+		name      string
+		pages     int
+		wantTexts []string
+	}{
+		{
+			name:      "one page",
+			pages:     1,
+			wantTexts: []string{">page 1<", ">footer<"},
+		},
+		{
+			name:      "two pages with footer on each page",
+			pages:     2,
+			wantTexts: []string{">page 1<", ">page 2<", ">footer<"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var core *SVGGenerator
+			core, err := NewSVGGenerator(_defaultMetaData, false, &_logger, func() {}, func(isLastPage bool) {
+				core.SetUnsafeCursor(10, 280)
+				core.PrintPdfText("footer", "", "L")
+			})
+			if err != nil {
+				t.Errorf("init core error\n%s", err.Error())
+				return
+			}
+
+			for page := 1; page <= tt.pages; page++ {
+				core.NewPage()
+				core.SetCursor(10, 10)
+				core.PrintPdfText("page "+string(rune('0'+page)), "", "L")
+				core.DrawLine(10, 20, 100, 20, core.GetLineColor(), core.GetLineWidth())
+			}
+
+			var buf bytes.Buffer
+			if err = core.Output(&buf); err != nil {
+				t.Errorf("Output() error = %v", err)
+				return
+			}
+
+			svg := buf.String()
+			if !strings.HasPrefix(svg, "<svg") {
+				t.Errorf("Output() is not a svg image")
+			}
+			for _, text := range tt.wantTexts {
+				if !strings.Contains(svg, text) {
+					t.Errorf("Output() does not contain %s", text)
+				}
+			}
+			if got := strings.Count(svg, ">footer<"); got != tt.pages {
+				t.Errorf("Output() footers = %v, want %v", got, tt.pages)
+			}
+			if got := strings.Count(svg, "<line"); got != tt.pages {
+				t.Errorf("Output() lines = %v, want %v", got, tt.pages)
+			}
+		})
+	}
+}
+
+func TestSVGGenerator_Output_line(t *testing.T) {
+	tests := []struct {
+		name      string
+		color     Color
+		lineWidth float64
+		wantLine  string
+	}{
+		{name: "color and width", color: Color{R: 255}, lineWidth: .8, wantLine: `stroke="#ff0000" stroke-width="0.800"/>`},
+		{name: "zero width uses the default line width", color: Color{B: 255}, lineWidth: 0, wantLine: `stroke="#0000ff" stroke-width="0.300"/>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := _defaultMetaData
+			data.DefaultLineWidth = .3
+			core, err := NewSVGGenerator(data, false, &_logger, func() {}, func(isLastPage bool) {})
+			if err != nil {
+				t.Fatalf("init core error\n%s", err.Error())
+			}
+
+			core.NewPage()
+			lineColor, lineWidth := core.GetLineColor(), core.GetLineWidth()
+			core.DrawLine(10, 20, 100, 20, tt.color, tt.lineWidth)
+			// the next line is drawn in the line color and width again
+			core.DrawLine(10, 30, 100, 30, lineColor, lineWidth)
+
+			var buffer bytes.Buffer
+			if err = core.Output(&buffer); err != nil {
+				t.Fatalf("Output() error = %v", err)
+			}
+
+			lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+			var gotLines []string
+			for _, line := range lines {
+				if strings.HasPrefix(line, "<line") {
+					gotLines = append(gotLines, line)
+				}
+			}
+			if len(gotLines) != 2 {
+				t.Fatalf("Output() lines = %q, want 2 lines", gotLines)
+			}
+			if !strings.HasSuffix(gotLines[0], tt.wantLine) {
+				t.Errorf("Output() line = %s, want %s", gotLines[0], tt.wantLine)
+			}
+			if wantLine := fmt.Sprintf(`stroke="%s" stroke-width="%.3f"/>`, svgColor(lineColor), lineWidth); !strings.HasSuffix(gotLines[1], wantLine) {
+				t.Errorf("Output() next line = %s, want %s", gotLines[1], wantLine)
+			}
+		})
+	}
+}
+
+func TestPNGGenerator_Output(t *testing.T) {
+	tests := []struct {
+		name       string
+		page       int
+		dpi        float64
+		wantWidth  int
+		wantHeight int
+		wantErr    bool
+	}{
+		{name: "first page", page: 1, dpi: 72, wantWidth: 596, wantHeight: 842},
+		{name: "all pages", page: 0, dpi: 36, wantWidth: 298, wantHeight: 848},
+		{name: "page out of range", page: 3, dpi: 72, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, err := NewPNGGenerator(_defaultMetaData, false, &_logger, func() {}, func(isLastPage bool) {}, tt.page, tt.dpi)
+			if err != nil {
+				t.Errorf("init core error\n%s", err.Error())
+				return
+			}
+
+			core.NewPage()
+			core.DrawLine(10, 20, 100, 20, core.GetLineColor(), core.GetLineWidth())
+			core.NewPage()
+
+			var buf bytes.Buffer
+			err = core.Output(&buf)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Output() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			img, err := png.Decode(&buf)
+			if err != nil {
+				t.Errorf("Output() is not a png image: %v", err)
+				return
+			}
+			if got := img.Bounds(); got.Dx() != tt.wantWidth || got.Dy() != tt.wantHeight {
+				t.Errorf("Output() size = %vx%v, want %vx%v", got.Dx(), got.Dy(), tt.wantWidth, tt.wantHeight)
+			}
+		})
+	}
+}
+
+func TestPNGGenerator_Output_tooLarge(t *testing.T) {
+	core, err := NewPNGGenerator(_defaultMetaData, false, &_logger, func() {}, func(isLastPage bool) {}, 0, 600)
+	if err != nil {
+		t.Fatalf("init core error\n%s", err.Error())
+	}
+
+	// three DIN A4 pages at 600 dpi have more than MaxPreviewPixels
+	for page := 1; page <= 3; page++ {
+		core.NewPage()
+	}
+
+	var buf bytes.Buffer
+	if err = core.Output(&buf); !errorsWithStack.Is(err, ErrPreviewTooLarge) {
+		t.Errorf("Output() error = %v, want %v", err, ErrPreviewTooLarge)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Output() wrote %d bytes", buf.Len())
+	}
+}
@@ -0,0 +1,1083 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	errorsWithStack "github.com/go-errors/errors"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// svgImage is a parsed SVG image, which is drawn as vector graphic into the PDF (see RegisterImage).
+//
+// Supported are paths, the basic shapes rect, circle, ellipse, line, polyline and polygon, groups with transforms,
+// <use> references, fills, strokes and opacities as attributes, style attributes or class rules of a <style> element.
+// Texts must be converted to paths, gradients are filled with their first stop color.
+type svgImage struct {
+	// width and height are the size of the image in CSS pixels (1/96 in).
+	width  float64
+	height float64
+	shapes []svgShape
+}
+
+// svgShape is a painted path of an svgImage in the coordinates of the image size.
+// fill and stroke are nil, if the path is not filled or stroked.
+type svgShape struct {
+	subpaths      []svgSubpath
+	fill          *Color
+	fillEvenOdd   bool
+	fillOpacity   float64
+	stroke        *Color
+	strokeWidth   float64
+	strokeOpacity float64
+	lineCap       string
+	lineJoin      string
+}
+
+type svgPoint struct {
+	x float64
+	y float64
+}
+
+// svgSegment is a straight line or a cubic Bézier curve with the control points c1 and c2 to the point end.
+type svgSegment struct {
+	line bool
+	c1   svgPoint
+	c2   svgPoint
+	end  svgPoint
+}
+
+type svgSubpath struct {
+	start    svgPoint
+	segments []svgSegment
+	closed   bool
+}
+
+// svgMatrix is an affine transformation [a b c d e f] like the SVG transform matrix().
+type svgMatrix [6]float64
+
+var svgIdentity = svgMatrix{1, 0, 0, 1, 0, 0}
+
+// multiply returns the transformation, which applies n first and m afterwards.
+func (m svgMatrix) multiply(n svgMatrix) svgMatrix {
+	return svgMatrix{
+		m[0]*n[0] + m[2]*n[1],
+		m[1]*n[0] + m[3]*n[1],
+		m[0]*n[2] + m[2]*n[3],
+		m[1]*n[2] + m[3]*n[3],
+		m[0]*n[4] + m[2]*n[5] + m[4],
+		m[1]*n[4] + m[3]*n[5] + m[5],
+	}
+}
+
+func (m svgMatrix) apply(p svgPoint) svgPoint {
+	return svgPoint{x: m[0]*p.x + m[2]*p.y + m[4], y: m[1]*p.x + m[3]*p.y + m[5]}
+}
+
+// scale returns the mean scaling factor, e.g. to scale the stroke width.
+func (m svgMatrix) scale() float64 {
+	return math.Sqrt(math.Abs(m[0]*m[3] - m[1]*m[2]))
+}
+
+// svgNode is an element of the SVG document.
+type svgNode struct {
+	name     string
+	attrs    map[string]string
+	children []*svgNode
+	text     string
+}
+
+// svgStyleProperties are the supported presentation attributes and style properties.
+var svgStyleProperties = []string{"fill", "fill-rule", "fill-opacity", "stroke", "stroke-width", "stroke-opacity",
+	"stroke-linecap", "stroke-linejoin", "opacity", "display", "visibility", "stop-color"}
+
+// svgNamedColors are the most common SVG color keywords.
+var svgNamedColors = map[string]Color{
+	"black": {R: 0, G: 0, B: 0}, "white": {R: 255, G: 255, B: 255}, "red": {R: 255, G: 0, B: 0},
+	"green": {R: 0, G: 128, B: 0}, "blue": {R: 0, G: 0, B: 255}, "yellow": {R: 255, G: 255, B: 0},
+	"orange": {R: 255, G: 165, B: 0}, "purple": {R: 128, G: 0, B: 128}, "gray": {R: 128, G: 128, B: 128},
+	"grey": {R: 128, G: 128, B: 128}, "silver": {R: 192, G: 192, B: 192}, "navy": {R: 0, G: 0, B: 128},
+	"teal": {R: 0, G: 128, B: 128}, "maroon": {R: 128, G: 0, B: 0}, "olive": {R: 128, G: 128, B: 0},
+	"lime": {R: 0, G: 255, B: 0}, "aqua": {R: 0, G: 255, B: 255}, "cyan": {R: 0, G: 255, B: 255},
+	"fuchsia": {R: 255, G: 0, B: 255}, "magenta": {R: 255, G: 0, B: 255}, "darkgray": {R: 169, G: 169, B: 169},
+	"lightgray": {R: 211, G: 211, B: 211}, "darkblue": {R: 0, G: 0, B: 139}, "darkred": {R: 139, G: 0, B: 0},
+	"darkgreen": {R: 0, G: 100, B: 0},
+}
+
+// svgUnits maps the length units to CSS pixels.
+var svgUnits = map[string]float64{"": 1, "px": 1, "pt": 4. / 3, "pc": 16, "mm": 96 / 25.4, "cm": 96 / 2.54, "in": 96}
+
+// svgMaxUseDepth limits the nesting of <use> references, which may reference each other.
+const svgMaxUseDepth = 8
+
+// svgMaxElements, svgMaxShapes and svgMaxSegments limit the image expanded by the <use> references.
+// A small image can reference a group of 10 references to a group of 10 references and so on,
+// so the nesting depth alone does not limit the number of shapes.
+const (
+	svgMaxElements = 50000
+	svgMaxShapes   = 20000
+	svgMaxSegments = 500000
+)
+
+var (
+	svgTransformRegexp = regexp.MustCompile(`([a-zA-Z]+)\s*\(([^)]*)\)`)
+	svgCommentRegexp   = regexp.MustCompile(`(?s)/\*.*?\*/`)
+)
+
+// svgParser converts the svgNode tree into the shapes of an svgImage.
+type svgParser struct {
+	ids         map[string]*svgNode
+	classRules  map[string][][2]string
+	stopColors  map[string]*Color
+	shapes      []svgShape
+	useDepth    int
+	parseErrors []string
+	// elements and segments count the expanded image, limitErr is set, if a limit is exceeded
+	elements int
+	segments int
+	limitErr error
+}
+
+// parseSVGImage parses the SVG document data.
+func parseSVGImage(data []byte) (*svgImage, error) {
+	root, err := parseSVGNodes(data)
+	if err != nil {
+		return nil, err
+	}
+
+	image := &svgImage{}
+	viewBox := svgNumbers(root.attrs["viewBox"])
+	if len(viewBox) != 4 || viewBox[2] <= 0 || viewBox[3] <= 0 {
+		viewBox = nil
+	}
+
+	width, hasWidth := svgLength(root.attrs["width"])
+	height, hasHeight := svgLength(root.attrs["height"])
+	switch {
+	case hasWidth && hasHeight:
+	case viewBox == nil:
+		return nil, errorsWithStack.New(fmt.Sprintf("the svg image has neither a width and height nor a viewBox"))
+	case hasWidth:
+		height = width * viewBox[3] / viewBox[2]
+	case hasHeight:
+		width = height * viewBox[2] / viewBox[3]
+	default:
+		width, height = viewBox[2], viewBox[3]
+	}
+	if width <= 0 || height <= 0 {
+		return nil, errorsWithStack.New(fmt.Sprintf("the svg image has no size"))
+	}
+	image.width, image.height = width, height
+
+	matrix := svgIdentity
+	if viewBox != nil {
+		scaleX, scaleY := width/viewBox[2], height/viewBox[3]
+		if !strings.HasPrefix(strings.TrimSpace(root.attrs["preserveAspectRatio"]), "none") {
+			// the default xMidYMid meet centers the view box
+			scaleX = math.Min(scaleX, scaleY)
+			scaleY = scaleX
+		}
+		matrix = svgMatrix{scaleX, 0, 0, scaleY,
+			(width-viewBox[2]*scaleX)/2 - viewBox[0]*scaleX,
+			(height-viewBox[3]*scaleY)/2 - viewBox[1]*scaleY}
+	}
+
+	parser := &svgParser{ids: map[string]*svgNode{}, classRules: map[string][][2]string{}, stopColors: map[string]*Color{}}
+	parser.collect(root)
+	parser.walkChildren(root, matrix, parser.style(root, map[string]string{}), 1)
+	if parser.limitErr != nil {
+		return nil, parser.limitErr
+	}
+	if len(parser.parseErrors) > 0 {
+		return nil, errorsWithStack.New(fmt.Sprintf("the svg image can't be parsed: %s", strings.Join(parser.parseErrors, ", ")))
+	}
+
+	image.shapes = parser.shapes
+	return image, nil
+}
+
+// parseSVGNodes parses the XML of an SVG document into a tree of nodes with the svg element as root.
+func parseSVGNodes(data []byte) (root *svgNode, err error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	// SVG editors write entities of the doctype into the attributes
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+
+	var stack []*svgNode
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errorsWithStack.New(fmt.Sprintf("the svg image is no valid XML: %s", err.Error()))
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			node := &svgNode{name: t.Name.Local, attrs: map[string]string{}}
+			for _, attr := range t.Attr {
+				node.attrs[attr.Name.Local] = attr.Value
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, node)
+			} else if root == nil {
+				root = node
+			}
+			stack = append(stack, node)
+		case xml.EndElement:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += string(t)
+			}
+		}
+	}
+
+	if root == nil || root.name != "svg" {
+		return nil, errorsWithStack.New(fmt.Sprintf("the image is no svg image"))
+	}
+	return root, nil
+}
+
+// collect registers the ids, the class rules of the <style> elements and the first stop colors of the gradients.
+func (parser *svgParser) collect(node *svgNode) {
+	if id := node.attrs["id"]; id != "" {
+		parser.ids[id] = node
+	}
+
+	switch node.name {
+	case "style":
+		parser.addClassRules(node.text)
+	case "linearGradient", "radialGradient":
+		for _, child := range node.children {
+			if child.name != "stop" {
+				continue
+			}
+			stopColor := parser.style(child, map[string]string{})["stop-color"]
+			if stopColor == "" {
+				stopColor = "black"
+			}
+			parser.stopColors[node.attrs["id"]] = parser.color(stopColor)
+			break
+		}
+	}
+
+	for _, child := range node.children {
+		parser.collect(child)
+	}
+}
+
+// addClassRules adds the rules of simple class selectors like ".st0, .st1 { fill: #1a4d8f }" of a style sheet.
+func (parser *svgParser) addClassRules(styleSheet string) {
+	styleSheet = svgCommentRegexp.ReplaceAllString(styleSheet, "")
+	for _, rule := range strings.Split(styleSheet, "}") {
+		selectors, declarations, found := strings.Cut(rule, "{")
+		if !found {
+			continue
+		}
+		for _, selector := range strings.Split(selectors, ",") {
+			selector = strings.TrimSpace(selector)
+			if !strings.HasPrefix(selector, ".") || strings.ContainsAny(selector, " >:[") {
+				continue
+			}
+			class := selector[1:]
+			parser.classRules[class] = append(parser.classRules[class], svgDeclarations(declarations)...)
+		}
+	}
+}
+
+// svgDeclarations returns the supported properties of a style declaration like "fill: red; stroke: none".
+func svgDeclarations(declarations string) (properties [][2]string) {
+	for _, declaration := range strings.Split(declarations, ";") {
+		property, value, found := strings.Cut(declaration, ":")
+		if !found {
+			continue
+		}
+		property, value = strings.TrimSpace(property), strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), "!important"))
+		for _, supported := range svgStyleProperties {
+			if property == supported {
+				properties = append(properties, [2]string{property, value})
+			}
+		}
+	}
+	return properties
+}
+
+// style returns the properties of node: the inherited properties overwritten by the presentation attributes,
+// the class rules and the style attribute.
+func (parser *svgParser) style(node *svgNode, inherited map[string]string) map[string]string {
+	style := map[string]string{}
+	for property, value := range inherited {
+		style[property] = value
+	}
+	// opacity and display are not inherited
+	delete(style, "opacity")
+	delete(style, "display")
+
+	var properties [][2]string
+	for _, property := range svgStyleProperties {
+		if value, ok := node.attrs[property]; ok {
+			properties = append(properties, [2]string{property, value})
+		}
+	}
+	for _, class := range strings.Fields(node.attrs["class"]) {
+		properties = append(properties, parser.classRules[class]...)
+	}
+	properties = append(properties, svgDeclarations(node.attrs["style"])...)
+
+	for _, property := range properties {
+		if value := strings.TrimSpace(property[1]); value != "inherit" && value != "" {
+			style[property[0]] = value
+		}
+	}
+	return style
+}
+
+func (parser *svgParser) walkChildren(node *svgNode, matrix svgMatrix, style map[string]string, opacity float64) {
+	for _, child := range node.children {
+		parser.walk(child, matrix, style, opacity)
+	}
+}
+
+// walk adds the shapes of node and its children. It stops, if the image exceeds svgMaxElements.
+func (parser *svgParser) walk(node *svgNode, matrix svgMatrix, inherited map[string]string, opacity float64) {
+	if parser.limitErr != nil {
+		return
+	}
+	parser.elements++
+	if parser.elements > svgMaxElements {
+		parser.limitErr = errorsWithStack.New(fmt.Sprintf("the svg image has more than %d elements", svgMaxElements))
+		return
+	}
+
+	style := parser.style(node, inherited)
+	if style["display"] == "none" {
+		return
+	}
+	opacity *= svgNumber(style["opacity"], 1)
+	matrix = matrix.multiply(parser.transform(node.attrs["transform"]))
+
+	attr := func(name string) float64 {
+		value, _ := svgLength(node.attrs[name])
+		return value
+	}
+
+	path := &svgPathBuilder{}
+	switch node.name {
+	case "g", "a", "switch":
+		parser.walkChildren(node, matrix, style, opacity)
+		return
+	case "svg":
+		parser.walkChildren(node, matrix.multiply(svgMatrix{1, 0, 0, 1, attr("x"), attr("y")}), style, opacity)
+		return
+	case "use":
+		target := parser.ids[strings.TrimPrefix(node.attrs["href"], "#")]
+		if target == nil || parser.useDepth >= svgMaxUseDepth {
+			return
+		}
+		parser.useDepth++
+		matrix = matrix.multiply(svgMatrix{1, 0, 0, 1, attr("x"), attr("y")})
+		if target.name == "symbol" {
+			parser.walkChildren(target, matrix, parser.style(target, style), opacity)
+		} else {
+			parser.walk(target, matrix, style, opacity)
+		}
+		parser.useDepth--
+		return
+	case "path":
+		if err := path.parse(node.attrs["d"]); err != nil {
+			parser.parseErrors = append(parser.parseErrors, err.Error())
+		}
+	case "rect":
+		path.rect(attr("x"), attr("y"), attr("width"), attr("height"), node.attrs["rx"], node.attrs["ry"])
+	case "circle":
+		path.ellipse(attr("cx"), attr("cy"), attr("r"), attr("r"))
+	case "ellipse":
+		path.ellipse(attr("cx"), attr("cy"), attr("rx"), attr("ry"))
+	case "line":
+		path.moveTo(svgPoint{x: attr("x1"), y: attr("y1")})
+		path.lineTo(svgPoint{x: attr("x2"), y: attr("y2")})
+	case "polyline", "polygon":
+		points := svgNumbers(node.attrs["points"])
+		for i := 0; i+1 < len(points); i += 2 {
+			if i == 0 {
+				path.moveTo(svgPoint{x: points[i], y: points[i+1]})
+			} else {
+				path.lineTo(svgPoint{x: points[i], y: points[i+1]})
+			}
+		}
+		if node.name == "polygon" {
+			path.closePath()
+		}
+	default:
+		// e.g. defs, symbol, clipPath, text or image
+		return
+	}
+
+	// invisible shapes are counted too, each reference parses the path again
+	for _, subpath := range path.subpaths {
+		parser.segments += len(subpath.segments)
+	}
+	if parser.segments > svgMaxSegments {
+		parser.limitErr = errorsWithStack.New(fmt.Sprintf("the svg image has more than %d path segments", svgMaxSegments))
+		return
+	}
+
+	parser.addShape(path.subpaths, matrix, style, opacity, node.name != "line")
+}
+
+// addShape adds the subpaths transformed by matrix as painted shape.
+func (parser *svgParser) addShape(subpaths []svgSubpath, matrix svgMatrix, style map[string]string, opacity float64, fillable bool) {
+	if len(subpaths) == 0 || style["visibility"] == "hidden" || style["visibility"] == "collapse" {
+		return
+	}
+
+	shape := svgShape{
+		fillEvenOdd:   style["fill-rule"] == "evenodd",
+		fillOpacity:   opacity * svgNumber(style["fill-opacity"], 1),
+		strokeOpacity: opacity * svgNumber(style["stroke-opacity"], 1),
+		lineCap:       style["stroke-linecap"],
+		lineJoin:      style["stroke-linejoin"],
+	}
+	if fillable {
+		fill, ok := style["fill"]
+		if !ok {
+			fill = "black"
+		}
+		shape.fill = parser.color(fill)
+	}
+	if stroke, ok := style["stroke"]; ok {
+		shape.stroke = parser.color(stroke)
+	}
+	strokeWidth, ok := svgLength(style["stroke-width"])
+	if !ok {
+		strokeWidth = 1
+	}
+	shape.strokeWidth = strokeWidth * matrix.scale()
+	if shape.strokeWidth <= 0 {
+		shape.stroke = nil
+	}
+	if shape.fill == nil && shape.stroke == nil {
+		return
+	}
+
+	if len(parser.shapes) >= svgMaxShapes {
+		parser.limitErr = errorsWithStack.New(fmt.Sprintf("the svg image has more than %d shapes", svgMaxShapes))
+		return
+	}
+
+	for _, subpath := range subpaths {
+		transformed := svgSubpath{start: matrix.apply(subpath.start), closed: subpath.closed}
+		for _, segment := range subpath.segments {
+			transformed.segments = append(transformed.segments, svgSegment{
+				line: segment.line,
+				c1:   matrix.apply(segment.c1),
+				c2:   matrix.apply(segment.c2),
+				end:  matrix.apply(segment.end),
+			})
+		}
+		shape.subpaths = append(shape.subpaths, transformed)
+	}
+	parser.shapes = append(parser.shapes, shape)
+}
+
+// color returns the color of a fill or stroke value or nil for "none".
+// A gradient is replaced by its first stop color, unknown colors are black.
+func (parser *svgParser) color(value string) *Color {
+	value = strings.ToLower(strings.TrimSpace(value))
+	switch {
+	case value == "none" || value == "transparent":
+		return nil
+	case strings.HasPrefix(value, "url("):
+		id := strings.TrimPrefix(strings.Trim(strings.Split(value[4:], ")")[0], " '\""), "#")
+		if stopColor, ok := parser.stopColors[id]; ok {
+			return stopColor
+		}
+		// a gradient may reference the stops of another gradient
+		if gradient := parser.ids[id]; gradient != nil {
+			if stopColor, ok := parser.stopColors[strings.TrimPrefix(gradient.attrs["href"], "#")]; ok {
+				return stopColor
+			}
+		}
+		return nil
+	case strings.HasPrefix(value, "#"):
+		hexStr := value[1:]
+		if len(hexStr) == 3 {
+			hexStr = string([]byte{hexStr[0], hexStr[0], hexStr[1], hexStr[1], hexStr[2], hexStr[2]})
+		}
+		if rgb, err := strconv.ParseUint(hexStr, 16, 32); err == nil && len(hexStr) == 6 {
+			return &Color{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb)}
+		}
+	case strings.HasPrefix(value, "rgb(") || strings.HasPrefix(value, "rgba("):
+		channels := strings.Split(strings.TrimSuffix(value[strings.Index(value, "(")+1:], ")"), ",")
+		if len(channels) >= 3 {
+			var rgb [3]uint8
+			for i := range rgb {
+				channel := strings.TrimSpace(channels[i])
+				number := svgNumber(strings.TrimSuffix(channel, "%"), 0)
+				if strings.HasSuffix(channel, "%") {
+					number = number * 255 / 100
+				}
+				rgb[i] = uint8(math.Max(0, math.Min(255, math.Round(number))))
+			}
+			return &Color{R: rgb[0], G: rgb[1], B: rgb[2]}
+		}
+	default:
+		if named, ok := svgNamedColors[value]; ok {
+			return &named
+		}
+	}
+
+	return &Color{}
+}
+
+// transform parses the transform attribute, e.g. "translate(10 20) rotate(45)".
+func (parser *svgParser) transform(value string) svgMatrix {
+	matrix := svgIdentity
+	for _, match := range svgTransformRegexp.FindAllStringSubmatch(value, -1) {
+		args := svgNumbers(match[2])
+		arg := func(i int, fallback float64) float64 {
+			if i < len(args) {
+				return args[i]
+			}
+			return fallback
+		}
+
+		var step svgMatrix
+		switch match[1] {
+		case "matrix":
+			if len(args) != 6 {
+				continue
+			}
+			copy(step[:], args)
+		case "translate":
+			step = svgMatrix{1, 0, 0, 1, arg(0, 0), arg(1, 0)}
+		case "scale":
+			step = svgMatrix{arg(0, 1), 0, 0, arg(1, arg(0, 1)), 0, 0}
+		case "rotate":
+			angle := arg(0, 0) * math.Pi / 180
+			cx, cy := arg(1, 0), arg(2, 0)
+			step = svgMatrix{1, 0, 0, 1, cx, cy}.
+				multiply(svgMatrix{math.Cos(angle), math.Sin(angle), -math.Sin(angle), math.Cos(angle), 0, 0}).
+				multiply(svgMatrix{1, 0, 0, 1, -cx, -cy})
+		case "skewX":
+			step = svgMatrix{1, 0, math.Tan(arg(0, 0) * math.Pi / 180), 1, 0, 0}
+		case "skewY":
+			step = svgMatrix{1, math.Tan(arg(0, 0) * math.Pi / 180), 0, 1, 0, 0}
+		default:
+			continue
+		}
+		matrix = matrix.multiply(step)
+	}
+	return matrix
+}
+
+// svgLength parses a length with an absolute unit into CSS pixels. Percentages are not supported.
+func svgLength(value string) (length float64, ok bool) {
+	value = strings.TrimSpace(value)
+	number := strings.TrimRight(value, "abcdefghijklmnopqrstuvwxyz%")
+	unitFactor, knownUnit := svgUnits[value[len(number):]]
+	length, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+	if err != nil || !knownUnit {
+		return 0, false
+	}
+	return length * unitFactor, true
+}
+
+// svgNumber parses a number or returns fallback.
+func svgNumber(value string, fallback float64) float64 {
+	number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return fallback
+	}
+	return number
+}
+
+// svgNumbers parses a list of numbers separated by whitespace or commas, e.g. a viewBox or the points of a polygon.
+func svgNumbers(value string) (numbers []float64) {
+	scanner := svgPathScanner{d: value}
+	for scanner.hasNumber() {
+		number, err := scanner.number()
+		if err != nil {
+			break
+		}
+		numbers = append(numbers, number)
+	}
+	return numbers
+}
+
+// svgPathScanner reads the commands and numbers of the path data.
+type svgPathScanner struct {
+	d   string
+	pos int
+}
+
+func (scanner *svgPathScanner) skipSeparators() {
+	for scanner.pos < len(scanner.d) && strings.IndexByte(" \t\r\n,", scanner.d[scanner.pos]) >= 0 {
+		scanner.pos++
+	}
+}
+
+func (scanner *svgPathScanner) hasNumber() bool {
+	scanner.skipSeparators()
+	return scanner.pos < len(scanner.d) && strings.IndexByte("+-.0123456789", scanner.d[scanner.pos]) >= 0
+}
+
+// number reads a number like "-1.5e-3". A second decimal point starts the next number, e.g. "1.5.5".
+func (scanner *svgPathScanner) number() (float64, error) {
+	scanner.skipSeparators()
+	start := scanner.pos
+	isDigit := func() bool {
+		return scanner.pos < len(scanner.d) && scanner.d[scanner.pos] >= '0' && scanner.d[scanner.pos] <= '9'
+	}
+
+	if scanner.pos < len(scanner.d) && (scanner.d[scanner.pos] == '+' || scanner.d[scanner.pos] == '-') {
+		scanner.pos++
+	}
+	for isDigit() {
+		scanner.pos++
+	}
+	if scanner.pos < len(scanner.d) && scanner.d[scanner.pos] == '.' {
+		scanner.pos++
+		for isDigit() {
+			scanner.pos++
+		}
+	}
+	if scanner.pos < len(scanner.d) && (scanner.d[scanner.pos] == 'e' || scanner.d[scanner.pos] == 'E') {
+		exponentStart := scanner.pos
+		scanner.pos++
+		if scanner.pos < len(scanner.d) && (scanner.d[scanner.pos] == '+' || scanner.d[scanner.pos] == '-') {
+			scanner.pos++
+		}
+		if !isDigit() {
+			scanner.pos = exponentStart
+		}
+		for isDigit() {
+			scanner.pos++
+		}
+	}
+
+	number, err := strconv.ParseFloat(scanner.d[start:scanner.pos], 64)
+	if err != nil {
+		return 0, errorsWithStack.New(fmt.Sprintf("invalid number at position %d of the path data", start))
+	}
+	return number, nil
+}
+
+// flag reads an arc flag, which may be written without separator, e.g. "a1 1 0 01 1 1".
+func (scanner *svgPathScanner) flag() (bool, error) {
+	scanner.skipSeparators()
+	if scanner.pos < len(scanner.d) && (scanner.d[scanner.pos] == '0' || scanner.d[scanner.pos] == '1') {
+		scanner.pos++
+		return scanner.d[scanner.pos-1] == '1', nil
+	}
+	return false, errorsWithStack.New(fmt.Sprintf("invalid arc flag at position %d of the path data", scanner.pos))
+}
+
+// svgPathParameters is the number of parameters of the path commands.
+var svgPathParameters = map[byte]int{'m': 2, 'l': 2, 'h': 1, 'v': 1, 'c': 6, 's': 4, 'q': 4, 't': 2, 'a': 7}
+
+// svgPathBuilder builds the subpaths of a path, all curves are converted to cubic Bézier curves.
+type svgPathBuilder struct {
+	subpaths    []svgSubpath
+	current     svgPoint
+	lastControl svgPoint
+	lastCommand byte
+}
+
+func (path *svgPathBuilder) moveTo(p svgPoint) {
+	path.subpaths = append(path.subpaths, svgSubpath{start: p})
+	path.current = p
+}
+
+// subpath returns the current subpath. After a closed subpath, a new subpath starts at the current point.
+func (path *svgPathBuilder) subpath() *svgSubpath {
+	if len(path.subpaths) == 0 || path.subpaths[len(path.subpaths)-1].closed {
+		path.moveTo(path.current)
+	}
+	return &path.subpaths[len(path.subpaths)-1]
+}
+
+func (path *svgPathBuilder) lineTo(p svgPoint) {
+	subpath := path.subpath()
+	subpath.segments = append(subpath.segments, svgSegment{line: true, c1: path.current, c2: p, end: p})
+	path.current = p
+}
+
+func (path *svgPathBuilder) cubicTo(c1 svgPoint, c2 svgPoint, p svgPoint) {
+	subpath := path.subpath()
+	subpath.segments = append(subpath.segments, svgSegment{c1: c1, c2: c2, end: p})
+	path.current = p
+}
+
+// quadTo adds a quadratic Bézier curve as cubic curve.
+func (path *svgPathBuilder) quadTo(q svgPoint, p svgPoint) {
+	p0 := path.current
+	path.cubicTo(
+		svgPoint{x: p0.x + 2./3*(q.x-p0.x), y: p0.y + 2./3*(q.y-p0.y)},
+		svgPoint{x: p.x + 2./3*(q.x-p.x), y: p.y + 2./3*(q.y-p.y)},
+		p)
+}
+
+func (path *svgPathBuilder) closePath() {
+	if len(path.subpaths) == 0 {
+		return
+	}
+	subpath := &path.subpaths[len(path.subpaths)-1]
+	subpath.closed = true
+	path.current = subpath.start
+}
+
+// arcTo adds an elliptical arc like the SVG arc command as cubic curves of at most 90 degrees,
+// see the endpoint to center conversion of the SVG specification (appendix F.6.5).
+func (path *svgPathBuilder) arcTo(rx float64, ry float64, angle float64, largeArc bool, sweep bool, p svgPoint) {
+	p0 := path.current
+	if p0 == p {
+		return
+	}
+	rx, ry = math.Abs(rx), math.Abs(ry)
+	if rx == 0 || ry == 0 {
+		path.lineTo(p)
+		return
+	}
+
+	phi := angle * math.Pi / 180
+	cosPhi, sinPhi := math.Cos(phi), math.Sin(phi)
+	dx, dy := (p0.x-p.x)/2, (p0.y-p.y)/2
+	x1 := cosPhi*dx + sinPhi*dy
+	y1 := -sinPhi*dx + cosPhi*dy
+
+	// too small radii are scaled up, until the arc reaches the end point
+	if lambda := x1*x1/(rx*rx) + y1*y1/(ry*ry); lambda > 1 {
+		rx, ry = rx*math.Sqrt(lambda), ry*math.Sqrt(lambda)
+	}
+
+	numerator := rx*rx*ry*ry - rx*rx*y1*y1 - ry*ry*x1*x1
+	denominator := rx*rx*y1*y1 + ry*ry*x1*x1
+	coefficient := 0.
+	if numerator > 0 && denominator > 0 {
+		coefficient = math.Sqrt(numerator / denominator)
+	}
+	if largeArc == sweep {
+		coefficient = -coefficient
+	}
+	cx1, cy1 := coefficient*rx*y1/ry, -coefficient*ry*x1/rx
+	cx := cosPhi*cx1 - sinPhi*cy1 + (p0.x+p.x)/2
+	cy := sinPhi*cx1 + cosPhi*cy1 + (p0.y+p.y)/2
+
+	theta := math.Atan2((y1-cy1)/ry, (x1-cx1)/rx)
+	deltaTheta := math.Atan2((-y1-cy1)/ry, (-x1-cx1)/rx) - theta
+	if sweep && deltaTheta < 0 {
+		deltaTheta += 2 * math.Pi
+	} else if !sweep && deltaTheta > 0 {
+		deltaTheta -= 2 * math.Pi
+	}
+
+	point := func(t float64) svgPoint {
+		ex, ey := rx*math.Cos(t), ry*math.Sin(t)
+		return svgPoint{x: cx + cosPhi*ex - sinPhi*ey, y: cy + sinPhi*ex + cosPhi*ey}
+	}
+	tangent := func(t float64) svgPoint {
+		ex, ey := -rx*math.Sin(t), ry*math.Cos(t)
+		return svgPoint{x: cosPhi*ex - sinPhi*ey, y: sinPhi*ex + cosPhi*ey}
+	}
+
+	segments := int(math.Ceil(math.Abs(deltaTheta) / (math.Pi / 2)))
+	step := deltaTheta / float64(segments)
+	k := 4. / 3 * math.Tan(step/4)
+	for i := 0; i < segments; i++ {
+		t1, t2 := theta+float64(i)*step, theta+float64(i+1)*step
+		a, b := point(t1), point(t2)
+		if i == segments-1 {
+			b = p
+		}
+		da, db := tangent(t1), tangent(t2)
+		path.cubicTo(svgPoint{x: a.x + k*da.x, y: a.y + k*da.y}, svgPoint{x: b.x - k*db.x, y: b.y - k*db.y}, b)
+	}
+}
+
+// rect adds a rectangle with optional rounded corners.
+func (path *svgPathBuilder) rect(x float64, y float64, w float64, h float64, rxStr string, ryStr string) {
+	if w <= 0 || h <= 0 {
+		return
+	}
+
+	rx, hasRx := svgLength(rxStr)
+	ry, hasRy := svgLength(ryStr)
+	if !hasRx {
+		rx = ry
+	}
+	if !hasRy {
+		ry = rx
+	}
+	rx, ry = math.Min(math.Max(rx, 0), w/2), math.Min(math.Max(ry, 0), h/2)
+
+	if rx == 0 || ry == 0 {
+		path.moveTo(svgPoint{x: x, y: y})
+		path.lineTo(svgPoint{x: x + w, y: y})
+		path.lineTo(svgPoint{x: x + w, y: y + h})
+		path.lineTo(svgPoint{x: x, y: y + h})
+		path.closePath()
+		return
+	}
+
+	path.moveTo(svgPoint{x: x + rx, y: y})
+	path.lineTo(svgPoint{x: x + w - rx, y: y})
+	path.arcTo(rx, ry, 0, false, true, svgPoint{x: x + w, y: y + ry})
+	path.lineTo(svgPoint{x: x + w, y: y + h - ry})
+	path.arcTo(rx, ry, 0, false, true, svgPoint{x: x + w - rx, y: y + h})
+	path.lineTo(svgPoint{x: x + rx, y: y + h})
+	path.arcTo(rx, ry, 0, false, true, svgPoint{x: x, y: y + h - ry})
+	path.lineTo(svgPoint{x: x, y: y + ry})
+	path.arcTo(rx, ry, 0, false, true, svgPoint{x: x + rx, y: y})
+	path.closePath()
+}
+
+func (path *svgPathBuilder) ellipse(cx float64, cy float64, rx float64, ry float64) {
+	if rx <= 0 || ry <= 0 {
+		return
+	}
+
+	path.moveTo(svgPoint{x: cx + rx, y: cy})
+	path.arcTo(rx, ry, 0, false, true, svgPoint{x: cx - rx, y: cy})
+	path.arcTo(rx, ry, 0, false, true, svgPoint{x: cx + rx, y: cy})
+	path.closePath()
+}
+
+// parse adds the subpaths of the path data d, e.g. "M10 10 h 20 v 20 z".
+func (path *svgPathBuilder) parse(d string) error {
+	scanner := svgPathScanner{d: d}
+	for {
+		scanner.skipSeparators()
+		if scanner.pos >= len(d) {
+			return nil
+		}
+
+		command := d[scanner.pos]
+		if strings.IndexByte("MmLlHhVvCcSsQqTtAaZz", command) < 0 {
+			return errorsWithStack.New(fmt.Sprintf("invalid path command \"%c\" at position %d", command, scanner.pos))
+		}
+		if len(path.subpaths) == 0 && command != 'M' && command != 'm' {
+			return errorsWithStack.New(fmt.Sprintf("the path data must start with a move command"))
+		}
+		scanner.pos++
+
+		if command == 'Z' || command == 'z' {
+			path.closePath()
+			path.lastCommand = command
+			continue
+		}
+
+		// the parameters of a command may be repeated
+		for first := true; first || scanner.hasNumber(); first = false {
+			if err := path.command(&scanner, command, first); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// command reads the parameters of one path command and adds the segment.
+func (path *svgPathBuilder) command(scanner *svgPathScanner, command byte, first bool) error {
+	var args [7]float64
+	lower := command | 0x20
+	for i := 0; i < svgPathParameters[lower]; i++ {
+		var err error
+		if lower == 'a' && (i == 3 || i == 4) {
+			var flag bool
+			flag, err = scanner.flag()
+			if flag {
+				args[i] = 1
+			}
+		} else {
+			args[i], err = scanner.number()
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	relative := command == lower
+	origin := svgPoint{}
+	if relative {
+		origin = path.current
+	}
+	at := func(x float64, y float64) svgPoint {
+		return svgPoint{x: origin.x + x, y: origin.y + y}
+	}
+	// the reflection of the last control point for the smooth curve commands
+	reflected := path.current
+	if (lower == 's' && strings.IndexByte("CcSs", path.lastCommand) >= 0) || (lower == 't' && strings.IndexByte("QqTt", path.lastCommand) >= 0) {
+		reflected = svgPoint{x: 2*path.current.x - path.lastControl.x, y: 2*path.current.y - path.lastControl.y}
+	}
+
+	switch lower {
+	case 'm':
+		if first {
+			path.moveTo(at(args[0], args[1]))
+		} else {
+			// further coordinate pairs of a move command are lines
+			path.lineTo(at(args[0], args[1]))
+		}
+	case 'l':
+		path.lineTo(at(args[0], args[1]))
+	case 'h':
+		p := path.current
+		p.x = origin.x + args[0]
+		path.lineTo(p)
+	case 'v':
+		p := path.current
+		p.y = origin.y + args[0]
+		path.lineTo(p)
+	case 'c':
+		path.lastControl = at(args[2], args[3])
+		path.cubicTo(at(args[0], args[1]), path.lastControl, at(args[4], args[5]))
+	case 's':
+		path.lastControl = at(args[0], args[1])
+		path.cubicTo(reflected, path.lastControl, at(args[2], args[3]))
+	case 'q':
+		path.lastControl = at(args[0], args[1])
+		path.quadTo(path.lastControl, at(args[2], args[3]))
+	case 't':
+		path.lastControl = reflected
+		path.quadTo(reflected, at(args[0], args[1]))
+	case 'a':
+		path.arcTo(args[0], args[1], args[2], args[3] == 1, args[4] == 1, at(args[5], args[6]))
+	}
+
+	path.lastCommand = command
+	return nil
+}
+
+// extent returns the image size in the unit of measure with k points per unit, like gofpdf.ImageInfoType.Extent.
+func (image *svgImage) extent(k float64) (w float64, h float64) {
+	// one CSS pixel is 0.75 pt
+	return image.width * .75 / k, image.height * .75 / k
+}
+
+// registerSvgImage parses and registers an SVG image, which is drawn by PlaceRegisteredImageOnPage().
+func (core *PDFGenerator) registerSvgImage(imageNameStr string, imageData []byte) {
+	svg, err := parseSVGImage(imageData)
+	if err != nil {
+		core.pdf.SetError(errorsWithStack.New(fmt.Sprintf("The image \"%s\" can't be registered: %s", imageNameStr, err.Error())))
+		return
+	}
+
+	core.registeredSvgImages[imageNameStr] = svg
+	core.registeredImageTypes[imageNameStr] = "svg"
+	if core.display != nil {
+		core.display.images[imageNameStr] = displayImageData{data: imageData, imageType: "svg", svg: svg}
+	}
+}
+
+// drawSvgImage draws the shapes of the svg image as vector graphic into the rectangle x, y, w, h.
+func (core *PDFGenerator) drawSvgImage(svg *svgImage, x float64, y float64, w float64, h float64) {
+	scaleX, scaleY := w/svg.width, h/svg.height
+	point := func(p svgPoint) (float64, float64) {
+		return x + p.x*scaleX, y + p.y*scaleY
+	}
+
+	lineWidth := core.pdf.GetLineWidth()
+	drawR, drawG, drawB := core.pdf.GetDrawColor()
+	fillR, fillG, fillB := core.pdf.GetFillColor()
+
+	for _, shape := range svg.shapes {
+		// the fill and the stroke are drawn separately, because they may have different opacities
+		if shape.fill != nil {
+			core.pdf.SetFillColor(int(shape.fill.R), int(shape.fill.G), int(shape.fill.B))
+			styleStr := "F"
+			if shape.fillEvenOdd {
+				styleStr = "F*"
+			}
+			core.drawSvgPath(shape.subpaths, point, styleStr, shape.fillOpacity)
+		}
+
+		if shape.stroke != nil {
+			core.pdf.SetDrawColor(int(shape.stroke.R), int(shape.stroke.G), int(shape.stroke.B))
+			core.pdf.SetLineWidth(shape.strokeWidth * math.Sqrt(scaleX*scaleY))
+			core.pdf.SetLineCapStyle(shape.lineCap)
+			core.pdf.SetLineJoinStyle(shape.lineJoin)
+			core.drawSvgPath(shape.subpaths, point, "D", shape.strokeOpacity)
+			core.pdf.SetLineCapStyle("butt")
+			core.pdf.SetLineJoinStyle("miter")
+		}
+	}
+
+	core.pdf.SetLineWidth(lineWidth)
+	core.pdf.SetDrawColor(drawR, drawG, drawB)
+	core.pdf.SetFillColor(fillR, fillG, fillB)
+}
+
+// drawSvgPath draws the subpaths with the gofpdf path style styleStr, e.g. "F" or "D".
+func (core *PDFGenerator) drawSvgPath(subpaths []svgSubpath, point func(p svgPoint) (float64, float64), styleStr string, opacity float64) {
+	if opacity <= 0 {
+		return
+	}
+	// inside a watermark, the opacity of the watermark is kept
+	baseOpacity := 1.
+	if core.transform != nil {
+		baseOpacity = core.transform.opacity
+	}
+	if opacity < 1 {
+		core.pdf.SetAlpha(baseOpacity*opacity, "Normal")
+		defer core.pdf.SetAlpha(baseOpacity, "Normal")
+	}
+
+	for _, subpath := range subpaths {
+		core.pdf.MoveTo(point(subpath.start))
+		for _, segment := range subpath.segments {
+			endX, endY := point(segment.end)
+			if segment.line {
+				core.pdf.LineTo(endX, endY)
+				continue
+			}
+			c1X, c1Y := point(segment.c1)
+			c2X, c2Y := point(segment.c2)
+			core.pdf.CurveBezierCubicTo(c1X, c1Y, c2X, c2Y, endX, endY)
+		}
+		if subpath.closed {
+			core.pdf.ClosePath()
+		}
+	}
+	core.pdf.DrawPath(styleStr)
+}
+
+// flatten returns the subpaths as polygons in the rectangle x, y, w, h, e.g. to rasterize the image.
+func (image *svgImage) flatten(subpaths []svgSubpath, x float64, y float64, w float64, h float64) (polygons [][]svgPoint) {
+	scaleX, scaleY := w/image.width, h/image.height
+	point := func(p svgPoint) svgPoint {
+		return svgPoint{x: x + p.x*scaleX, y: y + p.y*scaleY}
+	}
+
+	const curveSteps = 12
+	for _, subpath := range subpaths {
+		polygon := []svgPoint{point(subpath.start)}
+		p0 := subpath.start
+		for _, segment := range subpath.segments {
+			if segment.line {
+				polygon = append(polygon, point(segment.end))
+				p0 = segment.end
+				continue
+			}
+			for step := 1; step <= curveSteps; step++ {
+				t := float64(step) / curveSteps
+				u := 1 - t
+				polygon = append(polygon, point(svgPoint{
+					x: u*u*u*p0.x + 3*u*u*t*segment.c1.x + 3*u*t*t*segment.c2.x + t*t*t*segment.end.x,
+					y: u*u*u*p0.y + 3*u*u*t*segment.c1.y + 3*u*t*t*segment.c2.y + t*t*t*segment.end.y,
+				}))
+			}
+			p0 = segment.end
+		}
+		polygons = append(polygons, polygon)
+	}
+	return polygons
+}
@@ -0,0 +1,290 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+// testSvg is a logo with the supported SVG elements.
+const testSvg = `<?xml version="1.0" encoding="UTF-8"?>
+<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" width="60mm" height="20mm" viewBox="0 0 300 100">
+  <defs>
+    <style>.brand { fill: #1a4d8f } .line, .other { fill: none; stroke: #e94e1b; stroke-width: 6 }</style>
+    <linearGradient id="gradient"><stop offset="0" stop-color="#2a9d8f"/></linearGradient>
+    <circle id="dot" r="8" fill="red"/>
+  </defs>
+  <rect x="2" y="2" width="96" height="96" rx="16" class="brand"/>
+  <path class="line" d="M20 70 L50 30 L80 70"/>
+  <circle cx="200" cy="50" r="40" fill="url(#gradient)" opacity="0.5"/>
+  <g transform="translate(120 50) rotate(-20)"><ellipse rx="20" ry="10" style="fill: rgb(233, 196, 106)"/></g>
+  <use xlink:href="#dot" x="280" y="20"/>
+  <path d="M250 90a20 20 0 1 1 40 0z" fill-rule="evenodd"/>
+  <text x="10" y="10">texts must be converted to paths</text>
+</svg>`
+
+func Test_parseSVGImage(t *testing.T) {
+	image, err := parseSVGImage([]byte(testSvg))
+	if err != nil {
+		t.Fatalf("parseSVGImage() error = %v", err)
+	}
+
+	if width, height := image.extent(72 / 25.4); math.Abs(width-60) > 1e-9 || math.Abs(height-20) > 1e-9 {
+		t.Errorf("extent() = %v, %v, want 60, 20", width, height)
+	}
+
+	// the view box is scaled to the image size of 60 mm = 226.77 px
+	const scale = 60 * 96 / 25.4 / 300
+	wantShapes := []struct {
+		fill        *Color
+		stroke      *Color
+		fillOpacity float64
+		end         svgPoint
+	}{
+		{fill: &Color{R: 26, G: 77, B: 143}, fillOpacity: 1, end: svgPoint{x: 82, y: 2}},
+		{stroke: &Color{R: 233, G: 78, B: 27}, fillOpacity: 1, end: svgPoint{x: 50, y: 30}},
+		{fill: &Color{R: 42, G: 157, B: 143}, fillOpacity: .5, end: svgPoint{x: 200, y: 90}},
+		{fill: &Color{R: 233, G: 196, B: 106}, fillOpacity: 1, end: svgPoint{x: 120 + 10*math.Sin(20*math.Pi/180), y: 50 + 10*math.Cos(20*math.Pi/180)}},
+		{fill: &Color{R: 255}, fillOpacity: 1, end: svgPoint{x: 280, y: 28}},
+		{fill: &Color{}, fillOpacity: 1, end: svgPoint{x: 270, y: 70}},
+	}
+	if len(image.shapes) != len(wantShapes) {
+		t.Fatalf("parseSVGImage() has %d shapes, want %d", len(image.shapes), len(wantShapes))
+	}
+
+	for i, want := range wantShapes {
+		shape := image.shapes[i]
+		if (shape.fill == nil) != (want.fill == nil) || (shape.fill != nil && *shape.fill != *want.fill) {
+			t.Errorf("shape %d: fill = %v, want %v", i, shape.fill, want.fill)
+		}
+		if (shape.stroke == nil) != (want.stroke == nil) || (shape.stroke != nil && *shape.stroke != *want.stroke) {
+			t.Errorf("shape %d: stroke = %v, want %v", i, shape.stroke, want.stroke)
+		}
+		if shape.fillOpacity != want.fillOpacity {
+			t.Errorf("shape %d: fillOpacity = %v, want %v", i, shape.fillOpacity, want.fillOpacity)
+		}
+
+		gotEnd := shape.subpaths[0].segments[0].end
+		if math.Abs(gotEnd.x-want.end.x*scale) > 1e-9 || math.Abs(gotEnd.y-want.end.y*scale) > 1e-9 {
+			t.Errorf("shape %d: first segment end = %v, want %v", i, gotEnd, want.end)
+		}
+	}
+
+	if !image.shapes[5].fillEvenOdd {
+		t.Errorf("the fill rule evenodd is not parsed")
+	}
+	if width := image.shapes[1].strokeWidth; math.Abs(width-6*scale) > 1e-9 {
+		t.Errorf("strokeWidth = %v, want %v", width, 6*scale)
+	}
+}
+
+// fanOutSvg returns an image, which references each group 10 times by the next group, so it has 10^levels circles.
+func fanOutSvg(levels int) string {
+	var svg strings.Builder
+	svg.WriteString(`<svg xmlns="http://www.w3.org/2000/svg" width="10" height="10"><defs><circle id="g0" r="1"/>`)
+	for level := 1; level <= levels; level++ {
+		svg.WriteString(fmt.Sprintf(`<g id="g%d">`, level))
+		for i := 0; i < 10; i++ {
+			svg.WriteString(fmt.Sprintf(`<use href="#g%d"/>`, level-1))
+		}
+		svg.WriteString(`</g>`)
+	}
+	svg.WriteString(fmt.Sprintf(`</defs><use href="#g%d"/></svg>`, levels))
+	return svg.String()
+}
+
+func Test_parseSVGImage_limits(t *testing.T) {
+	tests := []struct {
+		name       string
+		svg        string
+		wantShapes int
+		wantErr    bool
+	}{
+		{
+			name:       "fan-out within the limits",
+			svg:        fanOutSvg(3),
+			wantShapes: 1000,
+			wantErr:    false,
+		},
+		{
+			name:    "fan-out of the maximal depth",
+			svg:     fanOutSvg(svgMaxUseDepth),
+			wantErr: true,
+		},
+		{
+			name:    "too many path segments",
+			svg:     `<svg width="10" height="10"><path id="p" d="M0 0` + strings.Repeat(" L1 1", 60000) + `"/>` + strings.Repeat(`<use href="#p"/>`, 10) + `</svg>`,
+			wantErr: true,
+		},
+		{
+			name:    "too many invisible path segments",
+			svg:     `<svg width="10" height="10"><path id="p" fill="none" d="M0 0` + strings.Repeat(" L1 1", 60000) + `"/>` + strings.Repeat(`<use href="#p"/>`, 10) + `</svg>`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			image, err := parseSVGImage([]byte(tt.svg))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSVGImage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && len(image.shapes) != tt.wantShapes {
+				t.Errorf("parseSVGImage() has %d shapes, want %d", len(image.shapes), tt.wantShapes)
+			}
+		})
+	}
+}
+
+func Test_svgPathBuilder_parse(t *testing.T) {
+	tests := []struct {
+		name      string
+		d         string
+		wantEnds  []svgPoint
+		wantClose bool
+		wantErr   bool
+	}{
+		{
+			name:     "relative lines",
+			d:        "m10 10 h20 v-5 l-5-5",
+			wantEnds: []svgPoint{{30, 10}, {30, 5}, {25, 0}},
+		},
+		{
+			name:      "implicit line after move",
+			d:         "M0,0 10,0 10,10z",
+			wantEnds:  []svgPoint{{10, 0}, {10, 10}},
+			wantClose: true,
+		},
+		{
+			name:     "compact numbers",
+			d:        "M.5.5L1.5-2e1",
+			wantEnds: []svgPoint{{1.5, -20}},
+		},
+		{
+			name:     "smooth curves",
+			d:        "M0 0 C0 10 10 10 10 0 S20 -10 20 0 Q25 5 30 0 T40 0",
+			wantEnds: []svgPoint{{10, 0}, {20, 0}, {30, 0}, {40, 0}},
+		},
+		{
+			name:     "arc with compact flags",
+			d:        "M0 0 a10 10 0 0110 10",
+			wantEnds: []svgPoint{{10, 10}},
+		},
+		{
+			name:    "no move command",
+			d:       "L10 10",
+			wantErr: true,
+		},
+		{
+			name:    "unknown command",
+			d:       "M0 0 X10",
+			wantErr: true,
+		},
+		{
+			name:    "missing number",
+			d:       "M0 0 L10",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := &svgPathBuilder{}
+			err := path.parse(tt.d)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var gotEnds []svgPoint
+			for _, segment := range path.subpaths[0].segments {
+				gotEnds = append(gotEnds, segment.end)
+			}
+			// an arc is split into several curves, only the last end point is compared
+			if len(gotEnds) > len(tt.wantEnds) {
+				gotEnds = append(gotEnds[:len(tt.wantEnds)-1], gotEnds[len(gotEnds)-1])
+			}
+			for i := range tt.wantEnds {
+				if i >= len(gotEnds) || math.Abs(gotEnds[i].x-tt.wantEnds[i].x) > 1e-9 || math.Abs(gotEnds[i].y-tt.wantEnds[i].y) > 1e-9 {
+					t.Fatalf("parse() segment ends = %v, want %v", gotEnds, tt.wantEnds)
+				}
+			}
+			if path.subpaths[0].closed != tt.wantClose {
+				t.Errorf("parse() closed = %v, want %v", path.subpaths[0].closed, tt.wantClose)
+			}
+		})
+	}
+}
+
+func TestPDFGenerator_RegisterImage_svg(t *testing.T) {
+	tests := []struct {
+		name    string
+		svg     string
+		wantErr bool
+	}{
+		{
+			name:    "logo",
+			svg:     testSvg,
+			wantErr: false,
+		},
+		{
+			name:    "size of the view box",
+			svg:     `<svg viewBox="0 0 40 20"><path d="M0 0H40V20Z" fill="#1a4d8f"/></svg>`,
+			wantErr: false,
+		},
+		{
+			name:    "no size",
+			svg:     `<svg><path d="M0 0H40V20Z"/></svg>`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid path",
+			svg:     `<svg width="40" height="20"><path d="M0 0H"/></svg>`,
+			wantErr: true,
+		},
+		{
+			name:    "no svg root",
+			svg:     `<html><svg width="40" height="20"/></html>`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, err := NewPDFGenerator(_defaultMetaData, false, &_logger, func() {}, func(isLastPage bool) {})
+			if err != nil {
+				t.Fatalf("init core error\n%s", err.Error())
+			}
+
+			core.RegisterImage("logo", []byte(tt.svg))
+			if gotErr := core.pdf.Err(); gotErr != tt.wantErr {
+				t.Fatalf("RegisterImage() set a error = %v, want %v", core.pdf.Error(), tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			core.NewPage()
+			core.SetCursor(100, 20)
+			core.PlaceRegisteredImageOnPage("logo", "R", .5)
+
+			var buffer bytes.Buffer
+			if err = core.Output(&buffer); err != nil {
+				t.Errorf("Output() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestRecordingGenerator_RegisterImage_svg(t *testing.T) {
+	rec, err := NewRecordingGenerator(_defaultMetaData, false, &_logger, func() {}, func(isLastPage bool) {})
+	if err != nil {
+		t.Fatalf("init recorder error\n%s", err.Error())
+	}
+
+	rec.RegisterImage("logo", []byte(testSvg))
+	if gotWidth, gotHeight := rec.GetRegisteredImageExtent("logo"); math.Abs(gotWidth-60) > 1e-9 || math.Abs(gotHeight-20) > 1e-9 {
+		t.Errorf("GetRegisteredImageExtent() = %v, %v, want 60, 20", gotWidth, gotHeight)
+	}
+}
@@ -0,0 +1,431 @@
+package generator
+
+import (
+	"encoding/binary"
+	"fmt"
+	errorsWithStack "github.com/go-errors/errors"
+)
+
+// limits of the outline of one glyph, far above the glyphs of real fonts.
+// The components of a composite glyph may be composite glyphs again, so a crafted font can multiply
+// the components with each nesting level. The budget of a glyph stops the parsing of such a font.
+const (
+	maxGlyphDepth      = 8
+	maxGlyphComponents = 1024
+	maxGlyphPoints     = 1 << 16
+)
+
+// trueTypeFont is a minimal TrueType parser, which reads the glyph outlines and advance widths of a font.
+// It is used to rasterize text for the PNG preview, all other font handling is done by gofpdf.
+type trueTypeFont struct {
+	data             []byte
+	unitsPerEm       float64
+	numGlyphs        int
+	numberOfHMetrics int
+	longLoca         bool
+	cmap             func(r rune) int
+	tables           map[string][]byte
+}
+
+// glyphBudget counts the components and the points of a glyph parsed so far, see maxGlyphComponents and maxGlyphPoints.
+type glyphBudget struct {
+	components int
+	points     int
+}
+
+// fontPoint is a point of a glyph outline, measured in font units with the y-axis pointing up.
+type fontPoint struct {
+	x       float64
+	y       float64
+	onCurve bool
+}
+
+func parseTrueTypeFont(data []byte) (font *trueTypeFont, err error) {
+	if len(data) < 12 {
+		return nil, errorsWithStack.New(fmt.Sprintf("The font data is too short."))
+	}
+
+	font = &trueTypeFont{data: data, tables: map[string][]byte{}}
+	numTables := int(binary.BigEndian.Uint16(data[4:]))
+	for i := 0; i < numTables; i++ {
+		record := 12 + 16*i
+		if record+16 > len(data) {
+			return nil, errorsWithStack.New(fmt.Sprintf("The table directory is truncated."))
+		}
+		tag := string(data[record : record+4])
+		offset := int(binary.BigEndian.Uint32(data[record+8:]))
+		length := int(binary.BigEndian.Uint32(data[record+12:]))
+		if offset+length > len(data) {
+			return nil, errorsWithStack.New(fmt.Sprintf("The table %s is truncated.", tag))
+		}
+		font.tables[tag] = data[offset : offset+length]
+	}
+
+	for _, tag := range []string{"head", "maxp", "hhea", "hmtx", "cmap", "loca", "glyf"} {
+		if font.tables[tag] == nil {
+			return nil, errorsWithStack.New(fmt.Sprintf("The table %s is missing.", tag))
+		}
+	}
+
+	head := font.tables["head"]
+	if len(head) < 54 {
+		return nil, errorsWithStack.New(fmt.Sprintf("The table head is too short."))
+	}
+	font.unitsPerEm = float64(binary.BigEndian.Uint16(head[18:]))
+	if font.unitsPerEm == 0 {
+		return nil, errorsWithStack.New(fmt.Sprintf("The table head has no units per em."))
+	}
+	font.longLoca = binary.BigEndian.Uint16(head[50:]) != 0
+
+	if len(font.tables["maxp"]) < 6 {
+		return nil, errorsWithStack.New(fmt.Sprintf("The table maxp is too short."))
+	}
+	font.numGlyphs = int(binary.BigEndian.Uint16(font.tables["maxp"][4:]))
+
+	if len(font.tables["hhea"]) < 36 {
+		return nil, errorsWithStack.New(fmt.Sprintf("The table hhea is too short."))
+	}
+	font.numberOfHMetrics = int(binary.BigEndian.Uint16(font.tables["hhea"][34:]))
+	if font.numberOfHMetrics == 0 || 4*font.numberOfHMetrics > len(font.tables["hmtx"]) {
+		return nil, errorsWithStack.New(fmt.Sprintf("The table hmtx is too short for %d metrics.", font.numberOfHMetrics))
+	}
+
+	font.cmap, err = parseCmap(font.tables["cmap"])
+	if err != nil {
+		return nil, err
+	}
+
+	return font, nil
+}
+
+// parseCmap returns a lookup function from the best unicode subtable (format 12 or format 4).
+// The sizes of the subtables are checked here, so the lookup function never reads outside the table.
+func parseCmap(cmap []byte) (func(r rune) int, error) {
+	if len(cmap) < 4 {
+		return nil, errorsWithStack.New(fmt.Sprintf("The table cmap is too short."))
+	}
+	numSubtables := int(binary.BigEndian.Uint16(cmap[2:]))
+	if 4+8*numSubtables > len(cmap) {
+		return nil, errorsWithStack.New(fmt.Sprintf("The table cmap is truncated."))
+	}
+
+	var format4, format12 []byte
+	for i := 0; i < numSubtables; i++ {
+		record := 4 + 8*i
+		platformID := binary.BigEndian.Uint16(cmap[record:])
+		encodingID := binary.BigEndian.Uint16(cmap[record+2:])
+		offset := int(binary.BigEndian.Uint32(cmap[record+4:]))
+		if offset+2 > len(cmap) || (platformID != 0 && platformID != 3) || (platformID == 3 && encodingID != 1 && encodingID != 10) {
+			continue
+		}
+		switch binary.BigEndian.Uint16(cmap[offset:]) {
+		case 4:
+			format4 = cmap[offset:]
+		case 12:
+			format12 = cmap[offset:]
+		}
+	}
+
+	switch {
+	case format12 != nil:
+		if len(format12) < 16 {
+			return nil, errorsWithStack.New(fmt.Sprintf("The cmap subtable format 12 is too short."))
+		}
+		nGroups := int(binary.BigEndian.Uint32(format12[12:]))
+		if nGroups > (len(format12)-16)/12 {
+			return nil, errorsWithStack.New(fmt.Sprintf("The cmap subtable format 12 is too short for %d groups.", nGroups))
+		}
+		return func(r rune) int {
+			for i := 0; i < nGroups; i++ {
+				group := format12[16+12*i:]
+				start := rune(binary.BigEndian.Uint32(group))
+				end := rune(binary.BigEndian.Uint32(group[4:]))
+				if r >= start && r <= end {
+					return int(binary.BigEndian.Uint32(group[8:])) + int(r-start)
+				}
+			}
+			return 0
+		}, nil
+	case format4 != nil:
+		if len(format4) < 14 {
+			return nil, errorsWithStack.New(fmt.Sprintf("The cmap subtable format 4 is too short."))
+		}
+		segCount := int(binary.BigEndian.Uint16(format4[6:])) / 2
+		endCodes := 14
+		startCodes := endCodes + 2*segCount + 2
+		idDeltas := startCodes + 2*segCount
+		idRangeOffsets := idDeltas + 2*segCount
+		if idRangeOffsets+2*segCount > len(format4) {
+			return nil, errorsWithStack.New(fmt.Sprintf("The cmap subtable format 4 is too short for %d segments.", segCount))
+		}
+		return func(r rune) int {
+			if r > 0xFFFF {
+				return 0
+			}
+			for i := 0; i < segCount; i++ {
+				end := rune(binary.BigEndian.Uint16(format4[endCodes+2*i:]))
+				if r > end {
+					continue
+				}
+				start := rune(binary.BigEndian.Uint16(format4[startCodes+2*i:]))
+				if r < start {
+					return 0
+				}
+				delta := int(binary.BigEndian.Uint16(format4[idDeltas+2*i:]))
+				rangeOffset := int(binary.BigEndian.Uint16(format4[idRangeOffsets+2*i:]))
+				if rangeOffset == 0 {
+					return (int(r) + delta) & 0xFFFF
+				}
+				index := idRangeOffsets + 2*i + rangeOffset + 2*int(r-start)
+				if index+2 > len(format4) {
+					return 0
+				}
+				glyph := int(binary.BigEndian.Uint16(format4[index:]))
+				if glyph == 0 {
+					return 0
+				}
+				return (glyph + delta) & 0xFFFF
+			}
+			return 0
+		}, nil
+	}
+
+	return nil, errorsWithStack.New(fmt.Sprintf("The font has no unicode cmap."))
+}
+
+// glyphIndex returns the glyph of r or 0 (the missing glyph) if r is not part of the font.
+func (font *trueTypeFont) glyphIndex(r rune) int {
+	glyph := font.cmap(r)
+	if glyph >= font.numGlyphs {
+		return 0
+	}
+	return glyph
+}
+
+// advanceWidth returns the advance width of a glyph in font units.
+func (font *trueTypeFont) advanceWidth(glyph int) float64 {
+	hmtx := font.tables["hmtx"]
+	if glyph >= font.numberOfHMetrics {
+		glyph = font.numberOfHMetrics - 1
+	}
+	if 4*glyph+2 > len(hmtx) {
+		return 0
+	}
+	return float64(binary.BigEndian.Uint16(hmtx[4*glyph:]))
+}
+
+// glyphData returns the raw glyf entry of a glyph, or nil for empty glyphs like the space.
+func (font *trueTypeFont) glyphData(glyph int) []byte {
+	loca := font.tables["loca"]
+	var start, end int
+	if font.longLoca {
+		if 4*glyph+8 > len(loca) {
+			return nil
+		}
+		start = int(binary.BigEndian.Uint32(loca[4*glyph:]))
+		end = int(binary.BigEndian.Uint32(loca[4*glyph+4:]))
+	} else {
+		if 2*glyph+4 > len(loca) {
+			return nil
+		}
+		start = 2 * int(binary.BigEndian.Uint16(loca[2*glyph:]))
+		end = 2 * int(binary.BigEndian.Uint16(loca[2*glyph+2:]))
+	}
+
+	glyf := font.tables["glyf"]
+	if start >= end || end > len(glyf) {
+		return nil
+	}
+	return glyf[start:end]
+}
+
+// glyphContours returns the outline contours of a glyph.
+// An error is returned, if the glyph exceeds maxGlyphComponents or maxGlyphPoints.
+func (font *trueTypeFont) glyphContours(glyph int) ([][]fontPoint, error) {
+	return font.glyphContoursDepth(glyph, 0, &glyphBudget{})
+}
+
+func (font *trueTypeFont) glyphContoursDepth(glyph int, depth int, budget *glyphBudget) (contours [][]fontPoint, err error) {
+	data := font.glyphData(glyph)
+	if len(data) < 10 || depth > maxGlyphDepth {
+		return nil, nil
+	}
+
+	numberOfContours := int(int16(binary.BigEndian.Uint16(data)))
+	if numberOfContours < 0 {
+		return font.compositeContours(data[10:], depth, budget)
+	}
+
+	// --> simple glyph
+	p := 10
+	if p+2*numberOfContours+2 > len(data) {
+		return nil, nil
+	}
+	endPoints := make([]int, numberOfContours)
+	for i := range endPoints {
+		endPoints[i] = int(binary.BigEndian.Uint16(data[p:]))
+		p += 2
+	}
+	if numberOfContours == 0 {
+		return nil, nil
+	}
+	numPoints := endPoints[numberOfContours-1] + 1
+	budget.points += numPoints
+	if budget.points > maxGlyphPoints {
+		return nil, errorsWithStack.New(fmt.Sprintf("The glyph has more than %d points.", maxGlyphPoints))
+	}
+	p += 2 + int(binary.BigEndian.Uint16(data[p:]))
+
+	flags := make([]byte, 0, numPoints)
+	for len(flags) < numPoints && p < len(data) {
+		flag := data[p]
+		p++
+		flags = append(flags, flag)
+		if flag&0x08 != 0 && p < len(data) {
+			repeat := int(data[p])
+			p++
+			for i := 0; i < repeat; i++ {
+				flags = append(flags, flag)
+			}
+		}
+	}
+	if len(flags) < numPoints {
+		return nil, nil
+	}
+
+	points := make([]fontPoint, numPoints)
+	readCoordinates := func(shortBit byte, sameBit byte, set func(i int, v float64)) bool {
+		value := 0
+		for i := 0; i < numPoints; i++ {
+			flag := flags[i]
+			switch {
+			case flag&shortBit != 0:
+				if p >= len(data) {
+					return false
+				}
+				if flag&sameBit != 0 {
+					value += int(data[p])
+				} else {
+					value -= int(data[p])
+				}
+				p++
+			case flag&sameBit == 0:
+				if p+2 > len(data) {
+					return false
+				}
+				value += int(int16(binary.BigEndian.Uint16(data[p:])))
+				p += 2
+			}
+			set(i, float64(value))
+		}
+		return true
+	}
+	if !readCoordinates(0x02, 0x10, func(i int, v float64) { points[i].x = v }) ||
+		!readCoordinates(0x04, 0x20, func(i int, v float64) { points[i].y = v }) {
+		return nil, nil
+	}
+
+	start := 0
+	for i, end := range endPoints {
+		if end < start || end >= numPoints {
+			return contours, nil
+		}
+		contour := make([]fontPoint, 0, end-start+1)
+		for j := start; j <= end; j++ {
+			points[j].onCurve = flags[j]&0x01 != 0
+			contour = append(contour, points[j])
+		}
+		contours = append(contours, contour)
+		start = endPoints[i] + 1
+	}
+	// <--
+
+	return contours, nil
+}
+
+// compositeContours combines the transformed contours of all components of a composite glyph.
+// The components of all nesting levels are counted in budget.
+func (font *trueTypeFont) compositeContours(data []byte, depth int, budget *glyphBudget) (contours [][]fontPoint, err error) {
+	const (
+		argsAreWords    = 0x0001
+		argsAreXY       = 0x0002
+		haveScale       = 0x0008
+		moreComponents  = 0x0020
+		haveXYScale     = 0x0040
+		haveTwoByTwo    = 0x0080
+		f2dot14Quotient = 1 << 14
+	)
+
+	p := 0
+	for {
+		if p+4 > len(data) {
+			return contours, nil
+		}
+		flags := binary.BigEndian.Uint16(data[p:])
+		component := int(binary.BigEndian.Uint16(data[p+2:]))
+		p += 4
+
+		budget.components++
+		if budget.components > maxGlyphComponents {
+			return nil, errorsWithStack.New(fmt.Sprintf("The glyph has more than %d components.", maxGlyphComponents))
+		}
+
+		var dx, dy float64
+		if flags&argsAreWords != 0 {
+			if p+4 > len(data) {
+				return contours, nil
+			}
+			dx, dy = float64(int16(binary.BigEndian.Uint16(data[p:]))), float64(int16(binary.BigEndian.Uint16(data[p+2:])))
+			p += 4
+		} else {
+			if p+2 > len(data) {
+				return contours, nil
+			}
+			dx, dy = float64(int8(data[p])), float64(int8(data[p+1]))
+			p += 2
+		}
+		if flags&argsAreXY == 0 {
+			// point matching is not supported, the component is placed at the origin
+			dx, dy = 0, 0
+		}
+
+		readF2Dot14 := func() float64 {
+			if p+2 > len(data) {
+				return 0
+			}
+			v := float64(int16(binary.BigEndian.Uint16(data[p:]))) / f2dot14Quotient
+			p += 2
+			return v
+		}
+		a, b, c, d := 1., 0., 0., 1.
+		switch {
+		case flags&haveScale != 0:
+			a = readF2Dot14()
+			d = a
+		case flags&haveXYScale != 0:
+			a, d = readF2Dot14(), readF2Dot14()
+		case flags&haveTwoByTwo != 0:
+			a, b, c, d = readF2Dot14(), readF2Dot14(), readF2Dot14(), readF2Dot14()
+		}
+
+		componentContours, err := font.glyphContoursDepth(component, depth+1, budget)
+		if err != nil {
+			return nil, err
+		}
+		for _, contour := range componentContours {
+			transformed := make([]fontPoint, len(contour))
+			for i, point := range contour {
+				transformed[i] = fontPoint{
+					x:       a*point.x + c*point.y + dx,
+					y:       b*point.x + d*point.y + dy,
+					onCurve: point.onCurve,
+				}
+			}
+			contours = append(contours, transformed)
+		}
+
+		if flags&moreComponents == 0 {
+			return contours, nil
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package generator
+
+import (
+	"encoding/binary"
+	"os"
+	"sort"
+	"testing"
+)
+
+// testTrueTypeFont returns the font file of the tables, the table directory is sorted by tag.
+func testTrueTypeFont(tables map[string][]byte) []byte {
+	tags := make([]string, 0, len(tables))
+	for tag := range tables {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	data := make([]byte, 12+16*len(tags))
+	binary.BigEndian.PutUint16(data[4:], uint16(len(tags)))
+	for i, tag := range tags {
+		record := data[12+16*i:]
+		copy(record, tag)
+		binary.BigEndian.PutUint32(record[8:], uint32(len(data)))
+		binary.BigEndian.PutUint32(record[12:], uint32(len(tables[tag])))
+		data = append(data, tables[tag]...)
+	}
+	return data
+}
+
+// testFontTables returns the tables of a font with 2 glyphs, which maps "A" to the glyph 1.
+// The cmap has the unicode subtable cmapSubtable.
+func testFontTables(cmapSubtable []byte) map[string][]byte {
+	head := make([]byte, 54)
+	binary.BigEndian.PutUint16(head[18:], 1000)
+	maxp := make([]byte, 6)
+	binary.BigEndian.PutUint16(maxp[4:], 2)
+	hhea := make([]byte, 36)
+	binary.BigEndian.PutUint16(hhea[34:], 2)
+
+	cmap := make([]byte, 12)
+	binary.BigEndian.PutUint16(cmap[2:], 1)
+	binary.BigEndian.PutUint16(cmap[4:], 3)
+	binary.BigEndian.PutUint16(cmap[6:], 1)
+	binary.BigEndian.PutUint32(cmap[8:], 12)
+
+	return map[string][]byte{
+		"head": head,
+		"maxp": maxp,
+		"hhea": hhea,
+		"hmtx": make([]byte, 8),
+		"cmap": append(cmap, cmapSubtable...),
+		"loca": make([]byte, 6),
+		"glyf": make([]byte, 0),
+	}
+}
+
+// testCmapFormat12 returns a cmap subtable of the format 12, which declares nGroups groups and contains the group "A".
+func testCmapFormat12(nGroups uint32) []byte {
+	subtable := make([]byte, 28)
+	binary.BigEndian.PutUint16(subtable, 12)
+	binary.BigEndian.PutUint32(subtable[12:], nGroups)
+	binary.BigEndian.PutUint32(subtable[16:], 'A')
+	binary.BigEndian.PutUint32(subtable[20:], 'A')
+	binary.BigEndian.PutUint32(subtable[24:], 1)
+	return subtable
+}
+
+// testCmapFormat4 returns a cmap subtable of the format 4, which declares segCount segments:
+// "A" and the final segment 0xFFFF.
+func testCmapFormat4(segCount uint16) []byte {
+	subtable := make([]byte, 14+8*2+2)
+	binary.BigEndian.PutUint16(subtable, 4)
+	binary.BigEndian.PutUint16(subtable[6:], 2*segCount)
+	for i, value := range []uint16{'A', 0xFFFF, 0, 'A', 0xFFFF, 0x10000 - 'A' + 1, 1, 0, 0} {
+		binary.BigEndian.PutUint16(subtable[14+2*i:], value)
+	}
+	return subtable
+}
+
+func Test_parseTrueTypeFont(t *testing.T) {
+	// withTable replaces a table of a valid font, nil removes the table
+	withTable := func(tag string, data []byte) []byte {
+		tables := testFontTables(testCmapFormat12(1))
+		tables[tag] = data
+		if data == nil {
+			delete(tables, tag)
+		}
+		return testTrueTypeFont(tables)
+	}
+
+	openSans, err := os.ReadFile("../fonts/OpenSans-Regular.ttf")
+	if err != nil {
+		t.Fatalf("read test font error\n%s", err.Error())
+	}
+
+	tests := []struct {
+		name      string
+		data      []byte
+		wantErr   bool
+		wantGlyph bool
+	}{
+		{name: "OpenSans", data: openSans, wantErr: false, wantGlyph: true},
+		{name: "format 12", data: testTrueTypeFont(testFontTables(testCmapFormat12(1))), wantErr: false, wantGlyph: true},
+		{name: "format 4", data: testTrueTypeFont(testFontTables(testCmapFormat4(2))), wantErr: false, wantGlyph: true},
+		{name: "too short", data: []byte("true"), wantErr: true},
+		{name: "truncated table directory", data: testTrueTypeFont(testFontTables(testCmapFormat12(1)))[:40], wantErr: true},
+		{name: "missing table", data: withTable("loca", nil), wantErr: true},
+		{name: "short head", data: withTable("head", make([]byte, 20)), wantErr: true},
+		{name: "no units per em", data: withTable("head", make([]byte, 54)), wantErr: true},
+		{name: "short maxp", data: withTable("maxp", make([]byte, 2)), wantErr: true},
+		{name: "short hhea", data: withTable("hhea", make([]byte, 30)), wantErr: true},
+		{name: "short hmtx", data: withTable("hmtx", make([]byte, 4)), wantErr: true},
+		{name: "short cmap", data: withTable("cmap", make([]byte, 1)), wantErr: true},
+		{name: "truncated cmap records", data: withTable("cmap", []byte{0, 0, 0, 2, 0, 3, 0, 1}), wantErr: true},
+		{name: "subtable offset outside cmap", data: withTable("cmap", []byte{0, 0, 0, 1, 0, 3, 0, 1, 0, 0, 1, 0}), wantErr: true},
+		{name: "short format 12", data: testTrueTypeFont(testFontTables(testCmapFormat12(1)[:14])), wantErr: true},
+		{name: "oversized nGroups", data: testTrueTypeFont(testFontTables(testCmapFormat12(0x10000000))), wantErr: true},
+		{name: "short format 4", data: testTrueTypeFont(testFontTables(testCmapFormat4(2)[:10])), wantErr: true},
+		{name: "oversized segCount", data: testTrueTypeFont(testFontTables(testCmapFormat4(1000))), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			font, err := parseTrueTypeFont(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseTrueTypeFont() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if gotGlyph := font.glyphIndex('A') != 0; gotGlyph != tt.wantGlyph {
+				t.Errorf("glyphIndex('A') != 0 is %v, want %v", gotGlyph, tt.wantGlyph)
+			}
+			if font.glyphIndex(0x1F600) != 0 {
+				t.Errorf("glyphIndex() of a missing character is not 0")
+			}
+			_ = font.advanceWidth(font.glyphIndex('A'))
+			_, _ = font.glyphContours(font.glyphIndex('A'))
+		})
+	}
+}
+
+// testGlyphFont returns a font with the glyf entries glyphs after the empty glyph 0.
+func testGlyphFont(glyphs ...[]byte) *trueTypeFont {
+	tables := testFontTables(testCmapFormat12(1))
+	binary.BigEndian.PutUint16(tables["head"][50:], 1)
+
+	loca := make([]byte, 4*(len(glyphs)+2))
+	var glyf []byte
+	for i, glyph := range glyphs {
+		glyf = append(glyf, glyph...)
+		binary.BigEndian.PutUint32(loca[4*(i+2):], uint32(len(glyf)))
+	}
+	tables["loca"] = loca
+	tables["glyf"] = glyf
+
+	font, _ := parseTrueTypeFont(testTrueTypeFont(tables))
+	return font
+}
+
+// testCompositeGlyph returns a composite glyph of the components, which are placed at the origin.
+func testCompositeGlyph(components ...uint16) []byte {
+	glyph := make([]byte, 10)
+	binary.BigEndian.PutUint16(glyph, 0xFFFF)
+	for i, component := range components {
+		flags := uint16(0x0002)
+		if i < len(components)-1 {
+			flags |= 0x0020
+		}
+		glyph = binary.BigEndian.AppendUint16(glyph, flags)
+		glyph = binary.BigEndian.AppendUint16(glyph, component)
+		glyph = append(glyph, 0, 0)
+	}
+	return glyph
+}
+
+// testSimpleGlyph returns a simple glyph with one contour of numPoints points on the curve at the origin.
+func testSimpleGlyph(numPoints int) []byte {
+	glyph := make([]byte, 10)
+	binary.BigEndian.PutUint16(glyph, 1)
+	glyph = binary.BigEndian.AppendUint16(glyph, uint16(numPoints-1))
+	glyph = append(glyph, 0, 0)
+	for i := 0; i < numPoints; i++ {
+		glyph = append(glyph, 0x01|0x10|0x20)
+	}
+	return glyph
+}
+
+func TestTrueTypeFont_glyphContours(t *testing.T) {
+	tests := []struct {
+		name         string
+		font         *trueTypeFont
+		wantContours int
+		wantErr      bool
+	}{
+		{name: "simple glyph", font: testGlyphFont(testSimpleGlyph(3)), wantContours: 1},
+		{name: "composite glyph", font: testGlyphFont(testCompositeGlyph(2, 2), testSimpleGlyph(3)), wantContours: 2},
+		// each level of the glyph refers to itself 4 times, i.e. 4^9 components
+		{name: "too many components", font: testGlyphFont(testCompositeGlyph(1, 1, 1, 1)), wantErr: true},
+		{name: "too many points", font: testGlyphFont(testCompositeGlyph(2, 2), testSimpleGlyph(maxGlyphPoints)), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contours, err := tt.font.glyphContours(1)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("glyphContours() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(contours) != tt.wantContours {
+				t.Errorf("glyphContours() has %d contours, want %d", len(contours), tt.wantContours)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package generator
+
+import (
+	"fmt"
+	errorsWithStack "github.com/go-errors/errors"
+	"math"
+	"strings"
+)
+
+// watermarkSubLineScale is the font size of the further lines of a watermark relative to the first line.
+const watermarkSubLineScale = .4
+
+// Watermark is a rotated, semi-transparent text or image, e.g. "ENTWURF" across a draft or a "BEZAHLT" stamp.
+//
+// Lines are printed bold and centered one below the other. The first line gets the largest font size fitting into
+// Width, the further lines (e.g. a date) are smaller. If ImageName is set, the registered image is drawn instead.
+//
+// X and Y are the center, Width is the width of the text or the image in the unit of measure.
+// Angle is the counterclockwise rotation in degree around the center.
+// Opacity is between 0 (invisible) and 1 (opaque).
+//
+// If Border is true, a rounded frame is drawn around the lines like a rubber stamp.
+// The lines and the frame are drawn in Color.
+type Watermark struct {
+	Lines     []string
+	ImageName string
+	X         float64
+	Y         float64
+	Width     float64
+	Angle     float64
+	Opacity   float64
+	Color     Color
+	Border    bool
+}
+
+// displayTransform is the rotation and the opacity of the elements drawn by DrawWatermark.
+// The elements are rotated counterclockwise by angle in degree around cx, cy.
+type displayTransform struct {
+	angle   float64
+	cx      float64
+	cy      float64
+	opacity float64
+}
+
+// validate checks the width, the opacity and that the watermark has lines or an image.
+func (mark Watermark) validate() error {
+	if mark.Width <= 0 {
+		return errorsWithStack.New(fmt.Sprintf("The watermark width (%f) must be greater than 0.", mark.Width))
+	}
+
+	if mark.Opacity <= 0 || mark.Opacity > 1 {
+		return errorsWithStack.New(fmt.Sprintf("The watermark opacity (%f) must be greater than 0 and at most 1.", mark.Opacity))
+	}
+
+	if mark.ImageName == "" && strings.TrimSpace(strings.Join(mark.Lines, "")) == "" {
+		return errorsWithStack.New("A watermark requires lines or an image.")
+	}
+
+	return nil
+}
+
+// apply returns the position of the rotated point x, y.
+func (t *displayTransform) apply(x float64, y float64) (float64, float64) {
+	if t == nil || t.angle == 0 {
+		return x, y
+	}
+
+	sin, cos := math.Sincos(t.angle * math.Pi / 180)
+	dx, dy := x-t.cx, y-t.cy
+	return t.cx + dx*cos + dy*sin, t.cy - dx*sin + dy*cos
+}
+
+// DrawWatermark draws the watermark on the current page, on top of the content drawn so far.
+// Use GoToPage to mark further pages, after their content is printed.
+func (core *PDFGenerator) DrawWatermark(mark Watermark) {
+	if core.strictErrorHandling == true && core.pdf.Err() {
+		return
+	}
+
+	// --> validate inputs
+	if err := mark.validate(); err != nil {
+		core.pdf.SetError(err)
+		return
+	}
+
+	if mark.ImageName != "" && !core.ImageIsRegistered(mark.ImageName) {
+		core.pdf.SetError(errorsWithStack.New(fmt.Sprintf("The watermark image \"%s\" is not registered.", mark.ImageName)))
+		return
+	}
+	// <--
+
+	core.pdf.TransformBegin()
+	core.pdf.TransformRotate(mark.Angle, mark.X, mark.Y)
+	core.pdf.SetAlpha(mark.Opacity, "Normal")
+	core.transform = &displayTransform{angle: mark.Angle, cx: mark.X, cy: mark.Y, opacity: mark.Opacity}
+	defer func() {
+		core.transform = nil
+		core.pdf.SetAlpha(1, "Normal")
+		core.pdf.TransformEnd()
+	}()
+
+	if mark.ImageName != "" {
+		imgWd, imgHt := core.GetRegisteredImageExtent(mark.ImageName)
+		w, h := mark.Width, imgHt*mark.Width/imgWd
+		x, y := mark.X-w/2, mark.Y-h/2
+		if svg, ok := core.registeredSvgImages[mark.ImageName]; ok {
+			core.drawSvgImage(svg, x, y, w, h)
+		} else {
+			core.pdf.Image(mark.ImageName, x, y, w, h, false, core.registeredImageTypes[mark.ImageName], 0, "")
+		}
+		core.record(displayItem{kind: displayImage, x: x, y: y, w: w, h: h, imageName: mark.ImageName})
+		return
+	}
+
+	core.drawWatermarkLines(mark)
+}
+
+// drawWatermarkLines prints the lines of the watermark centered around its center and the frame of a stamp.
+func (core *PDFGenerator) drawWatermarkLines(mark Watermark) {
+	fontSize := core.GetFontSize()
+	pdfFontSize, _ := core.pdf.GetFontSize()
+	textColor := core.GetTextColor()
+	oldX, oldY := core.pdf.GetXY()
+	autoPageBreak, marginBottom := core.pdf.GetAutoPageBreak()
+	core.pdf.SetAutoPageBreak(false, marginBottom)
+	// the font of the pdf is restored as well, because the footer of the last page is printed with it on closing
+	defer func() {
+		core.pdf.SetAutoPageBreak(autoPageBreak, marginBottom)
+		core.pdf.SetXY(oldX, oldY)
+		core.SetTextColor(textColor)
+		core.SetFontSize(fontSize)
+		core.setFont(core.fontFamily, "", pdfFontSize)
+	}()
+
+	// the font size is measured at 100 pt and scaled to the width, a stamp keeps a space to its frame
+	textWidth := mark.Width
+	if mark.Border {
+		textWidth *= .85
+	}
+	core.setFont(core.fontFamily, "b", 100)
+	widest := 0.
+	for i, line := range mark.Lines {
+		scale := 1.
+		if i > 0 {
+			scale = watermarkSubLineScale
+		}
+		widest = math.Max(widest, core.textWidth(line, "b")*scale)
+	}
+	size := 100 * textWidth / widest
+
+	heights := make([]float64, len(mark.Lines))
+	blockHeight := 0.
+	for i := range mark.Lines {
+		heights[i] = core.pdf.PointConvert(size)
+		if i > 0 {
+			heights[i] *= watermarkSubLineScale
+		}
+		blockHeight += heights[i]
+	}
+
+	core.SetTextColor(mark.Color)
+	y := mark.Y - blockHeight/2
+	for i, line := range mark.Lines {
+		if i == 0 {
+			core.SetFontSize(size)
+		} else {
+			core.SetFontSize(size * watermarkSubLineScale)
+		}
+		core.pdf.SetXY(mark.X-textWidth/2, y)
+		core.PrintPdfTextFormatted(line, "b", "C", "", false, Color{}, heights[i], textWidth)
+		y += heights[i]
+	}
+
+	if mark.Border {
+		padding := heights[0] / 4
+		core.DrawRect(mark.X-mark.Width/2, mark.Y-blockHeight/2-padding, mark.Width, blockHeight+2*padding, padding,
+			ShapeStyle{Stroke: true, Line: LineStyle{Color: mark.Color, Width: heights[0] / 12}})
+	}
+}
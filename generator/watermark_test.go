@@ -0,0 +1,139 @@
+package generator
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestPDFGenerator_DrawWatermark(t *testing.T) {
+	tests := []struct {
+		name    string
+		mark    Watermark
+		wantErr bool
+	}{
+		{
+			name:    "diagonal text",
+			mark:    Watermark{Lines: []string{"ENTWURF"}, X: 105, Y: 148.5, Width: 200, Angle: 54.7, Opacity: .15, Color: Color{R: 128, G: 128, B: 128}},
+			wantErr: false,
+		},
+		{
+			name:    "stamp with date",
+			mark:    Watermark{Lines: []string{"BEZAHLT", "24.12.2024"}, X: 150, Y: 60, Width: 50, Angle: 15, Opacity: .7, Color: Color{R: 200}, Border: true},
+			wantErr: false,
+		},
+		{
+			name:    "image",
+			mark:    Watermark{ImageName: "logo", X: 105, Y: 148.5, Width: 100, Angle: 30, Opacity: .2},
+			wantErr: false,
+		},
+		{
+			name:    "no opacity",
+			mark:    Watermark{Lines: []string{"KOPIE"}, X: 105, Y: 148.5, Width: 200},
+			wantErr: true,
+		},
+		{
+			name:    "no width",
+			mark:    Watermark{Lines: []string{"KOPIE"}, X: 105, Y: 148.5, Opacity: .2},
+			wantErr: true,
+		},
+		{
+			name:    "empty lines",
+			mark:    Watermark{Lines: []string{" ", ""}, X: 105, Y: 148.5, Width: 200, Opacity: .2},
+			wantErr: true,
+		},
+		{
+			name:    "image not registered",
+			mark:    Watermark{ImageName: "badge", X: 105, Y: 148.5, Width: 100, Opacity: .2},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, err := NewPDFGenerator(_defaultMetaData, false, &_logger, func() {}, func(isLastPage bool) {})
+			if err != nil {
+				t.Fatalf("init core error\n%s", err.Error())
+			}
+			core.NewPage()
+			core.RegisterImage("logo", testPng(t))
+			core.SetCursor(30, 40)
+			fontSize := core.GetFontSize()
+			pdfFontSize, _ := core.pdf.GetFontSize()
+
+			core.DrawWatermark(tt.mark)
+			if gotErr := core.pdf.Err(); gotErr != tt.wantErr {
+				t.Fatalf("DrawWatermark() set a error = %v, want %v", core.pdf.Error(), tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if core.transform != nil {
+				t.Errorf("DrawWatermark() keeps the transform %v", core.transform)
+			}
+			if x, y := core.GetCursor(); x != 30 || y != 40 {
+				t.Errorf("DrawWatermark() moved the cursor to %v, %v", x, y)
+			}
+			if core.GetFontSize() != fontSize || core.GetTextColor() != (Color{}) {
+				t.Errorf("DrawWatermark() changed the font size to %v and the text color to %v", core.GetFontSize(), core.GetTextColor())
+			}
+			// the footer of the last page is printed with the font of the pdf
+			if got, _ := core.pdf.GetFontSize(); got != pdfFontSize {
+				t.Errorf("DrawWatermark() changed the pdf font size to %v, want %v", got, pdfFontSize)
+			}
+
+			var buffer bytes.Buffer
+			if err = core.Output(&buffer); err != nil {
+				t.Errorf("Output() error = %v", err)
+			}
+		})
+	}
+}
+
+func Test_displayTransform_apply(t *testing.T) {
+	tests := []struct {
+		name      string
+		transform *displayTransform
+		x, y      float64
+		wantX     float64
+		wantY     float64
+	}{
+		{name: "no transform", transform: nil, x: 20, y: 30, wantX: 20, wantY: 30},
+		{name: "counterclockwise on the page", transform: &displayTransform{angle: 90, cx: 100, cy: 100}, x: 110, y: 100, wantX: 100, wantY: 90},
+		{name: "center keeps its position", transform: &displayTransform{angle: 33, cx: 100, cy: 100}, x: 100, y: 100, wantX: 100, wantY: 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotX, gotY := tt.transform.apply(tt.x, tt.y)
+			if math.Abs(gotX-tt.wantX) > 1e-9 || math.Abs(gotY-tt.wantY) > 1e-9 {
+				t.Errorf("apply() = %v, %v, want %v, %v", gotX, gotY, tt.wantX, tt.wantY)
+			}
+		})
+	}
+}
+
+func TestSVGGenerator_Output_watermark(t *testing.T) {
+	core, err := NewSVGGenerator(_defaultMetaData, false, &_logger, func() {}, func(isLastPage bool) {})
+	if err != nil {
+		t.Fatalf("init core error\n%s", err.Error())
+	}
+
+	core.NewPage()
+	core.DrawWatermark(Watermark{Lines: []string{"BEZAHLT", "24.12.2024"}, X: 150, Y: 60, Width: 50, Angle: 15, Opacity: .7, Color: Color{R: 200}, Border: true})
+
+	var buffer bytes.Buffer
+	if err = core.Output(&buffer); err != nil {
+		t.Fatalf("Output() error = %v", err)
+	}
+
+	svg := buffer.String()
+	if got := strings.Count(svg, `<g transform="rotate(-15.000 150.000 60.000)" opacity="0.700">`); got != 3 {
+		t.Errorf("Output() has %d rotated elements, want 3", got)
+	}
+	for _, want := range []string{`fill="#c80000"`, `>BEZAHLT</text>`, `>24.12.2024</text>`, `stroke="#c80000"`} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("Output() does not contain %s", want)
+		}
+	}
+}
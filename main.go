@@ -1,20 +1,35 @@
 package main
 
 import (
+	"SimpleInvoice/generator"
+	"SimpleInvoice/norms/letter"
 	"SimpleInvoice/pdfType"
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
 	"fmt"
 	errorsWithStack "github.com/go-errors/errors"
 	"github.com/rs/zerolog"
+	"io"
 	"log"
 	"net/http"
 	"os"
 	"os/exec"
 	"runtime"
+	"strconv"
+	"strings"
 	"time"
 )
 
 var logger zerolog.Logger
 
+// fontUploadToken enables POST /fonts for requests with the header "Authorization: Bearer <fontUploadToken>".
+// The uploaded font families are used by all later requests, so the upload is disabled without a token.
+var fontUploadToken = ""
+
+// maxFontUploadSize limits the request body of POST /fonts in bytes.
+const maxFontUploadSize = 64 << 20
+
 func invoiceRequest(w http.ResponseWriter, r *http.Request) {
 	h := pdfType.NewInvoice(&logger)
 	executeHandler(h, w, r)
@@ -30,10 +45,95 @@ func attachmentTableRequest(w http.ResponseWriter, r *http.Request) {
 	executeHandler(h, w, r)
 }
 
+func documentRequest(w http.ResponseWriter, r *http.Request) {
+	h := pdfType.NewDocument(&logger)
+	executeHandler(h, w, r)
+}
+
+// fontsRequest lists the registered font families with GET and registers an uploaded font family with POST.
+//
+// The POST request is a multipart form with the field "name" and a font file for each style,
+// e.g. "Regular" and "Bold" (see generator.FontStyles). The uploaded family is kept until the server stops.
+// It requires the fontUploadToken and can neither replace a registered family nor exceed the limits
+// of generator.DefaultFontRegistry.
+func fontsRequest(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		if fontUploadToken == "" {
+			http.Error(w, "The upload of fonts is disabled, set the fontUploadToken of the server.", http.StatusForbidden)
+			return
+		}
+		authorization := r.Header.Get("Authorization")
+		token := strings.TrimPrefix(authorization, "Bearer ")
+		if token == authorization || subtle.ConstantTimeCompare([]byte(token), []byte(fontUploadToken)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "The upload of fonts requires the font upload token.", http.StatusUnauthorized)
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxFontUploadSize)
+		err := registerUploadedFont(r)
+		if err != nil {
+			logError(err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, fmt.Sprintf("The method %s is not allowed, use GET or POST.", r.Method), http.StatusMethodNotAllowed)
+		return
+	}
+
+	families, err := generator.DefaultFontRegistry.Families()
+	if err != nil {
+		logError(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(map[string][]string{"families": families})
+	if err != nil {
+		logError(err)
+	}
+}
+
+// registerUploadedFont registers the font family of a multipart POST request in the default font registry.
+func registerUploadedFont(r *http.Request) (err error) {
+	if err = r.ParseMultipartForm(32 << 20); err != nil {
+		return err
+	}
+	defer func() {
+		_ = r.MultipartForm.RemoveAll()
+	}()
+
+	family := generator.FontFamily{Name: r.FormValue("name"), Styles: map[string][]byte{}}
+	for styleName, styleStr := range generator.FontStyles {
+		file, _, err := r.FormFile(styleName)
+		if err == http.ErrMissingFile {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		family.Styles[styleStr], err = io.ReadAll(file)
+		_ = file.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return generator.DefaultFontRegistry.Register(family)
+}
+
 func handleRequests() {
 	http.HandleFunc("/invoice", invoiceRequest)
 	http.HandleFunc("/delivery-node", deliveryNodeRequest)
 	http.HandleFunc("/attachment/table", attachmentTableRequest)
+	http.HandleFunc("/document", documentRequest)
+	http.HandleFunc("/fonts", fontsRequest)
 	logger.Debug().Msg("start server on localhost:10000")
 	log.Fatal(http.ListenAndServe(":10000", nil))
 }
@@ -43,12 +143,30 @@ func main() {
 	const loggingLevel = 0
 	const logDir = ""
 	const openBrowserOnStartup = false
+	const fontDir = ""
+	const assetDir = ""
+	const imageHosts = ""
+	const imagePrivateNetworks = false
+	const fontToken = ""
 
 	err := initLogger(loggingLevel, logDir)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
 
+	// the bundled fonts are embedded, fontDir adds further font files of the server
+	generator.FontDir = fontDir
+	// POST /fonts registers font families for all requests, it is disabled without fontToken
+	fontUploadToken = fontToken
+	// images referenced as "asset:<path>" are read from assetDir
+	pdfType.AssetDir = assetDir
+	// remote images are downloaded from all hosts or only from the comma separated imageHosts, e.g. "cdn.example.com",
+	// but neither from loopback, private nor link-local addresses without imagePrivateNetworks
+	if imageHosts != "" {
+		generator.DefaultImageCache.AllowedHosts = strings.Split(imageHosts, ",")
+	}
+	generator.DefaultImageCache.AllowPrivateNetworks = imagePrivateNetworks
+
 	if openBrowserOnStartup {
 		go openBrowser("http://localhost:10000/")
 	}
@@ -57,26 +175,240 @@ func main() {
 }
 
 func executeHandler(handler pdfType.PdfType, w http.ResponseWriter, r *http.Request) {
-	err := handler.SetDataFromRequest(r)
+	format, err := negotiateOutputFormat(r)
+	if err != nil {
+		logError(err)
+		http.Error(w, err.Error(), http.StatusNotAcceptable)
+		return
+	}
+
+	err = setGeneratorBackend(handler, format, r)
+	if err != nil {
+		logError(err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = setDebugOverlay(handler, r)
+	if err != nil {
+		logError(err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	checkHandler, check, err := checkRequested(handler, r)
+	if err != nil {
+		logError(err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = handler.SetDataFromRequest(r)
 	if err != nil {
 		logError(err)
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	pdf, err := handler.GeneratePDF()
+	var output bytes.Buffer
+	contentType := outputContentTypes[format]
+	switch {
+	case check:
+		// dry-run, the document is generated to measure the zones, but only the report is returned
+		_, err = handler.GeneratePDF()
+		if err == nil {
+			err = json.NewEncoder(&output).Encode(checkHandler.ComplianceReport())
+		}
+		contentType = "application/json"
+	case format == "html":
+		err = handler.RenderHTML(&output)
+	case format == "text":
+		err = handler.RenderText(&output)
+	default:
+		var pdf generator.Generator
+		pdf, err = handler.GeneratePDF()
+		if err == nil {
+			err = pdf.Output(&output)
+		}
+	}
 	if err != nil {
 		logError(err)
+		if errorsWithStack.Is(err, generator.ErrPreviewTooLarge) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	err = pdf.Output(w)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Vary", "Accept")
+	_, err = output.WriteTo(w)
 	if err != nil {
 		logError(err)
 	}
 }
 
+// outputContentTypes maps the supported output formats to the content type of the response.
+var outputContentTypes = map[string]string{
+	"pdf":  "application/pdf",
+	"svg":  "image/svg+xml",
+	"png":  "image/png",
+	"html": "text/html; charset=utf-8",
+	"text": "text/plain; charset=utf-8",
+}
+
+// negotiateOutputFormat returns the output format of the "format" query parameter (pdf, svg, png, html or text).
+// Without the query parameter, the format is negotiated by the Accept header of the request.
+// The PDF is returned, if no Accept header or "*/*" is given.
+func negotiateOutputFormat(r *http.Request) (format string, err error) {
+	if format = r.URL.Query().Get("format"); format != "" {
+		if outputContentTypes[format] == "" {
+			return "", errorsWithStack.New(fmt.Sprintf("The format \"%s\" is not supported, use pdf, svg, png, html or text.", format))
+		}
+		return format, nil
+	}
+
+	accept := r.Header.Get("Accept")
+	if strings.TrimSpace(accept) == "" {
+		return "pdf", nil
+	}
+
+	mediaRangeFormats := map[string]string{
+		"*/*":             "pdf",
+		"application/*":   "pdf",
+		"application/pdf": "pdf",
+		"image/*":         "png",
+		"image/png":       "png",
+		"image/svg+xml":   "svg",
+		"text/*":          "html",
+		"text/html":       "html",
+		"text/plain":      "text",
+	}
+
+	var bestQuality float64
+	for _, mediaRange := range strings.Split(accept, ",") {
+		params := strings.Split(mediaRange, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+
+		quality := 1.
+		for _, param := range params[1:] {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if name == "q" {
+				quality, err = strconv.ParseFloat(value, 64)
+				if err != nil {
+					quality = 0
+				}
+			}
+		}
+
+		// the first media range wins, if the quality is equal
+		if mediaFormat := mediaRangeFormats[mediaType]; mediaFormat != "" && quality > bestQuality {
+			format, bestQuality = mediaFormat, quality
+		}
+	}
+
+	if format == "" {
+		return "", errorsWithStack.New(fmt.Sprintf("None of the accepted media types \"%s\" is supported, use application/pdf, image/svg+xml, image/png, text/html or text/plain.", accept))
+	}
+
+	return format, nil
+}
+
+// setGeneratorBackend selects the generator backend of the handler for the svg and png format.
+//
+// The png format renders one page (query parameter "page", default 1) or all pages ("page=all")
+// with the resolution of the query parameter "dpi" (default 96).
+func setGeneratorBackend(handler pdfType.PdfType, format string, r *http.Request) (err error) {
+	query := r.URL.Query()
+
+	switch format {
+	case "svg":
+		handler.SetGeneratorBackend(generator.NewSVGGeneratorBackend)
+	case "png":
+		page := 1
+		if pageStr := query.Get("page"); pageStr == "all" {
+			page = 0
+		} else if pageStr != "" {
+			page, err = strconv.Atoi(pageStr)
+			if err != nil || page < 1 {
+				return errorsWithStack.New(fmt.Sprintf("The page \"%s\" must be a page number or \"all\".", pageStr))
+			}
+		}
+
+		dpi := 96.
+		if dpiStr := query.Get("dpi"); dpiStr != "" {
+			dpi, err = strconv.ParseFloat(dpiStr, 64)
+			if err != nil || dpi < 10 || dpi > 600 {
+				return errorsWithStack.New(fmt.Sprintf("The dpi \"%s\" must be a number in range [10, 600].", dpiStr))
+			}
+		}
+
+		handler.SetGeneratorBackend(generator.NewPNGGeneratorBackend(page, dpi))
+	}
+
+	return nil
+}
+
+// debugOverlayHandler is implemented by the pdf types, which can draw a debug overlay (e.g. pdfType.Invoice).
+type debugOverlayHandler interface {
+	SetDebugOverlay(enabled bool)
+}
+
+// setDebugOverlay enables the debug overlay of the handler with the query parameter "debug=true".
+// The parameter is ignored by handlers without a debug overlay.
+func setDebugOverlay(handler pdfType.PdfType, r *http.Request) (err error) {
+	debugHandler, ok := handler.(debugOverlayHandler)
+	if !ok {
+		return nil
+	}
+
+	enabled, err := boolQuery(r, "debug")
+	if err != nil {
+		return err
+	}
+
+	debugHandler.SetDebugOverlay(enabled)
+
+	return nil
+}
+
+// complianceHandler is implemented by the pdf types, which check the zones of the letter norm (e.g. pdfType.Invoice).
+type complianceHandler interface {
+	ComplianceReport() letter.Report
+}
+
+// checkRequested returns true, if the compliance report is requested with the query parameter "check=true".
+// A handler without a compliance report returns an error for "check=true".
+func checkRequested(handler pdfType.PdfType, r *http.Request) (checkHandler complianceHandler, check bool, err error) {
+	check, err = boolQuery(r, "check")
+	if err != nil || !check {
+		return nil, false, err
+	}
+
+	checkHandler, ok := handler.(complianceHandler)
+	if !ok {
+		return nil, false, errorsWithStack.New(fmt.Sprintf("The endpoint %s has no compliance report, remove the query parameter \"check\".", r.URL.Path))
+	}
+
+	return checkHandler, true, nil
+}
+
+// boolQuery returns the boolean value of the query parameter name, false if the parameter is not set.
+func boolQuery(r *http.Request, name string) (value bool, err error) {
+	valueStr := r.URL.Query().Get(name)
+	if valueStr == "" {
+		return false, nil
+	}
+
+	value, err = strconv.ParseBool(valueStr)
+	if err != nil {
+		return false, errorsWithStack.New(fmt.Sprintf("The %s \"%s\" must be true or false.", name, valueStr))
+	}
+
+	return value, nil
+}
+
 func openBrowser(url string) {
 	var err error
 
@@ -0,0 +1,89 @@
+package letter
+
+import (
+	"strings"
+)
+
+// addressFormat is the postal address format of a country.
+//
+// Each line is a template with the placeholders {road}, {houseNumber}, {streetSupplement}, {zipCode}, {city} and {state}.
+// A missing value is removed with the following space. Lines, which are empty after replacing the placeholders, are skipped.
+// If upperCaseCity is true, the city is always printed in capitals, e.g. the post town in the UK.
+type addressFormat struct {
+	lines         []string
+	upperCaseCity bool
+}
+
+// defaultAddressFormat is used for countries without an entry in addressFormats, e.g. for Germany.
+var defaultAddressFormat = addressFormat{lines: []string{"{road} {houseNumber}", "{streetSupplement}", "{zipCode} {city}"}}
+
+// addressFormats maps the ISO 3166-1 alpha-2 country codes to the postal address formats.
+var addressFormats = map[string]addressFormat{
+	"AT": defaultAddressFormat,
+	"BE": defaultAddressFormat,
+	"CH": defaultAddressFormat,
+	"DE": defaultAddressFormat,
+	"DK": defaultAddressFormat,
+	"IT": defaultAddressFormat,
+	"PL": defaultAddressFormat,
+	"SE": defaultAddressFormat,
+	"ES": {lines: []string{"{road}, {houseNumber}", "{streetSupplement}", "{zipCode} {city}"}},
+	"NL": {lines: []string{"{road} {houseNumber}", "{streetSupplement}", "{zipCode}  {city}"}, upperCaseCity: true},
+	"FR": {lines: []string{"{houseNumber} {road}", "{streetSupplement}", "{zipCode} {city}"}, upperCaseCity: true},
+	"GB": {lines: []string{"{houseNumber} {road}", "{streetSupplement}", "{city}", "{zipCode}"}, upperCaseCity: true},
+	"IE": {lines: []string{"{houseNumber} {road}", "{streetSupplement}", "{city}", "{zipCode}"}},
+	"US": {lines: []string{"{houseNumber} {road}", "{streetSupplement}", "{city} {state} {zipCode}"}},
+	"CA": {lines: []string{"{houseNumber} {road}", "{streetSupplement}", "{city} {state} {zipCode}"}},
+	"AU": {lines: []string{"{houseNumber} {road}", "{streetSupplement}", "{city} {state} {zipCode}"}, upperCaseCity: true},
+}
+
+// PostalLines returns the lines of address below the name: the road with the house number, the street supplement,
+// the postcode, the city and the state, ordered by the format of the country (see Address.CountryCode).
+// The state is only printed in the countries with states in the address, i.e. US, CA and AU.
+//
+// For international mail, the city is printed in capitals.
+func PostalLines(address FullAdresse, international bool) (lines []string) {
+	format, ok := addressFormats[strings.ToUpper(address.Address.CountryCode)]
+	if !ok {
+		format = defaultAddressFormat
+	}
+
+	city := address.Address.CityName
+	if format.upperCaseCity || international {
+		city = strings.ToUpper(city)
+	}
+
+	values := []string{
+		"{road}", address.Address.Road,
+		"{houseNumber}", address.Address.HouseNumber,
+		"{streetSupplement}", address.Address.StreetSupplement,
+		"{zipCode}", address.Address.ZipCode,
+		"{city}", city,
+		"{state}", address.Address.State,
+	}
+	// a missing value in the middle of a line is removed with its space, e.g. a US address without state
+	var oldnew []string
+	for i := 0; i < len(values); i += 2 {
+		if values[i+1] == "" {
+			oldnew = append(oldnew, values[i]+" ", "")
+		}
+	}
+	replacer := strings.NewReplacer(append(oldnew, values...)...)
+
+	for _, lineTemplate := range format.lines {
+		// trim the separators of missing values, e.g. a road without house number
+		if line := strings.Trim(replacer.Replace(lineTemplate), " ,"); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
+// IsInternational returns true, if the receiver address is in another country than the sender.
+// The mail is domestic, if one of the country codes is unknown.
+func IsInternational(receiverAddress FullAdresse, senderCountryCode string) bool {
+	receiverCountryCode := receiverAddress.Address.CountryCode
+
+	return receiverCountryCode != "" && senderCountryCode != "" && !strings.EqualFold(receiverCountryCode, senderCountryCode)
+}
@@ -0,0 +1,119 @@
+package letter
+
+import (
+	"strings"
+	"testing"
+)
+
+// testPostalAddress returns an address in countryCode with the road, the house number, the postcode, the city and the state.
+func testPostalAddress(countryCode string, road string, houseNumber string, zipCode string, cityName string, state string) (address FullAdresse) {
+	address.Address.Road = road
+	address.Address.HouseNumber = houseNumber
+	address.Address.ZipCode = zipCode
+	address.Address.CityName = cityName
+	address.Address.State = state
+	address.Address.CountryCode = countryCode
+	return address
+}
+
+func TestPostalLines(t *testing.T) {
+	tests := []struct {
+		name          string
+		address       FullAdresse
+		international bool
+		want          []string
+	}{
+		{name: "AT", address: testPostalAddress("AT", "Stephansplatz", "1", "1010", "Wien", ""), want: []string{"Stephansplatz 1", "1010 Wien"}},
+		{name: "BE", address: testPostalAddress("BE", "Rue de la Loi", "16", "1000", "Bruxelles", ""), want: []string{"Rue de la Loi 16", "1000 Bruxelles"}},
+		{name: "CH", address: testPostalAddress("CH", "Bundesplatz", "3", "3003", "Bern", ""), want: []string{"Bundesplatz 3", "3003 Bern"}},
+		{name: "DE", address: testPostalAddress("DE", "Musterstraße", "42", "01234", "Musterstadt", ""), want: []string{"Musterstraße 42", "01234 Musterstadt"}},
+		{name: "DK", address: testPostalAddress("DK", "Prins Jørgens Gård", "11", "1218", "København K", ""), want: []string{"Prins Jørgens Gård 11", "1218 København K"}},
+		{name: "IT", address: testPostalAddress("IT", "Via del Corso", "12", "00186", "Roma", ""), want: []string{"Via del Corso 12", "00186 Roma"}},
+		{name: "PL", address: testPostalAddress("PL", "ul. Wiejska", "4", "00-902", "Warszawa", ""), want: []string{"ul. Wiejska 4", "00-902 Warszawa"}},
+		{name: "SE", address: testPostalAddress("SE", "Drottninggatan", "1", "111 51", "Stockholm", ""), want: []string{"Drottninggatan 1", "111 51 Stockholm"}},
+		{name: "ES", address: testPostalAddress("ES", "Calle Mayor", "5", "28013", "Madrid", ""), want: []string{"Calle Mayor, 5", "28013 Madrid"}},
+		{name: "ES without house number", address: testPostalAddress("ES", "Calle Mayor", "", "28013", "Madrid", ""), want: []string{"Calle Mayor", "28013 Madrid"}},
+		{name: "NL", address: testPostalAddress("NL", "Binnenhof", "1", "2513 AA", "Den Haag", ""), want: []string{"Binnenhof 1", "2513 AA  DEN HAAG"}},
+		{name: "FR", address: testPostalAddress("FR", "rue de Rivoli", "10", "75001", "Paris", ""), want: []string{"10 rue de Rivoli", "75001 PARIS"}},
+		{name: "GB", address: testPostalAddress("GB", "Downing Street", "10", "SW1A 2AA", "London", ""), want: []string{"10 Downing Street", "LONDON", "SW1A 2AA"}},
+		{name: "IE", address: testPostalAddress("IE", "Kildare Street", "2", "D02 A272", "Dublin 2", ""), want: []string{"2 Kildare Street", "Dublin 2", "D02 A272"}},
+		{name: "US", address: testPostalAddress("US", "Main Street", "742", "62701", "Springfield", "IL"), want: []string{"742 Main Street", "Springfield IL 62701"}},
+		{name: "US without state", address: testPostalAddress("US", "Main Street", "742", "62701", "Springfield", ""), want: []string{"742 Main Street", "Springfield 62701"}},
+		{name: "CA", address: testPostalAddress("CA", "Wellington Street", "111", "K1A 0A9", "Ottawa", "ON"), want: []string{"111 Wellington Street", "Ottawa ON K1A 0A9"}},
+		{name: "AU", address: testPostalAddress("AU", "George Street", "1", "2000", "Sydney", "NSW"), want: []string{"1 George Street", "SYDNEY NSW 2000"}},
+		{name: "lower case country code", address: testPostalAddress("fr", "rue de Rivoli", "10", "75001", "Paris", ""), want: []string{"10 rue de Rivoli", "75001 PARIS"}},
+		{name: "unknown country code", address: testPostalAddress("XX", "Musterstraße", "42", "01234", "Musterstadt", "Bayern"), want: []string{"Musterstraße 42", "01234 Musterstadt"}},
+		{name: "international", address: testPostalAddress("DE", "Musterstraße", "42", "01234", "Musterstadt", ""), international: true, want: []string{"Musterstraße 42", "01234 MUSTERSTADT"}},
+		{name: "international US", address: testPostalAddress("US", "Main Street", "742", "62701", "Springfield", "IL"), international: true, want: []string{"742 Main Street", "SPRINGFIELD IL 62701"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PostalLines(tt.address, tt.international); strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("PostalLines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostalLines_allFormats(t *testing.T) {
+	// every format prints the street supplement in its own line below the road
+	for countryCode := range addressFormats {
+		t.Run(countryCode, func(t *testing.T) {
+			address := testPostalAddress(countryCode, "Road", "1", "12345", "City", "")
+			address.Address.StreetSupplement = "Supplement"
+
+			lines := PostalLines(address, false)
+			if len(lines) < 3 || lines[1] != "Supplement" {
+				t.Errorf("PostalLines() = %q, want the street supplement in the second line", lines)
+			}
+		})
+	}
+}
+
+func TestReceiverAddressLines(t *testing.T) {
+	withCountry := func(address FullAdresse, country string) FullAdresse {
+		address.Address.Country = country
+		return address
+	}
+	french := testPostalAddress("FR", "rue de Rivoli", "10", "75001", "Paris", "")
+	french.CompanyName = "Exemple SARL"
+
+	tests := []struct {
+		name              string
+		address           FullAdresse
+		senderCountryCode string
+		want              []string
+	}{
+		{
+			name:              "international with country",
+			address:           withCountry(french, "France"),
+			senderCountryCode: "DE",
+			want:              []string{"Exemple SARL", "10 rue de Rivoli", "75001 PARIS", "FRANCE"},
+		},
+		{
+			name:              "international without country",
+			address:           french,
+			senderCountryCode: "DE",
+			want:              []string{"Exemple SARL", "10 rue de Rivoli", "75001 PARIS", "FR"},
+		},
+		{
+			name:              "domestic",
+			address:           withCountry(french, "France"),
+			senderCountryCode: "fr",
+			want:              []string{"Exemple SARL", "10 rue de Rivoli", "75001 PARIS"},
+		},
+		{
+			name:              "unknown sender country",
+			address:           withCountry(french, "France"),
+			senderCountryCode: "",
+			want:              []string{"Exemple SARL", "10 rue de Rivoli", "75001 PARIS", "France"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ReceiverAddressLines(tt.address, tt.senderCountryCode); strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("ReceiverAddressLines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
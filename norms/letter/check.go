@@ -0,0 +1,197 @@
+package letter
+
+import (
+	"SimpleInvoice/generator"
+	"fmt"
+	"math"
+)
+
+// levels of an Issue
+const (
+	IssueWarning = "warning"
+	IssueError   = "error"
+)
+
+// FontSizeSenderMin is the smallest font size in pt, the sender line is shrunk to (see PrintSenderAddress).
+const FontSizeSenderMin = 6.
+
+// Issue is a zone overflow found by Check.
+//
+// A warning is fixed while printing, e.g. by shrinking the font.
+// An error breaks the norm, e.g. the content is truncated or not visible through the envelope window.
+type Issue struct {
+	Zone    string `json:"zone"`
+	Level   string `json:"level"`
+	Message string `json:"message"`
+}
+
+// Report sums the issues of a letter. A letter is compliant, if there is no error.
+type Report struct {
+	Norm      string  `json:"norm"`
+	Compliant bool    `json:"compliant"`
+	Issues    []Issue `json:"issues"`
+}
+
+// CheckContent sums the content of the zones measured by Check.
+type CheckContent struct {
+	SenderAddress   FullAdresse
+	ReceiverAddress FullAdresse
+	Info            []InfoData
+	FooterColumns   [][]string
+}
+
+// Check measures the content of the sender line, the receiver address, the info block and the footer
+// with the fonts of the letter parts and reports each zone overflow.
+// Nothing is printed.
+func Check(pdfGen generator.Generator, norm Norm, content CheckContent) (report Report) {
+	zones := norm.Zones()
+	fontSize := pdfGen.GetFontSize()
+
+	report = Report{Norm: norm.Name(), Issues: []Issue{}}
+	report.Issues = append(report.Issues, checkSenderAddress(pdfGen, zones.AddressSender, content.SenderAddress)...)
+	report.Issues = append(report.Issues, checkReceiverAddress(pdfGen, zones.AddressReceiver, content.ReceiverAddress, content.SenderAddress.Address.CountryCode)...)
+	report.Issues = append(report.Issues, checkMetaInfo(pdfGen, zones.MetaInfo, content.Info)...)
+	report.Issues = append(report.Issues, checkFooter(pdfGen, zones, content.FooterColumns)...)
+
+	pdfGen.SetFontSize(fontSize)
+
+	report.Compliant = true
+	for _, issue := range report.Issues {
+		if issue.Level == IssueError {
+			report.Compliant = false
+		}
+	}
+
+	return report
+}
+
+func checkSenderAddress(pdfGen generator.Generator, zone Zone, senderAddress FullAdresse) (issues []Issue) {
+	companyLine, roadLine := SenderAddressLines(senderAddress)
+	width := zone.StopX - zone.StartX
+
+	fontSize := senderFontSize(pdfGen, zone, senderAddress)
+	if fontSize == FontSizeSmall {
+		return nil
+	}
+
+	pdfGen.SetFontSize(fontSize)
+	for _, line := range []string{companyLine, roadLine} {
+		if lineWidth := pdfGen.ComputeStringLength(line); lineWidth > width {
+			return append(issues, Issue{Zone: "addressSender", Level: IssueError, Message: fmt.Sprintf(
+				"the sender line \"%s\" is %.1f mm wide with %.1f pt, the zone is %.1f mm wide", line, lineWidth, fontSize, width)})
+		}
+	}
+
+	return append(issues, Issue{Zone: "addressSender", Level: IssueWarning, Message: fmt.Sprintf(
+		"the sender line is too wide for %.1f mm, it is printed with %.1f pt instead of %.1f pt", width, fontSize, FontSizeSmall)})
+}
+
+func checkReceiverAddress(pdfGen generator.Generator, zone Zone, receiverAddress FullAdresse, senderCountryCode string) (issues []Issue) {
+	lines := ReceiverAddressLines(receiverAddress, senderCountryCode)
+	width := zone.StopX - zone.StartX
+
+	if maxLines := zoneLines(pdfGen, zone, FontSizeDefault, FontGapSmall); len(lines) > maxLines {
+		issues = append(issues, Issue{Zone: "addressReceiver", Level: IssueError, Message: fmt.Sprintf(
+			"the receiver address has %d lines, the zone fits %d lines, the remaining lines are not printed", len(lines), maxLines)})
+	}
+
+	pdfGen.SetFontSize(FontSizeDefault)
+	for _, line := range lines {
+		if lineWidth := pdfGen.ComputeStringLength(line); lineWidth > width {
+			issues = append(issues, Issue{Zone: "addressReceiver", Level: IssueError, Message: fmt.Sprintf(
+				"the receiver line \"%s\" is %.1f mm wide, the zone is %.1f mm wide", line, lineWidth, width)})
+		}
+	}
+
+	return issues
+}
+
+func checkMetaInfo(pdfGen generator.Generator, zone Zone, data []InfoData) (issues []Issue) {
+	width := zone.StopX - zone.StartX
+
+	if maxLines := zoneLines(pdfGen, zone, FontSizeDefault, FontGapDefault); len(data) > maxLines {
+		issues = append(issues, Issue{Zone: "metaInfo", Level: IssueError, Message: fmt.Sprintf(
+			"the info block has %d rows, the zone fits %d rows, the remaining rows are not printed", len(data), maxLines)})
+	}
+
+	pdfGen.SetFontSize(FontSizeDefault)
+	var maxNameLength, maxValueLength float64
+	for _, datum := range data {
+		if nameLength := pdfGen.ComputeStringLength(datum.Name); nameLength > maxNameLength {
+			maxNameLength = nameLength
+		}
+		if valueLength := pdfGen.ComputeStringLength(datum.Value); valueLength > maxValueLength {
+			maxValueLength = valueLength
+		}
+	}
+
+	if infoWidth := maxNameLength + metaInfoGapNameValue + maxValueLength; infoWidth > width {
+		issues = append(issues, Issue{Zone: "metaInfo", Level: IssueWarning, Message: fmt.Sprintf(
+			"the info block is %.1f mm wide, the zone is %.1f mm wide", infoWidth, width)})
+	}
+
+	return issues
+}
+
+// checkFooter checks the footer columns, printed side by side like in the pdf types:
+// the first column left aligned, the last column right aligned and a middle column centered.
+func checkFooter(pdfGen generator.Generator, zones Zones, columns [][]string) (issues []Issue) {
+	if len(columns) == 0 {
+		return nil
+	}
+
+	var footerLines int
+	for _, column := range columns {
+		if len(column) > footerLines {
+			footerLines = len(column)
+		}
+	}
+
+	if bodyStopY := BodyStopY(zones, footerLines); bodyStopY <= zones.BodyStartY {
+		issues = append(issues, Issue{Zone: "footer", Level: IssueError, Message: fmt.Sprintf(
+			"the footer has %d lines and leaves no space for the body", footerLines)})
+	}
+
+	pdfGen.SetFontSize(FontSizeDefault)
+	columnWidth := (zones.BodyStopX - zones.BodyStartX) / float64(len(columns))
+	for i, column := range columns {
+		for _, line := range column {
+			if lineWidth := pdfGen.ComputeStringLength(line); lineWidth > columnWidth {
+				issues = append(issues, Issue{Zone: "footer", Level: IssueWarning, Message: fmt.Sprintf(
+					"the line \"%s\" of footer column %d is %.1f mm wide and overlaps the neighbour column of %.1f mm", line, i+1, lineWidth, columnWidth)})
+			}
+		}
+	}
+
+	return issues
+}
+
+// senderFontSize returns the font size of the sender lines, shrunk to the width of the zone,
+// but not smaller than FontSizeSenderMin.
+func senderFontSize(pdfGen generator.Generator, zone Zone, senderAddress FullAdresse) float64 {
+	companyLine, roadLine := SenderAddressLines(senderAddress)
+	width := zone.StopX - zone.StartX
+
+	fontSize := pdfGen.GetFontSize()
+	pdfGen.SetFontSize(FontSizeSmall)
+
+	fitSize := FontSizeSmall
+	for _, line := range []string{companyLine, roadLine} {
+		if lineWidth := pdfGen.ComputeStringLength(line); lineWidth > width && FontSizeSmall*width/lineWidth < fitSize {
+			fitSize = FontSizeSmall * width / lineWidth
+		}
+	}
+
+	pdfGen.SetFontSize(fontSize)
+
+	if fitSize < FontSizeSenderMin {
+		return FontSizeSenderMin
+	}
+	// round down to 0.1 pt, so the shrunk line is not wider than the zone
+	return math.Floor(fitSize*10) / 10
+}
+
+// zoneLines returns the number of text lines fitting into the zone. The last line needs no gap.
+func zoneLines(pdfGen generator.Generator, zone Zone, fontSize float64, fontGapY float64) int {
+	return int((zone.StopY - zone.StartY + fontGapY) / pdfGen.GetLineHeight(fontSize, fontGapY))
+}
@@ -0,0 +1,134 @@
+package letter
+
+import (
+	"SimpleInvoice/generator"
+	"github.com/rs/zerolog"
+	"strings"
+	"testing"
+)
+
+var _logger = zerolog.Nop()
+
+// _testMetaData is the meta data of the recording generators in the tests.
+var _testMetaData = generator.MetaData{
+	FontName: "OpenSans",
+	FontGapY: FontGapDefault,
+	FontSize: FontSizeDefault,
+	Unit:     "mm",
+}
+
+// _testZones are the zones of DIN 5008 Form A.
+var _testZones = Zones{
+	PageWidth:  210,
+	PageHeight: 297,
+
+	Header:          Zone{StartX: 0, StartY: 0, StopX: 210, StopY: 27},
+	AddressSender:   Zone{StartX: 25, StartY: 27, StopX: 105, StopY: 44.7},
+	AddressReceiver: Zone{StartX: 25, StartY: 44.7, StopX: 105, StopY: 72},
+	MetaInfo:        Zone{StartX: 125, StartY: 32, StopX: 200, StopY: 95},
+
+	BodyStartX:     25,
+	BodyStartY:     103.46,
+	BodyStopX:      190,
+	NextPageStartY: 27,
+
+	MarginPageNumberY: 4.23,
+}
+
+// testAddress returns a German address of companyName.
+func testAddress(companyName string) (address FullAdresse) {
+	address.CompanyName = companyName
+	address.Address.Road = "Musterstraße"
+	address.Address.HouseNumber = "42"
+	address.Address.ZipCode = "01234"
+	address.Address.CityName = "Musterstadt"
+	address.Address.CountryCode = "DE"
+	return address
+}
+
+// testInfoData returns rows of the info block.
+func testInfoData(rows int) (data []InfoData) {
+	for i := 0; i < rows; i++ {
+		data = append(data, InfoData{Name: "Rechnungsnummer:", Value: "XI-23045"})
+	}
+	return data
+}
+
+func TestCheck(t *testing.T) {
+	// the receiver address in the UK has 7 lines, the receiver zone fits 6 lines
+	ukAddress := testAddress("Musterfirma Ltd")
+	ukAddress.FullForename = "John"
+	ukAddress.FullSurname = "Smith"
+	ukAddress.Address.StreetSupplement = "Flat 3"
+	ukAddress.Address.CityName = "London"
+	ukAddress.Address.ZipCode = "SW1A 1AA"
+	ukAddress.Address.CountryCode = "GB"
+
+	tests := []struct {
+		name          string
+		content       CheckContent
+		wantCompliant bool
+		wantIssues    []string
+	}{
+		{
+			name:          "compliant",
+			content:       CheckContent{SenderAddress: testAddress("Musterfirma GbR"), ReceiverAddress: testAddress("Kunde GmbH"), Info: testInfoData(4)},
+			wantCompliant: true,
+			wantIssues:    nil,
+		},
+		{
+			name:          "sender line is shrunk",
+			content:       CheckContent{SenderAddress: testAddress(strings.Repeat("M", 60)), ReceiverAddress: testAddress("Kunde GmbH")},
+			wantCompliant: true,
+			wantIssues:    []string{"addressSender warning"},
+		},
+		{
+			name:          "sender line is too wide for the smallest font",
+			content:       CheckContent{SenderAddress: testAddress(strings.Repeat("M", 100)), ReceiverAddress: testAddress("Kunde GmbH")},
+			wantCompliant: false,
+			wantIssues:    []string{"addressSender error"},
+		},
+		{
+			name:          "receiver address has too many lines",
+			content:       CheckContent{SenderAddress: testAddress("Musterfirma GbR"), ReceiverAddress: ukAddress},
+			wantCompliant: false,
+			wantIssues:    []string{"addressReceiver error"},
+		},
+		{
+			name:          "info block has too many rows",
+			content:       CheckContent{SenderAddress: testAddress("Musterfirma GbR"), ReceiverAddress: testAddress("Kunde GmbH"), Info: testInfoData(11)},
+			wantCompliant: false,
+			wantIssues:    []string{"metaInfo error"},
+		},
+		{
+			name:          "footer leaves no space for the body",
+			content:       CheckContent{SenderAddress: testAddress("Musterfirma GbR"), ReceiverAddress: testAddress("Kunde GmbH"), FooterColumns: [][]string{make([]string, 50)}},
+			wantCompliant: false,
+			wantIssues:    []string{"footer error"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, err := generator.NewRecordingGenerator(_testMetaData, false, &_logger, func() {}, func(isLastPage bool) {})
+			if err != nil {
+				t.Fatalf("init recorder error\n%s", err.Error())
+			}
+
+			report := Check(rec, NewTemplate("test", _testZones, PageNumbers{Prefix: "Seite", Of: "von"}), tt.content)
+			if report.Compliant != tt.wantCompliant {
+				t.Errorf("Check() compliant = %v, want %v", report.Compliant, tt.wantCompliant)
+			}
+
+			var gotIssues []string
+			for _, issue := range report.Issues {
+				gotIssues = append(gotIssues, issue.Zone+" "+issue.Level)
+			}
+			if strings.Join(gotIssues, ", ") != strings.Join(tt.wantIssues, ", ") {
+				t.Errorf("Check() issues = %v, want %v\n%v", gotIssues, tt.wantIssues, report.Issues)
+			}
+			if len(rec.Operations()) != 0 {
+				t.Errorf("Check() printed %v", rec.Operations())
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package letter
+
+import (
+	"SimpleInvoice/generator"
+)
+
+// Norm is a business letter layout, e.g. DIN 5008 Form A.
+// A Norm defines the zones of the letter page and prints the norm specific parts of a letter.
+// All values are measured in mm.
+//
+// The pdf types only use this interface, so a new letter layout can be added by implementing Norm,
+// usually with NewTemplate for the zones of the layout.
+type Norm interface {
+	// Name returns the identifier of the norm, e.g. "din-5008-a".
+	Name() string
+
+	// Zones returns the geometry of the letter page.
+	Zones() Zones
+
+	// PageNumberPrefix returns the prefix of the page numbers printed by PageNumbering, e.g. "Seite".
+	PageNumberPrefix() string
+
+	// FullAddressesAndInfoPart prints the sender, the receiver address and the info block.
+	FullAddressesAndInfoPart(pdfGen generator.Generator, senderAddress FullAdresse, receiverAddress FullAdresse, data []InfoData)
+
+	// MimeImageHeader prints a logo image into the header zone, see ImagePlacement.
+	MimeImageHeader(pdfGen generator.Generator, strUrl string, placement ImagePlacement)
+
+	// MimeImageFooter prints a further image, e.g. a certification badge, into the footer zone above the footer starting at footerStartY.
+	MimeImageFooter(pdfGen generator.Generator, strUrl string, placement ImagePlacement, footerStartY float64)
+
+	// Footer prints the footer lines and the content returned by content at the bottom of the page.
+	Footer(content func(maxFooterHeight float64) (footerStartY float64), pdfGen generator.Generator) (footerStartY float64, err error)
+
+	// PageNumbering prints the page numbers above the footer, if the letter has more than one page.
+	PageNumbering(pdfGen generator.Generator, footerStartY float64)
+
+	// PageNumberingCustom prints the page numbers with a custom prefix above the footer.
+	PageNumberingCustom(prefixText string, pdfGen generator.Generator, footerStartY float64, ignoreFirstPage bool)
+
+	// Body sets the cursor to the start of the body and calls bodyGenerationFunc.
+	Body(pdfGen generator.Generator, bodyGenerationFunc func())
+
+	// BodyStopY returns the lowest possible y position of the body, if the footer has footerLines text lines.
+	BodyStopY(footerLines int) float64
+
+	// FoldMarks prints the fold marks and the punch mark on the left side of the current page.
+	FoldMarks(pdfGen generator.Generator)
+
+	// FollowUpHeader prints the compact header of a follow-up page, i.e. each page after the first.
+	FollowUpHeader(pdfGen generator.Generator, header FollowUpHeader)
+}
+
+// Zone is a rectangle area of the page.
+type Zone struct {
+	StartX float64
+	StartY float64
+	StopX  float64
+	StopY  float64
+}
+
+// Zones sums the geometry of a letter page.
+//
+// Header contains the logo.
+//
+// AddressSender contains the small sender line, AddressReceiver the receiver address.
+// Both zones are visible through the envelope window.
+//
+// MetaInfo contains the info block with e.g. the customer number and date.
+//
+// The body is printed between BodyStartX and BodyStopX, starting at BodyStartY on the first page
+// and at NextPageStartY on the following pages.
+// FollowUpHeader contains the compact header of the following pages, above NextPageStartY.
+//
+// MarginPageNumberY defines the gap between the footer and the bottom of the page.
+//
+// FoldMarksY are the y positions of the fold marks, to fold the letter for a window envelope.
+// PunchMarkY is the y position of the punch mark, zero if the norm has no punch mark.
+type Zones struct {
+	PageWidth  float64
+	PageHeight float64
+
+	Header          Zone
+	AddressSender   Zone
+	AddressReceiver Zone
+	MetaInfo        Zone
+
+	BodyStartX     float64
+	BodyStartY     float64
+	BodyStopX      float64
+	NextPageStartY float64
+	FollowUpHeader Zone
+
+	MarginPageNumberY float64
+
+	FoldMarksY []float64
+	PunchMarkY float64
+}
+
+// FitPage returns the zones for a page with the given size, e.g. a landscape page.
+// The body and the header keep their distance to the right page border, all other zones keep their position.
+func (zones Zones) FitPage(pageWidth float64, pageHeight float64) Zones {
+	zones.BodyStopX += pageWidth - zones.PageWidth
+	zones.Header.StopX += pageWidth - zones.PageWidth
+	zones.FollowUpHeader.StopX += pageWidth - zones.PageWidth
+	zones.PageWidth = pageWidth
+	zones.PageHeight = pageHeight
+
+	return zones
+}
+
+// Envelope is a window envelope in landscape orientation.
+// The window position is measured from the left and the top side of the envelope.
+type Envelope struct {
+	Name         string
+	Width        float64
+	Height       float64
+	WindowLeft   float64
+	WindowTop    float64
+	WindowWidth  float64
+	WindowHeight float64
+}
+
+// Envelopes are the common window envelopes with a 90 x 45 mm window on the left side.
+var Envelopes = []Envelope{
+	{Name: "DL", Width: 220, Height: 110, WindowLeft: 20, WindowTop: 50, WindowWidth: 90, WindowHeight: 45},
+	{Name: "C5", Width: 229, Height: 162, WindowLeft: 20, WindowTop: 50, WindowWidth: 90, WindowHeight: 45},
+	{Name: "C6", Width: 162, Height: 114, WindowLeft: 20, WindowTop: 54, WindowWidth: 90, WindowHeight: 45},
+}
+
+// WindowOnPage returns the position of the envelope window on the first page of a letter with zones.
+//
+// The letter is folded until the first panel fits into the envelope:
+// not folded, folded in half or folded at the first fold mark.
+// The first panel lies in the bottom left corner of the envelope.
+func (envelope Envelope) WindowOnPage(zones Zones) Zone {
+	panelHeight := zones.PageHeight
+	if panelHeight > envelope.Height {
+		panelHeight = zones.PageHeight / 2
+	}
+	if panelHeight > envelope.Height && len(zones.FoldMarksY) > 0 {
+		panelHeight = zones.FoldMarksY[0]
+	}
+
+	startY := envelope.WindowTop - (envelope.Height - panelHeight)
+	if startY < 0 {
+		startY = 0
+	}
+
+	return Zone{
+		StartX: envelope.WindowLeft,
+		StartY: startY,
+		StopX:  envelope.WindowLeft + envelope.WindowWidth,
+		StopY:  startY + envelope.WindowHeight,
+	}
+}
+
+type FullAdresse struct {
+	FullForename string `json:"fullForename"`
+	FullSurname  string `json:"fullSurname"`
+	NameTitle    string `json:"nameTitle"`
+	CompanyName  string `json:"companyName"`
+	Address      struct {
+		Road             string `json:"road"`
+		HouseNumber      string `json:"houseNumber"`
+		StreetSupplement string `json:"streetSupplement"`
+		ZipCode          string `json:"zipCode"`
+		CityName         string `json:"cityName"`
+		State            string `json:"state"`
+		Country          string `json:"country"`
+		CountryCode      string `json:"countryCode"`
+	} `json:"address"`
+}
+
+type InfoData struct {
+	Name  string
+	Value string
+}
+
+// FollowUpHeader is the content of the compact header on each page after the first.
+//
+// Title, Sender and Receiver are printed one below the other on the left side, empty values are skipped.
+// Info is printed on the right side, e.g. the document number and the date.
+// Lines that do not fit into the FollowUpHeader zone are skipped.
+type FollowUpHeader struct {
+	Title    string
+	Sender   string
+	Receiver string
+	Info     []InfoData
+}
@@ -1,6 +1,9 @@
 package din5008a
 
-import dinA4 "SimpleInvoice/norms/paperSize/din-a4"
+import (
+	"SimpleInvoice/norms/letter"
+	dinA4 "SimpleInvoice/norms/paperSize/din-a4"
+)
 
 // in mm
 // for DIN A4 paper
@@ -47,20 +50,16 @@ const (
 	BodyStopX  = 190
 
 	MarginPageNumberY = 4.23
+
+	// compact header of the follow-up pages, above the body starting at AddressSenderTextStartY
+	FollowUpHeaderStartY = 10.
+	FollowUpHeaderStopY  = 22.
+
+	// fold marks of Form A and the punch mark in the middle of the page
+	FoldMarkTopY    = 87.
+	FoldMarkBottomY = 192.
+	PunchMarkY      = 148.5
 )
 
-type FullAdresse struct {
-	FullForename string `json:"fullForename"`
-	FullSurname  string `json:"fullSurname"`
-	NameTitle    string `json:"nameTitle"`
-	CompanyName  string `json:"companyName"`
-	Address      struct {
-		Road             string `json:"road"`
-		HouseNumber      string `json:"houseNumber"`
-		StreetSupplement string `json:"streetSupplement"`
-		ZipCode          string `json:"zipCode"`
-		CityName         string `json:"cityName"`
-		Country          string `json:"country"`
-		CountryCode      string `json:"countryCode"`
-	} `json:"address"`
-}
+// FullAdresse is a postal address of the sender or the receiver.
+type FullAdresse = letter.FullAdresse
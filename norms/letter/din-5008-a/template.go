@@ -2,243 +2,80 @@ package din5008a
 
 import (
 	"SimpleInvoice/generator"
-	"fmt"
-	errorsWithStack "github.com/go-errors/errors"
+	"SimpleInvoice/norms/letter"
 	"github.com/rs/zerolog"
-	"net/url"
 )
 
-type InfoData struct {
-	Name  string
-	Value string
-}
-
-func FullAddressesAndInfoPart(pdfGen *generator.PDFGenerator, senderInfo FullAdresse, receiverAddress FullAdresse, data []InfoData) {
-	SenderAdresse(pdfGen, senderInfo)
-	ReceiverAdresse(pdfGen, receiverAddress)
-	MetaInfo(pdfGen, data)
-}
+// InfoData is a name and value of the info block.
+type InfoData = letter.InfoData
 
-func MimeImageHeader(pdfGen *generator.PDFGenerator, strUrl string) {
-	urlStruct, err := url.Parse(strUrl)
-	if err != nil {
-		pdfGen.SetError(errorsWithStack.New(err.Error()))
-		return
-	}
-	const marginRight = Width - MetaInfoStopX
-	const marginTop = 5.
+// zones are the zones of DIN 5008 Form A, see def.go.
+var zones = letter.Zones{
+	PageWidth:  Width,
+	PageHeight: Height,
 
-	const startX = HeaderStopX - marginRight
-	const startY = HeaderStartY + marginTop
-	const maxImageHeight = HeaderStopY - marginTop
+	Header:          letter.Zone{StartX: HeaderStartX, StartY: HeaderStartY, StopX: HeaderStopX, StopY: HeaderStopY},
+	AddressSender:   letter.Zone{StartX: AddressSenderTextStartX, StartY: AddressSenderTextStartY, StopX: AddressSenderTextStopX, StopY: AddressSenderTextStopY},
+	AddressReceiver: letter.Zone{StartX: AddressReceiverTextStartX, StartY: AddressReceiverTextStartY, StopX: AddressReceiverTextStopX, StopY: AddressReceiverTextStopY},
+	MetaInfo:        letter.Zone{StartX: MetaInfoStartX, StartY: MetaInfoStartY, StopX: MetaInfoStopX, StopY: MetaInfoStopY},
 
-	pdfGen.SetUnsafeCursor(startX, startY)
+	BodyStartX:     BodyStartX,
+	BodyStartY:     BodyStartY,
+	BodyStopX:      BodyStopX,
+	NextPageStartY: AddressSenderTextStartY,
+	FollowUpHeader: letter.Zone{StartX: BodyStartX, StartY: FollowUpHeaderStartY, StopX: BodyStopX, StopY: FollowUpHeaderStopY},
 
-	if !pdfGen.ImageIsRegistered(urlStruct.String()) {
-		pdfGen.RegisterMimeImageToPdf(urlStruct)
-	}
+	MarginPageNumberY: MarginPageNumberY,
 
-	_, imgHeight := pdfGen.GetRegisteredImageExtent(urlStruct.String())
-
-	scale := maxImageHeight / imgHeight
-	pdfGen.PlaceRegisteredImageOnPage(urlStruct.String(), "R", scale)
+	FoldMarksY: []float64{FoldMarkTopY, FoldMarkBottomY},
+	PunchMarkY: PunchMarkY,
 }
 
-func MetaInfo(pdfGen *generator.PDFGenerator, data []InfoData) {
-	var maxNameLength = 0.
-	var maxValueLength = 0.
-
-	pdfGen.SetFontSize(FontSize10)
-	pdfGen.SetFontGapY(FontGab10)
-
-	for _, datum := range data {
-		nameLength := pdfGen.ComputeStringLength(datum.Name)
-		if nameLength > maxNameLength {
-			maxNameLength = nameLength
-		}
-
-		valueLength := pdfGen.ComputeStringLength(datum.Value)
-		if valueLength > maxValueLength {
-			maxValueLength = valueLength
-		}
-	}
-
-	//todo check max width
-	//todo check max length
-
-	pdfGen.SetCursor(MetaInfoStartX, MetaInfoStartY)
-	for _, datum := range data {
-		pdfGen.PrintLnPdfText(datum.Name, "", "L")
-	}
-
-	const gapNameValue = 2
-	pdfGen.SetCursor(MetaInfoStartX+maxNameLength+gapNameValue, MetaInfoStartY)
-
-	for _, datum := range data {
-		pdfGen.PrintLnPdfText(datum.Value, "", "L")
-	}
-
-	_, y := pdfGen.GetCursor()
+// Norm implements letter.Norm for DIN 5008 Form A.
+// If the pdf has only one page, no page number is required by DIN 5008 A.
+var Norm = letter.NewTemplate("din-5008-a", zones, letter.PageNumbers{Prefix: "Seite", Of: "von"})
 
-	pdfGen.DrawLine(MetaInfoStartX, MetaInfoStartY, MetaInfoStartX, y-FontGab10)
+func FullAddressesAndInfoPart(pdfGen generator.Generator, senderInfo FullAdresse, receiverAddress FullAdresse, data []InfoData) {
+	Norm.FullAddressesAndInfoPart(pdfGen, senderInfo, receiverAddress, data)
 }
 
-func ReceiverAdresse(pdfGen *generator.PDFGenerator, receiverAddress FullAdresse) {
-	pdfGen.SetCursor(AddressReceiverTextStartX, AddressReceiverTextStartY)
-
-	pdfGen.SetFontSize(FontSize10)
-	pdfGen.SetFontGapY(FontGabSender8)
-
-	if receiverAddress.CompanyName != "" {
-		pdfGen.PrintLnPdfText(receiverAddress.CompanyName, "", "L")
-	}
-
-	if receiverAddress.FullForename != "" || receiverAddress.FullSurname != "" {
-		pdfGen.PrintLnPdfText(fmt.Sprintf("%s %s %s", receiverAddress.NameTitle, receiverAddress.FullForename, receiverAddress.FullSurname), "", "L")
-	}
-
-	pdfGen.PrintLnPdfText(fmt.Sprintf("%s %s", receiverAddress.Address.Road, receiverAddress.Address.HouseNumber), "", "L")
-
-	if receiverAddress.Address.StreetSupplement != "" {
-		pdfGen.PrintLnPdfText(receiverAddress.Address.StreetSupplement, "", "L")
-	}
-
-	pdfGen.PrintLnPdfText(fmt.Sprintf("%s %s", receiverAddress.Address.ZipCode, receiverAddress.Address.CityName), "", "L")
-
-	if receiverAddress.Address.Country != "" {
-		pdfGen.PrintLnPdfText(fmt.Sprintf("%s", receiverAddress.Address.Country), "", "L")
-	}
+func MimeImageHeader(pdfGen generator.Generator, strUrl string, placement letter.ImagePlacement) {
+	Norm.MimeImageHeader(pdfGen, strUrl, placement)
 }
 
-func SenderAdresse(pdfGen *generator.PDFGenerator, senderInfo FullAdresse) {
-
-	var addressSenderCompanySmall = ""
-
-	if senderInfo.CompanyName != "" {
-		addressSenderCompanySmall += fmt.Sprintf("%s", senderInfo.CompanyName)
-
-		if senderInfo.FullForename != "" || senderInfo.FullSurname != "" {
-			addressSenderCompanySmall += ", "
-		}
-	}
-
-	if senderInfo.NameTitle != "" && (senderInfo.FullForename != "" || senderInfo.FullSurname != "") {
-		addressSenderCompanySmall += fmt.Sprintf("%s ", senderInfo.NameTitle)
-	}
-
-	if senderInfo.FullForename != "" {
-		addressSenderCompanySmall += fmt.Sprintf("%s ", senderInfo.FullForename)
-	}
-	if senderInfo.FullSurname != "" {
-		addressSenderCompanySmall += fmt.Sprintf("%s ", senderInfo.FullSurname)
-	}
-
-	var addressSenderRoadSmall = ""
-
-	addressSenderRoadSmall += fmt.Sprintf("%s %s",
-		senderInfo.Address.Road,
-		senderInfo.Address.HouseNumber,
-	)
-
-	if senderInfo.Address.StreetSupplement != "" {
-		addressSenderRoadSmall += fmt.Sprintf(", %s", senderInfo.Address.StreetSupplement)
-	}
-
-	addressSenderRoadSmall += fmt.Sprintf(", %s %s",
-		senderInfo.Address.ZipCode,
-		senderInfo.Address.CityName,
-	)
-
-	if senderInfo.Address.CountryCode != "" {
-		addressSenderRoadSmall += fmt.Sprintf(", %s", senderInfo.Address.CountryCode)
-	}
-
-	pdfGen.SetCursor(AddressSenderTextStartX, AddressSenderTextStopY)
-	pdfGen.PreviousLine(AddressSenderTextStartX)
-
-	pdfGen.SetFontSize(FontSizeSender8)
-	pdfGen.SetFontGapY(FontGabSender8)
-	pdfGen.PrintPdfText(addressSenderCompanySmall, "", "L")
-	pdfGen.PreviousLine(AddressSenderTextStartX)
-	pdfGen.PrintPdfText(addressSenderRoadSmall, "", "L")
-
-	pdfGen.SetFontSize(FontSize10)
-	pdfGen.SetFontGapY(FontGab10)
+func MimeImageFooter(pdfGen generator.Generator, strUrl string, placement letter.ImagePlacement, footerStartY float64) {
+	Norm.MimeImageFooter(pdfGen, strUrl, placement, footerStartY)
 }
 
-func Footer(content func(maxFooterHeight float64) (footerStartY float64), pdfGen *generator.PDFGenerator) (footerStartY float64, err error) {
-	const startAtY = Height - MarginPageNumberY
-
-	footerStartY = Height
-
-	pdfGen.SetFontSize(FontSize10)
-	pdfGen.SetFontGapY(FontGabReceiver8)
-	pdfGen.DrawLine(BodyStartX, startAtY, BodyStopX, startAtY)
-	pdfGen.SetUnsafeCursor(BodyStartX, startAtY)
-
-	footerStartY = content(startAtY)
-	if footerStartY <= BodyStartY || footerStartY > Height {
-		return -1, errorsWithStack.New(fmt.Sprintf("footerStartY %.4f out of range [%.4f, %.4f)", footerStartY, BodyStartY, Width))
-	}
-
-	pdfGen.DrawLine(BodyStartX, footerStartY-1, BodyStopX, footerStartY-1)
-
-	return footerStartY, nil
+func MetaInfo(pdfGen generator.Generator, data []InfoData) {
+	letter.PrintMetaInfo(pdfGen, zones.MetaInfo, data)
 }
 
-func PageNumbering(pdfGen *generator.PDFGenerator, footerStartY float64) {
-	PageNumberingCustom("Seite", pdfGen, footerStartY, true)
+func ReceiverAdresse(pdfGen generator.Generator, receiverAddress FullAdresse, senderCountryCode string) {
+	letter.PrintReceiverAddress(pdfGen, zones.AddressReceiver, letter.ReceiverAddressLines(receiverAddress, senderCountryCode))
 }
 
-func PageNumberingCustom(prefixText string, pdfGen *generator.PDFGenerator, footerStartY float64, ignoreFirstPage bool) {
-	if pdfGen.GetTotalNumber() == 1 && ignoreFirstPage {
-		// if pdf has only one page, no page number is required by DIN 5008 A
-		return
-	}
-
-	pdfGen.SetFontSize(FontSize10)
-	pdfGen.SetFontGapY(0)
-
-	pages := pdfGen.GetTotalNumber()
-
-	for i := 1; i <= pages; i++ {
-		pdfGen.GoToPage(i)
-		pdfGen.SetUnsafeCursor(BodyStopX, footerStartY-MarginPageNumberY)
-		pdfGen.PreviousLine(BodyStopX)
-		text := fmt.Sprintf("%s %d von %d", prefixText, i, pages)
-		pdfGen.PrintPdfText(text, "", "R")
-	}
-
-	pdfGen.SetFontSize(FontSize10)
-	pdfGen.SetFontGapY(FontGab10)
+func SenderAdresse(pdfGen generator.Generator, senderInfo FullAdresse) {
+	letter.PrintSenderAddress(pdfGen, zones.AddressSender, senderInfo)
 }
 
-func Body(pdfGen *generator.PDFGenerator, bodyGenerationFunc func()) {
-	pdfGen.SetCursor(BodyStartX, BodyStartY)
-	pdfGen.SetFontSize(FontSize10)
-	pdfGen.SetFontGapY(FontGab10)
-
-	bodyGenerationFunc()
+func Footer(content func(maxFooterHeight float64) (footerStartY float64), pdfGen generator.Generator) (footerStartY float64, err error) {
+	return Norm.Footer(content, pdfGen)
 }
 
-func ShowDebugFrame(pdfGen *generator.PDFGenerator, logger *zerolog.Logger) {
-	logger.Warn().Msg("show debug frames")
-	pdfGen.DrawLine(HeaderStartX, HeaderStopY, HeaderStopX, HeaderStopY)
-
-	pdfGen.DrawLine(AddressSenderTextStartX, AddressSenderTextStartY, AddressSenderTextStartX, AddressSenderTextStopY)
-	pdfGen.DrawLine(AddressSenderTextStartX, AddressSenderTextStopY, AddressSenderTextStopX, AddressSenderTextStopY)
-	pdfGen.DrawLine(AddressSenderTextStopX, AddressSenderTextStartY, AddressSenderTextStopX, AddressSenderTextStopY)
+func PageNumbering(pdfGen generator.Generator, footerStartY float64) {
+	Norm.PageNumbering(pdfGen, footerStartY)
+}
 
-	pdfGen.DrawLine(AddressReceiverTextStartX, AddressReceiverTextStartY, AddressReceiverTextStartX, AddressReceiverTextStopY)
-	pdfGen.DrawLine(AddressReceiverTextStartX, AddressReceiverTextStopY, AddressReceiverTextStopX, AddressReceiverTextStopY)
-	pdfGen.DrawLine(AddressReceiverTextStopX, AddressReceiverTextStartY, AddressReceiverTextStopX, AddressReceiverTextStopY)
+// PageNumberingCustom prints "<prefixText> <page> von <pages>".
+func PageNumberingCustom(prefixText string, pdfGen generator.Generator, footerStartY float64, ignoreFirstPage bool) {
+	Norm.PageNumberingCustom(prefixText, pdfGen, footerStartY, ignoreFirstPage)
+}
 
-	pdfGen.DrawLine(MetaInfoStartX, MetaInfoStartY, MetaInfoStopX, MetaInfoStartY)
-	pdfGen.DrawLine(MetaInfoStartX, MetaInfoStopY, MetaInfoStopX, MetaInfoStopY)
-	pdfGen.DrawLine(MetaInfoStartX, MetaInfoStartY, MetaInfoStartX, MetaInfoStopY)
-	pdfGen.DrawLine(MetaInfoStopX, MetaInfoStartY, MetaInfoStopX, MetaInfoStopY)
+func Body(pdfGen generator.Generator, bodyGenerationFunc func()) {
+	Norm.Body(pdfGen, bodyGenerationFunc)
+}
 
-	pdfGen.DrawLine(BodyStartX, BodyStartY, BodyStopX, BodyStartY)
-	pdfGen.DrawLine(BodyStartX, BodyStartY, BodyStartX, Height-10)
-	pdfGen.DrawLine(BodyStopX, BodyStartY, BodyStopX, Height-10)
+func ShowDebugFrame(pdfGen generator.Generator, logger *zerolog.Logger) {
+	letter.ShowDebugFrame(pdfGen, zones, logger)
 }
@@ -0,0 +1,65 @@
+package din5008b
+
+import (
+	"SimpleInvoice/norms/letter"
+	din5008a "SimpleInvoice/norms/letter/din-5008-a"
+	dinA4 "SimpleInvoice/norms/paperSize/din-a4"
+)
+
+// in mm
+// for DIN A4 paper
+//
+// Form B has a taller header than Form A (see din5008a), so the address field starts at 45 mm.
+// The font sizes, the horizontal zones and the footer are the same as in Form A.
+const (
+	Width  = dinA4.Width
+	Height = dinA4.Height
+
+	FontSizeSender8   = din5008a.FontSizeSender8
+	FontGabSender8    = din5008a.FontGabSender8
+	FontSizeReceiver8 = din5008a.FontSizeReceiver8
+	FontGabReceiver8  = din5008a.FontGabReceiver8
+
+	FontSize10 = din5008a.FontSize10
+	FontSize11 = din5008a.FontSize11
+	FontSize12 = din5008a.FontSize12
+
+	LineSpacing = din5008a.LineSpacing
+	FontGab10   = din5008a.FontGab10
+	FontGab11   = din5008a.FontGab11
+	FontGab12   = din5008a.FontGab12
+
+	HeaderStartX = 0.
+	HeaderStartY = 0.
+	HeaderStopX  = dinA4.Width
+	HeaderStopY  = 45.
+
+	AddressSenderTextStartX = 25.
+	AddressSenderTextStartY = 45.
+	AddressSenderTextStopX  = 105.
+	AddressSenderTextStopY  = 62.7
+
+	AddressReceiverTextStartX = 25.
+	AddressReceiverTextStartY = 62.7
+	AddressReceiverTextStopX  = 105
+	AddressReceiverTextStopY  = 90.
+
+	MetaInfoStartX = 125.
+	MetaInfoStartY = 50.
+	MetaInfoStopX  = 200.
+	MetaInfoStopY  = 90.
+
+	BodyStartX = 25.
+	BodyStartY = 98.46
+	BodyStopX  = 190
+
+	MarginPageNumberY = din5008a.MarginPageNumberY
+
+	// fold marks of Form B and the punch mark in the middle of the page
+	FoldMarkTopY    = 105.
+	FoldMarkBottomY = 210.
+	PunchMarkY      = 148.5
+)
+
+// FullAdresse is the same address as in Form A.
+type FullAdresse = letter.FullAdresse
@@ -0,0 +1,31 @@
+package din5008b
+
+import (
+	"SimpleInvoice/norms/letter"
+	din5008a "SimpleInvoice/norms/letter/din-5008-a"
+)
+
+// zones are the zones of DIN 5008 Form B, see def.go.
+var zones = letter.Zones{
+	PageWidth:  Width,
+	PageHeight: Height,
+
+	Header:          letter.Zone{StartX: HeaderStartX, StartY: HeaderStartY, StopX: HeaderStopX, StopY: HeaderStopY},
+	AddressSender:   letter.Zone{StartX: AddressSenderTextStartX, StartY: AddressSenderTextStartY, StopX: AddressSenderTextStopX, StopY: AddressSenderTextStopY},
+	AddressReceiver: letter.Zone{StartX: AddressReceiverTextStartX, StartY: AddressReceiverTextStartY, StopX: AddressReceiverTextStopX, StopY: AddressReceiverTextStopY},
+	MetaInfo:        letter.Zone{StartX: MetaInfoStartX, StartY: MetaInfoStartY, StopX: MetaInfoStopX, StopY: MetaInfoStopY},
+
+	BodyStartX:     BodyStartX,
+	BodyStartY:     BodyStartY,
+	BodyStopX:      BodyStopX,
+	NextPageStartY: din5008a.AddressSenderTextStartY,
+	FollowUpHeader: letter.Zone{StartX: BodyStartX, StartY: din5008a.FollowUpHeaderStartY, StopX: BodyStopX, StopY: din5008a.FollowUpHeaderStopY},
+
+	MarginPageNumberY: MarginPageNumberY,
+
+	FoldMarksY: []float64{FoldMarkTopY, FoldMarkBottomY},
+	PunchMarkY: PunchMarkY,
+}
+
+// Norm implements letter.Norm for DIN 5008 Form B, the page numbers are the same as in Form A.
+var Norm = letter.NewTemplate("din-5008-b", zones, letter.PageNumbers{Prefix: "Seite", Of: "von"})
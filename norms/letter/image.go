@@ -0,0 +1,197 @@
+package letter
+
+import (
+	"SimpleInvoice/generator"
+	"fmt"
+	errorsWithStack "github.com/go-errors/errors"
+	"math"
+	"net/url"
+)
+
+// positions of an image, see ImagePlacement
+const (
+	ImagePositionLeft     = "left"
+	ImagePositionCenter   = "center"
+	ImagePositionRight    = "right"
+	ImagePositionAbsolute = "absolute"
+)
+
+// logoMarginTop is the gap between the top of the header zone and the logo in mm.
+const logoMarginTop = 5.
+
+// ImagePlacement defines the position and the size of an image, e.g. the logo in the header zone
+// or a badge in the footer zone. All values are measured in mm.
+//
+// Position is "left", "center" or "right" in the zone, or "absolute" at X and Y from the top left corner of the page.
+// An empty position uses the default of the zone, e.g. a right aligned logo.
+//
+// The image is scaled to fit into MaxWidth and MaxHeight, keeping its aspect ratio.
+// A zero value is replaced by the width or height of the zone. For an absolute position, a zero value does not limit
+// the size, without any limit the image is printed in its original size.
+//
+// The margins are the gaps between the image and the zone borders, they are ignored for an absolute position.
+//
+// If FollowUpPages is true, the image is printed on each follow-up page too.
+type ImagePlacement struct {
+	Position      string  `json:"position"`
+	X             float64 `json:"x"`
+	Y             float64 `json:"y"`
+	MaxWidth      float64 `json:"maxWidth"`
+	MaxHeight     float64 `json:"maxHeight"`
+	MarginTop     float64 `json:"marginTop"`
+	MarginRight   float64 `json:"marginRight"`
+	MarginBottom  float64 `json:"marginBottom"`
+	MarginLeft    float64 `json:"marginLeft"`
+	FollowUpPages bool    `json:"followUpPages"`
+}
+
+// Validate checks the position and that no size or margin is negative.
+func (placement ImagePlacement) Validate() error {
+	switch placement.Position {
+	case "", ImagePositionLeft, ImagePositionCenter, ImagePositionRight, ImagePositionAbsolute:
+	default:
+		return errorsWithStack.New(fmt.Sprintf("the image position \"%s\" must be left, center, right or absolute", placement.Position))
+	}
+
+	for _, value := range []float64{placement.X, placement.Y, placement.MaxWidth, placement.MaxHeight,
+		placement.MarginTop, placement.MarginRight, placement.MarginBottom, placement.MarginLeft} {
+		if value < 0 {
+			return errorsWithStack.New(fmt.Sprintf("the image placement %+v has a negative value", placement))
+		}
+	}
+
+	return nil
+}
+
+// PrintMimeImageHeader prints a logo into the header zone.
+// By default, the right side of the logo is aligned to the right side of the info block or the receiver address,
+// whichever is further right, and the logo is scaled to the height of the header zone.
+// The left side of the zone is the left side of the body.
+//
+// On a follow-up page, the logo is printed centered between the texts of the FollowUpHeader zone,
+// only an absolute position is kept.
+//
+// strUrl is the name of an already registered image or the URL of the image to download.
+func PrintMimeImageHeader(pdfGen generator.Generator, zones Zones, strUrl string, placement ImagePlacement) {
+	zones = zones.FitPage(pdfGen.GetPageSize())
+
+	if pdfGen.GetCurrentPageNumber() > 1 {
+		if placement.Position != ImagePositionAbsolute {
+			placement.Position = ImagePositionCenter
+		}
+		PrintImage(pdfGen, zones.FollowUpHeader, ImagePositionCenter, strUrl, placement)
+		return
+	}
+
+	marginRight := zones.PageWidth - zones.MetaInfo.StopX
+	if zones.AddressReceiver.StopX > zones.MetaInfo.StopX {
+		marginRight = zones.PageWidth - zones.AddressReceiver.StopX
+	}
+
+	zone := Zone{
+		StartX: zones.BodyStartX,
+		StartY: zones.Header.StartY + logoMarginTop,
+		StopX:  zones.Header.StopX - marginRight,
+		StopY:  zones.Header.StopY,
+	}
+	PrintImage(pdfGen, zone, ImagePositionRight, strUrl, placement)
+}
+
+// PrintMimeImageFooter prints an image, e.g. a certification badge, into the footer zone.
+// The zone is the line of the page numbers between the body and the footer, see BodyStopY.
+// By default, the image is aligned to the left side of the body, the page numbers are printed on the right side.
+//
+// footerStartY is the y position of the first footer line, see PrintFooter.
+func PrintMimeImageFooter(pdfGen generator.Generator, zones Zones, strUrl string, placement ImagePlacement, footerStartY float64) {
+	zones = zones.FitPage(pdfGen.GetPageSize())
+
+	zone := Zone{
+		StartX: zones.BodyStartX,
+		StartY: footerStartY - zones.MarginPageNumberY - footerLineHeight - 1,
+		StopX:  zones.BodyStopX,
+		StopY:  footerStartY - 1,
+	}
+	PrintImage(pdfGen, zone, ImagePositionLeft, strUrl, placement)
+}
+
+// PrintImage prints the image strUrl into zone, see ImagePlacement.
+// defaultPosition is used, if the placement has no position.
+//
+// strUrl is the name of an already registered image or the URL of the image to download.
+func PrintImage(pdfGen generator.Generator, zone Zone, defaultPosition string, strUrl string, placement ImagePlacement) {
+	imageNameStr := registerImage(pdfGen, strUrl)
+	if imageNameStr == "" {
+		return
+	}
+	imgWidth, imgHeight := pdfGen.GetRegisteredImageExtent(imageNameStr)
+
+	position := placement.Position
+	if position == "" {
+		position = defaultPosition
+	}
+
+	if position == ImagePositionAbsolute {
+		zone = Zone{StartX: placement.X, StartY: placement.Y}
+	} else {
+		zone.StartX += placement.MarginLeft
+		zone.StartY += placement.MarginTop
+		zone.StopX -= placement.MarginRight
+		zone.StopY -= placement.MarginBottom
+	}
+
+	maxWidth := placement.MaxWidth
+	maxHeight := placement.MaxHeight
+	if position != ImagePositionAbsolute {
+		if maxWidth == 0 {
+			maxWidth = zone.StopX - zone.StartX
+		}
+		if maxHeight == 0 {
+			maxHeight = zone.StopY - zone.StartY
+		}
+
+		// --> validate inputs
+		if maxWidth <= 0 || maxHeight <= 0 {
+			pdfGen.SetError(errorsWithStack.New(fmt.Sprintf("The image \"%s\" does not fit between its margins.", strUrl)))
+			return
+		}
+		// <--
+	}
+
+	scale := 1.
+	switch {
+	case maxWidth > 0 && maxHeight > 0:
+		scale = math.Min(maxWidth/imgWidth, maxHeight/imgHeight)
+	case maxWidth > 0:
+		scale = maxWidth / imgWidth
+	case maxHeight > 0:
+		scale = maxHeight / imgHeight
+	}
+
+	switch position {
+	case ImagePositionLeft, ImagePositionAbsolute:
+		pdfGen.SetUnsafeCursor(zone.StartX, zone.StartY)
+		pdfGen.PlaceRegisteredImageOnPage(imageNameStr, "L", scale)
+	case ImagePositionCenter:
+		pdfGen.SetUnsafeCursor((zone.StartX+zone.StopX)/2, zone.StartY)
+		pdfGen.PlaceRegisteredImageOnPage(imageNameStr, "C", scale)
+	default:
+		pdfGen.SetUnsafeCursor(zone.StopX, zone.StartY)
+		pdfGen.PlaceRegisteredImageOnPage(imageNameStr, "R", scale)
+	}
+}
+
+// registerImage returns the image name of strUrl and downloads the image, if it is not registered yet.
+// It returns an empty name, if the image can't be registered.
+func registerImage(pdfGen generator.Generator, strUrl string) (imageNameStr string) {
+	if pdfGen.ImageIsRegistered(strUrl) {
+		return strUrl
+	}
+
+	urlStruct, err := url.Parse(strUrl)
+	if err != nil {
+		pdfGen.SetError(errorsWithStack.New(err.Error()))
+		return ""
+	}
+
+	return pdfGen.RegisterMimeImageToPdf(urlStruct)
+}
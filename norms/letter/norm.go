@@ -0,0 +1,85 @@
+package letter
+
+import (
+	"SimpleInvoice/generator"
+	"fmt"
+)
+
+// PageNumbers is the wording of the page numbers "<Prefix> <page> <Of> <pages>", e.g. "Seite 1 von 2".
+type PageNumbers struct {
+	Prefix string
+	Of     string
+}
+
+// Template implements Norm with the print functions of this package.
+// The letter norms differ only in their zones and the wording of the page numbers, see NewTemplate.
+type Template struct {
+	name        string
+	zones       Zones
+	pageNumbers PageNumbers
+}
+
+var _ Norm = Template{}
+
+// NewTemplate returns the letter norm name with the geometry zones and the wording of the page numbers.
+func NewTemplate(name string, zones Zones, pageNumbers PageNumbers) Template {
+	return Template{name: name, zones: zones, pageNumbers: pageNumbers}
+}
+
+func (t Template) Name() string {
+	return t.name
+}
+
+func (t Template) Zones() Zones {
+	return t.zones
+}
+
+func (t Template) PageNumberPrefix() string {
+	return t.pageNumbers.Prefix
+}
+
+func (t Template) FullAddressesAndInfoPart(pdfGen generator.Generator, senderAddress FullAdresse, receiverAddress FullAdresse, data []InfoData) {
+	PrintSenderAddress(pdfGen, t.zones.AddressSender, senderAddress)
+	PrintReceiverAddress(pdfGen, t.zones.AddressReceiver, ReceiverAddressLines(receiverAddress, senderAddress.Address.CountryCode))
+	PrintMetaInfo(pdfGen, t.zones.MetaInfo, data)
+}
+
+func (t Template) MimeImageHeader(pdfGen generator.Generator, strUrl string, placement ImagePlacement) {
+	PrintMimeImageHeader(pdfGen, t.zones, strUrl, placement)
+}
+
+func (t Template) MimeImageFooter(pdfGen generator.Generator, strUrl string, placement ImagePlacement, footerStartY float64) {
+	PrintMimeImageFooter(pdfGen, t.zones, strUrl, placement, footerStartY)
+}
+
+func (t Template) Footer(content func(maxFooterHeight float64) (footerStartY float64), pdfGen generator.Generator) (footerStartY float64, err error) {
+	return PrintFooter(pdfGen, t.zones, content)
+}
+
+// PageNumbering prints the page numbers with the prefix of the norm, a letter with one page has no page number.
+func (t Template) PageNumbering(pdfGen generator.Generator, footerStartY float64) {
+	t.PageNumberingCustom(t.pageNumbers.Prefix, pdfGen, footerStartY, true)
+}
+
+// PageNumberingCustom prints "<prefixText> <page> <Of> <pages>", e.g. "Seite 1 von 2".
+func (t Template) PageNumberingCustom(prefixText string, pdfGen generator.Generator, footerStartY float64, ignoreFirstPage bool) {
+	PrintPageNumbers(pdfGen, t.zones, func(page int, pages int) string {
+		return fmt.Sprintf("%s %d %s %d", prefixText, page, t.pageNumbers.Of, pages)
+	}, footerStartY, ignoreFirstPage)
+}
+
+func (t Template) Body(pdfGen generator.Generator, bodyGenerationFunc func()) {
+	StartBody(pdfGen, t.zones, bodyGenerationFunc)
+}
+
+func (t Template) BodyStopY(footerLines int) float64 {
+	return BodyStopY(t.zones, footerLines)
+}
+
+func (t Template) FoldMarks(pdfGen generator.Generator) {
+	PrintFoldMarks(pdfGen, t.zones)
+}
+
+func (t Template) FollowUpHeader(pdfGen generator.Generator, header FollowUpHeader) {
+	PrintFollowUpHeader(pdfGen, t.zones, header)
+}
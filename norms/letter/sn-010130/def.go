@@ -0,0 +1,49 @@
+package sn010130
+
+import dinA4 "SimpleInvoice/norms/paperSize/din-a4"
+
+// in mm
+// for DIN A4 paper
+//
+// The Swiss SN 010130 letter has the address window on the right side,
+// so the info block moves to the left side under the header.
+const (
+	Width  = dinA4.Width
+	Height = dinA4.Height
+
+	HeaderStartX = 0.
+	HeaderStartY = 0.
+	HeaderStopX  = dinA4.Width
+	HeaderStopY  = 40.
+
+	AddressSenderTextStartX = 118.
+	AddressSenderTextStartY = 40.
+	AddressSenderTextStopX  = 200.
+	AddressSenderTextStopY  = 50.
+
+	AddressReceiverTextStartX = 118.
+	AddressReceiverTextStartY = 50.
+	AddressReceiverTextStopX  = 200.
+	AddressReceiverTextStopY  = 90.
+
+	MetaInfoStartX = 22.
+	MetaInfoStartY = 50.
+	MetaInfoStopX  = 105.
+	MetaInfoStopY  = 90.
+
+	BodyStartX     = 22.
+	BodyStartY     = 100.
+	BodyStopX      = 188.
+	NextPageStartY = 25.
+
+	MarginPageNumberY = 4.23
+
+	// compact header of the follow-up pages, above NextPageStartY
+	FollowUpHeaderStartY = 10.
+	FollowUpHeaderStopY  = 21.
+
+	// fold marks to fold the letter in three equal parts
+	FoldMarkTopY    = 99.
+	FoldMarkBottomY = 198.
+	PunchMarkY      = 148.5
+)
@@ -0,0 +1,372 @@
+package letter
+
+import (
+	"SimpleInvoice/generator"
+	"fmt"
+	errorsWithStack "github.com/go-errors/errors"
+	"github.com/rs/zerolog"
+	"math"
+	"strings"
+)
+
+// font sizes in pt and gaps in mm used by the shared letter parts
+const (
+	FontSizeDefault = 10.
+	FontGapDefault  = 3.
+	FontSizeSmall   = 8.
+	FontGapSmall    = 0.5
+	FontGapFooter   = 1.
+)
+
+// metaInfoGapNameValue is the gap between the name and the value column of the info block in mm.
+const metaInfoGapNameValue = 2.
+
+// the size of the barcode below the info block in mm, see PrintMetaInfoBarcode
+const (
+	metaInfoBarcodeHeight    = 15.
+	metaInfoBarcodeMinHeight = 8.
+	metaInfoBarcodeMaxWidth  = 60.
+)
+
+// PrintSenderAddress prints the small sender lines (see SenderAddressLines) at the bottom of the sender zone.
+// Lines wider than the zone are printed with a smaller font, see Check.
+func PrintSenderAddress(pdfGen generator.Generator, zone Zone, senderAddress FullAdresse) {
+	companyLine, roadLine := SenderAddressLines(senderAddress)
+
+	pdfGen.SetCursor(zone.StartX, zone.StopY)
+	pdfGen.PreviousLine(zone.StartX)
+
+	pdfGen.SetFontSize(senderFontSize(pdfGen, zone, senderAddress))
+	pdfGen.SetFontGapY(FontGapSmall)
+	pdfGen.PrintPdfText(companyLine, "", "L")
+	pdfGen.PreviousLine(zone.StartX)
+	pdfGen.PrintPdfText(roadLine, "", "L")
+
+	pdfGen.SetFontSize(FontSizeDefault)
+	pdfGen.SetFontGapY(FontGapDefault)
+}
+
+// PrintReceiverAddress prints the address lines from the top of the receiver zone.
+// Lines below the zone are not printed, see Check.
+func PrintReceiverAddress(pdfGen generator.Generator, zone Zone, lines []string) {
+	pdfGen.SetCursor(zone.StartX, zone.StartY)
+
+	pdfGen.SetFontSize(FontSizeDefault)
+	pdfGen.SetFontGapY(FontGapSmall)
+
+	if maxLines := zoneLines(pdfGen, zone, FontSizeDefault, FontGapSmall); len(lines) > maxLines {
+		lines = lines[:maxLines]
+	}
+
+	for _, line := range lines {
+		pdfGen.PrintLnPdfText(line, "", "L")
+	}
+}
+
+// PrintMetaInfo prints the names and values of the info block in two columns with a line on the left side.
+// Rows below the zone are not printed, see Check.
+func PrintMetaInfo(pdfGen generator.Generator, zone Zone, data []InfoData) {
+	var maxNameLength = 0.
+
+	pdfGen.SetFontSize(FontSizeDefault)
+	pdfGen.SetFontGapY(FontGapDefault)
+
+	if maxLines := zoneLines(pdfGen, zone, FontSizeDefault, FontGapDefault); len(data) > maxLines {
+		data = data[:maxLines]
+	}
+
+	for _, datum := range data {
+		nameLength := pdfGen.ComputeStringLength(datum.Name)
+		if nameLength > maxNameLength {
+			maxNameLength = nameLength
+		}
+	}
+
+	pdfGen.SetCursor(zone.StartX, zone.StartY)
+	for _, datum := range data {
+		pdfGen.PrintLnPdfText(datum.Name, "", "L")
+	}
+
+	pdfGen.SetCursor(zone.StartX+maxNameLength+metaInfoGapNameValue, zone.StartY)
+
+	for _, datum := range data {
+		pdfGen.PrintLnPdfText(datum.Value, "", "L")
+	}
+
+	_, y := pdfGen.GetCursor()
+
+	drawLine(pdfGen, zone.StartX, zone.StartY, zone.StartX, y-FontGapDefault)
+}
+
+// PrintMetaInfoBarcode prints content as Code 128 barcode with its human-readable text below the rows of the info block,
+// e.g. the delivery note number to scan the delivery at the goods receipt.
+//
+// rows is the number of rows printed with PrintMetaInfo.
+func PrintMetaInfoBarcode(pdfGen generator.Generator, zones Zones, rows int, content string) {
+	zone := zones.FitPage(pdfGen.GetPageSize()).MetaInfo
+
+	y := zone.StartY + float64(rows)*pdfGen.GetLineHeight(FontSizeDefault, FontGapDefault)
+	height := math.Min(metaInfoBarcodeHeight, zone.StopY-y)
+
+	// --> validate inputs
+	if height < metaInfoBarcodeMinHeight {
+		pdfGen.SetError(errorsWithStack.New(fmt.Sprintf("The barcode \"%s\" does not fit below the %d rows of the info block.", content, rows)))
+		return
+	}
+	// <--
+
+	pdfGen.SetFontSize(FontSizeSmall)
+	pdfGen.DrawBarcode(generator.BarcodeCode128, content, zone.StartX, y, math.Min(metaInfoBarcodeMaxWidth, zone.StopX-zone.StartX), height, true)
+	pdfGen.SetFontSize(FontSizeDefault)
+}
+
+// PrintFooter prints a line at the bottom of the page, the content above and a line above the content.
+func PrintFooter(pdfGen generator.Generator, zones Zones, content func(maxFooterHeight float64) (footerStartY float64)) (footerStartY float64, err error) {
+	zones = zones.FitPage(pdfGen.GetPageSize())
+	startAtY := zones.PageHeight - zones.MarginPageNumberY
+
+	pdfGen.SetFontSize(FontSizeDefault)
+	pdfGen.SetFontGapY(FontGapFooter)
+	drawLine(pdfGen, zones.BodyStartX, startAtY, zones.BodyStopX, startAtY)
+	pdfGen.SetUnsafeCursor(zones.BodyStartX, startAtY)
+
+	footerStartY = content(startAtY)
+	if footerStartY <= zones.BodyStartY || footerStartY > zones.PageHeight {
+		return -1, errorsWithStack.New(fmt.Sprintf("footerStartY %.4f out of range [%.4f, %.4f)", footerStartY, zones.BodyStartY, zones.PageHeight))
+	}
+
+	drawLine(pdfGen, zones.BodyStartX, footerStartY-1, zones.BodyStopX, footerStartY-1)
+
+	return footerStartY, nil
+}
+
+// PrintPageNumbers prints pageText right aligned above the footer of each page.
+func PrintPageNumbers(pdfGen generator.Generator, zones Zones, pageText func(page int, pages int) string, footerStartY float64, ignoreFirstPage bool) {
+	if pdfGen.GetTotalNumber() == 1 && ignoreFirstPage {
+		// if pdf has only one page, no page number is required
+		return
+	}
+
+	zones = zones.FitPage(pdfGen.GetPageSize())
+
+	pdfGen.SetFontSize(FontSizeDefault)
+	pdfGen.SetFontGapY(0)
+
+	pages := pdfGen.GetTotalNumber()
+
+	for i := 1; i <= pages; i++ {
+		pdfGen.GoToPage(i)
+		pdfGen.SetUnsafeCursor(zones.BodyStopX, footerStartY-zones.MarginPageNumberY)
+		pdfGen.PreviousLine(zones.BodyStopX)
+		pdfGen.PrintPdfText(pageText(i, pages), "", "R")
+	}
+
+	pdfGen.SetFontSize(FontSizeDefault)
+	pdfGen.SetFontGapY(FontGapDefault)
+}
+
+// StartBody sets the cursor to the start of the body and calls bodyGenerationFunc.
+func StartBody(pdfGen generator.Generator, zones Zones, bodyGenerationFunc func()) {
+	pdfGen.SetCursor(zones.BodyStartX, zones.BodyStartY)
+	pdfGen.SetFontSize(FontSizeDefault)
+	pdfGen.SetFontGapY(FontGapDefault)
+
+	bodyGenerationFunc()
+}
+
+// BodyStopY returns the lowest possible y position of the body,
+// if the footer content (see PrintFooter) has footerLines text lines.
+// Below, the page number and the footer are printed.
+func BodyStopY(zones Zones, footerLines int) float64 {
+	return FooterStartY(zones, footerLines) - zones.MarginPageNumberY - footerLineHeight - 1
+}
+
+// FooterStartY returns the y position of the first footer line,
+// if the footer content (see PrintFooter) has footerLines text lines.
+// Use it to place the page numbers, if the footer is not printed, e.g. because it is part of a letterhead.
+func FooterStartY(zones Zones, footerLines int) float64 {
+	return zones.PageHeight - zones.MarginPageNumberY - float64(footerLines)*(footerLineHeight+FontGapFooter)
+}
+
+// footerLineHeight is the height of a footer line in mm without gap.
+const footerLineHeight = FontSizeDefault * 25.4 / 72.
+
+// drawLine draws a line in the line color and the line width of pdfGen, see generator.MetaData.DefaultLineColor.
+func drawLine(pdfGen generator.Generator, x1 float64, y1 float64, x2 float64, y2 float64) {
+	pdfGen.DrawLine(x1, y1, x2, y2, pdfGen.GetLineColor(), pdfGen.GetLineWidth())
+}
+
+// ShowDebugFrame draws the border of each zone.
+func ShowDebugFrame(pdfGen generator.Generator, zones Zones, logger *zerolog.Logger) {
+	logger.Warn().Msg("show debug frames")
+	zones = zones.FitPage(pdfGen.GetPageSize())
+
+	drawLine(pdfGen, zones.Header.StartX, zones.Header.StopY, zones.Header.StopX, zones.Header.StopY)
+
+	for _, zone := range []Zone{zones.AddressSender, zones.AddressReceiver} {
+		drawLine(pdfGen, zone.StartX, zone.StartY, zone.StartX, zone.StopY)
+		drawLine(pdfGen, zone.StartX, zone.StopY, zone.StopX, zone.StopY)
+		drawLine(pdfGen, zone.StopX, zone.StartY, zone.StopX, zone.StopY)
+	}
+
+	drawLine(pdfGen, zones.MetaInfo.StartX, zones.MetaInfo.StartY, zones.MetaInfo.StopX, zones.MetaInfo.StartY)
+	drawLine(pdfGen, zones.MetaInfo.StartX, zones.MetaInfo.StopY, zones.MetaInfo.StopX, zones.MetaInfo.StopY)
+	drawLine(pdfGen, zones.MetaInfo.StartX, zones.MetaInfo.StartY, zones.MetaInfo.StartX, zones.MetaInfo.StopY)
+	drawLine(pdfGen, zones.MetaInfo.StopX, zones.MetaInfo.StartY, zones.MetaInfo.StopX, zones.MetaInfo.StopY)
+
+	drawLine(pdfGen, zones.BodyStartX, zones.BodyStartY, zones.BodyStopX, zones.BodyStartY)
+	drawLine(pdfGen, zones.BodyStartX, zones.BodyStartY, zones.BodyStartX, zones.PageHeight-10)
+	drawLine(pdfGen, zones.BodyStopX, zones.BodyStartY, zones.BodyStopX, zones.PageHeight-10)
+}
+
+// PrintFoldMarks prints short lines at the left side of the current page for each fold mark and a longer line for the punch mark.
+func PrintFoldMarks(pdfGen generator.Generator, zones Zones) {
+	const markStartX = 3.
+	const foldMarkLength = 5.
+	const punchMarkLength = 8.
+
+	for _, y := range zones.FoldMarksY {
+		drawLine(pdfGen, markStartX, y, markStartX+foldMarkLength, y)
+	}
+
+	if zones.PunchMarkY > 0 {
+		drawLine(pdfGen, markStartX, zones.PunchMarkY, markStartX+punchMarkLength, zones.PunchMarkY)
+	}
+}
+
+// ShowEnvelopeWindows draws the window of each envelope (see Envelopes) on the current page,
+// to check the placement of the address.
+func ShowEnvelopeWindows(pdfGen generator.Generator, zones Zones) {
+	fontSize := pdfGen.GetFontSize()
+	pdfGen.SetFontSize(6)
+
+	for i, envelope := range Envelopes {
+		window := envelope.WindowOnPage(zones)
+
+		drawLine(pdfGen, window.StartX, window.StartY, window.StopX, window.StartY)
+		drawLine(pdfGen, window.StartX, window.StopY, window.StopX, window.StopY)
+		drawLine(pdfGen, window.StartX, window.StartY, window.StartX, window.StopY)
+		drawLine(pdfGen, window.StopX, window.StartY, window.StopX, window.StopY)
+
+		// the windows of different envelopes can be at the same position
+		pdfGen.SetUnsafeCursor(window.StopX+1, window.StartY+float64(i)*3)
+		pdfGen.PrintPdfText(envelope.Name, "", "L")
+	}
+
+	pdfGen.SetFontSize(fontSize)
+}
+
+// PrintFollowUpHeader prints the compact header of a follow-up page into the FollowUpHeader zone
+// and a line below the header. The title is printed bold.
+func PrintFollowUpHeader(pdfGen generator.Generator, zones Zones, header FollowUpHeader) {
+	zones = zones.FitPage(pdfGen.GetPageSize())
+	zone := zones.FollowUpHeader
+
+	fontSize := pdfGen.GetFontSize()
+	fontGapY := pdfGen.GetFontGapY()
+	pdfGen.SetFontSize(FontSizeSmall)
+	pdfGen.SetFontGapY(FontGapSmall)
+
+	// lines that do not fit into the zone are skipped
+	maxLines := zoneLines(pdfGen, zone, FontSizeSmall, FontGapSmall)
+
+	pdfGen.SetUnsafeCursor(zone.StartX, zone.StartY)
+	lines := 0
+	for i, line := range []string{header.Title, header.Sender, header.Receiver} {
+		if line == "" || lines == maxLines {
+			continue
+		}
+		styleStr := ""
+		if i == 0 {
+			styleStr = "b"
+		}
+		pdfGen.PrintLnPdfText(line, styleStr, "L")
+		lines++
+	}
+
+	pdfGen.SetUnsafeCursor(zone.StopX, zone.StartY)
+	for i, datum := range header.Info {
+		if i == maxLines {
+			break
+		}
+		pdfGen.PrintLnPdfText(strings.TrimSpace(datum.Name+" "+datum.Value), "", "R")
+	}
+
+	drawLine(pdfGen, zone.StartX, zone.StopY, zone.StopX, zone.StopY)
+
+	pdfGen.SetFontSize(fontSize)
+	pdfGen.SetFontGapY(fontGapY)
+}
+
+// ShortName returns the company name of address, or the name of the person, if no company is set.
+func ShortName(address FullAdresse) string {
+	if address.CompanyName != "" {
+		return address.CompanyName
+	}
+
+	return strings.TrimSpace(fmt.Sprintf("%s %s", address.FullForename, address.FullSurname))
+}
+
+// ReceiverAddressLines returns the lines of the receiver address as printed in the address field,
+// formatted for the country of the receiver (see PostalLines).
+//
+// International mail ends with the country in capitals. Domestic mail, with the same country code
+// as senderCountryCode, has no country line. If a country code is unknown, the country is printed as given.
+func ReceiverAddressLines(receiverAddress FullAdresse, senderCountryCode string) (lines []string) {
+	if receiverAddress.CompanyName != "" {
+		lines = append(lines, receiverAddress.CompanyName)
+	}
+
+	if receiverAddress.FullForename != "" || receiverAddress.FullSurname != "" {
+		lines = append(lines, strings.TrimSpace(fmt.Sprintf("%s %s %s", receiverAddress.NameTitle, receiverAddress.FullForename, receiverAddress.FullSurname)))
+	}
+
+	international := IsInternational(receiverAddress, senderCountryCode)
+	lines = append(lines, PostalLines(receiverAddress, international)...)
+
+	country := receiverAddress.Address.Country
+	switch {
+	case international && country == "":
+		lines = append(lines, strings.ToUpper(receiverAddress.Address.CountryCode))
+	case international:
+		lines = append(lines, strings.ToUpper(country))
+	case country != "" && (receiverAddress.Address.CountryCode == "" || senderCountryCode == ""):
+		lines = append(lines, country)
+	}
+
+	return lines
+}
+
+// SenderAddressLines returns the two lines of the small sender address above the receiver address.
+// The road line is formatted for the country of the sender, see PostalLines.
+func SenderAddressLines(senderInfo FullAdresse) (companyLine string, roadLine string) {
+	var addressSenderCompanySmall = ""
+
+	if senderInfo.CompanyName != "" {
+		addressSenderCompanySmall += fmt.Sprintf("%s", senderInfo.CompanyName)
+
+		if senderInfo.FullForename != "" || senderInfo.FullSurname != "" {
+			addressSenderCompanySmall += ", "
+		}
+	}
+
+	if senderInfo.NameTitle != "" && (senderInfo.FullForename != "" || senderInfo.FullSurname != "") {
+		addressSenderCompanySmall += fmt.Sprintf("%s ", senderInfo.NameTitle)
+	}
+
+	if senderInfo.FullForename != "" {
+		addressSenderCompanySmall += fmt.Sprintf("%s ", senderInfo.FullForename)
+	}
+	if senderInfo.FullSurname != "" {
+		addressSenderCompanySmall += fmt.Sprintf("%s ", senderInfo.FullSurname)
+	}
+
+	// the postal lines in the format of the sender country, e.g. "Musterstraße 42, 01234 Musterstadt"
+	var addressSenderRoadSmall = strings.Join(PostalLines(senderInfo, false), ", ")
+
+	if senderInfo.Address.CountryCode != "" {
+		addressSenderRoadSmall += fmt.Sprintf(", %s", senderInfo.Address.CountryCode)
+	}
+
+	return addressSenderCompanySmall, addressSenderRoadSmall
+}
@@ -0,0 +1,116 @@
+package letter
+
+import (
+	"SimpleInvoice/generator"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestPrintSenderAddress(t *testing.T) {
+	tests := []struct {
+		name         string
+		senderName   string
+		wantFontSize float64
+	}{
+		{name: "fits", senderName: "Musterfirma GbR", wantFontSize: FontSizeSmall},
+		{name: "shrunk", senderName: strings.Repeat("M", 60), wantFontSize: 7.5},
+		{name: "smallest font", senderName: strings.Repeat("M", 100), wantFontSize: FontSizeSenderMin},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, err := generator.NewRecordingGenerator(_testMetaData, false, &_logger, func() {}, func(isLastPage bool) {})
+			if err != nil {
+				t.Fatalf("init recorder error\n%s", err.Error())
+			}
+			rec.NewPage()
+
+			PrintSenderAddress(rec, _testZones.AddressSender, testAddress(tt.senderName))
+
+			operations := rec.OperationsByName("PrintPdfText")
+			if len(operations) != 2 {
+				t.Fatalf("PrintSenderAddress() printed %d lines, want 2", len(operations))
+			}
+			for _, op := range operations {
+				if op.FontSize != tt.wantFontSize {
+					t.Errorf("PrintSenderAddress() printed %q with %v pt, want %v pt", op.Text, op.FontSize, tt.wantFontSize)
+				}
+			}
+			if rec.GetFontSize() != FontSizeDefault {
+				t.Errorf("PrintSenderAddress() does not restore the font size, got %v", rec.GetFontSize())
+			}
+		})
+	}
+}
+
+func TestPrintReceiverAddress(t *testing.T) {
+	tests := []struct {
+		name      string
+		lines     int
+		wantLines int
+	}{
+		{name: "fits", lines: 4, wantLines: 4},
+		{name: "zone is full", lines: 6, wantLines: 6},
+		{name: "more than 9 lines are truncated", lines: 10, wantLines: 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, err := generator.NewRecordingGenerator(_testMetaData, false, &_logger, func() {}, func(isLastPage bool) {})
+			if err != nil {
+				t.Fatalf("init recorder error\n%s", err.Error())
+			}
+			rec.NewPage()
+
+			var lines []string
+			for i := 0; i < tt.lines; i++ {
+				lines = append(lines, fmt.Sprintf("line %d", i+1))
+			}
+			PrintReceiverAddress(rec, _testZones.AddressReceiver, lines)
+
+			operations := rec.OperationsByName("PrintPdfText")
+			if len(operations) != tt.wantLines {
+				t.Fatalf("PrintReceiverAddress() printed %d lines, want %d", len(operations), tt.wantLines)
+			}
+			for i, op := range operations {
+				if op.Text != lines[i] {
+					t.Errorf("PrintReceiverAddress() printed %q as line %d, want %q", op.Text, i+1, lines[i])
+				}
+				if op.Y < _testZones.AddressReceiver.StartY || op.Y+rec.GetLineHeight(FontSizeDefault, 0) > _testZones.AddressReceiver.StopY {
+					t.Errorf("PrintReceiverAddress() printed %q at %.2f outside of the zone", op.Text, op.Y)
+				}
+			}
+		})
+	}
+}
+
+func TestPrintMetaInfo(t *testing.T) {
+	tests := []struct {
+		name     string
+		rows     int
+		wantRows int
+	}{
+		{name: "fits", rows: 4, wantRows: 4},
+		{name: "overflowing rows are truncated", rows: 11, wantRows: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, err := generator.NewRecordingGenerator(_testMetaData, false, &_logger, func() {}, func(isLastPage bool) {})
+			if err != nil {
+				t.Fatalf("init recorder error\n%s", err.Error())
+			}
+			rec.NewPage()
+
+			PrintMetaInfo(rec, _testZones.MetaInfo, testInfoData(tt.rows))
+
+			// each row is printed as name and value
+			if got := len(rec.OperationsByName("PrintPdfText")); got != 2*tt.wantRows {
+				t.Errorf("PrintMetaInfo() printed %d texts, want %d", got, 2*tt.wantRows)
+			}
+			for _, op := range rec.OperationsByName("DrawLine") {
+				if op.Values[3] > _testZones.MetaInfo.StopY {
+					t.Errorf("PrintMetaInfo() draws the line to %.2f below the zone", op.Values[3])
+				}
+			}
+		})
+	}
+}
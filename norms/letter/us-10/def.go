@@ -0,0 +1,49 @@
+package us10
+
+import usLetter "SimpleInvoice/norms/paperSize/us-letter"
+
+// in mm
+// for US Letter paper, folded in three parts for a #10 window envelope
+//
+// The window of a #10 envelope is 4.5 x 1.125 inch, 7/8 inch from the left side.
+// The address text starts 1 inch from the left side, inside the window.
+const (
+	Width  = usLetter.Width
+	Height = usLetter.Height
+
+	HeaderStartX = 0.
+	HeaderStartY = 0.
+	HeaderStopX  = usLetter.Width
+	HeaderStopY  = 38.1
+
+	AddressSenderTextStartX = 25.4
+	AddressSenderTextStartY = 38.1
+	AddressSenderTextStopX  = 136.5
+	AddressSenderTextStopY  = 50.8
+
+	AddressReceiverTextStartX = 25.4
+	AddressReceiverTextStartY = 50.8
+	AddressReceiverTextStopX  = 136.5
+	AddressReceiverTextStopY  = 79.4
+
+	MetaInfoStartX = 140.
+	MetaInfoStartY = 50.8
+	MetaInfoStopX  = 195.9
+	MetaInfoStopY  = 95.
+
+	BodyStartX     = 25.4
+	BodyStartY     = 101.6
+	BodyStopX      = 190.5
+	NextPageStartY = 25.4
+
+	MarginPageNumberY = 4.23
+
+	// compact header of the follow-up pages, above NextPageStartY
+	FollowUpHeaderStartY = 10.
+	FollowUpHeaderStopY  = 21.
+
+	// fold marks to fold the letter in three equal parts, US letters have no punch mark
+	FoldMarkTopY    = 93.1
+	FoldMarkBottomY = 186.3
+	PunchMarkY      = 0.
+)
@@ -0,0 +1,30 @@
+package us10
+
+import (
+	"SimpleInvoice/norms/letter"
+)
+
+// zones are the zones of the US business letter, see def.go.
+var zones = letter.Zones{
+	PageWidth:  Width,
+	PageHeight: Height,
+
+	Header:          letter.Zone{StartX: HeaderStartX, StartY: HeaderStartY, StopX: HeaderStopX, StopY: HeaderStopY},
+	AddressSender:   letter.Zone{StartX: AddressSenderTextStartX, StartY: AddressSenderTextStartY, StopX: AddressSenderTextStopX, StopY: AddressSenderTextStopY},
+	AddressReceiver: letter.Zone{StartX: AddressReceiverTextStartX, StartY: AddressReceiverTextStartY, StopX: AddressReceiverTextStopX, StopY: AddressReceiverTextStopY},
+	MetaInfo:        letter.Zone{StartX: MetaInfoStartX, StartY: MetaInfoStartY, StopX: MetaInfoStopX, StopY: MetaInfoStopY},
+
+	BodyStartX:     BodyStartX,
+	BodyStartY:     BodyStartY,
+	BodyStopX:      BodyStopX,
+	NextPageStartY: NextPageStartY,
+	FollowUpHeader: letter.Zone{StartX: BodyStartX, StartY: FollowUpHeaderStartY, StopX: BodyStopX, StopY: FollowUpHeaderStopY},
+
+	MarginPageNumberY: MarginPageNumberY,
+
+	FoldMarksY: []float64{FoldMarkTopY, FoldMarkBottomY},
+	PunchMarkY: PunchMarkY,
+}
+
+// Norm implements letter.Norm for the US business letter with a #10 window envelope.
+var Norm = letter.NewTemplate("us-10", zones, letter.PageNumbers{Prefix: "Page", Of: "of"})
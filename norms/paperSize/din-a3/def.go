@@ -0,0 +1,6 @@
+package dinA3
+
+const (
+	Width  = 297.
+	Height = 420.
+)
@@ -0,0 +1,6 @@
+package dinA5
+
+const (
+	Width  = 148.
+	Height = 210.
+)
@@ -0,0 +1,8 @@
+package usLegal
+
+// in mm
+// US Legal is 8.5 x 14 inch
+const (
+	Width  = 215.9
+	Height = 355.6
+)
@@ -0,0 +1,8 @@
+package usLetter
+
+// in mm
+// US Letter is 8.5 x 11 inch
+const (
+	Width  = 215.9
+	Height = 279.4
+)
@@ -0,0 +1,156 @@
+package pdfType
+
+import (
+	"SimpleInvoice/generator"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// positions of a chart relative to the table, see tableChart
+const (
+	chartBeforeTable = "before"
+	chartAfterTable  = "after"
+)
+
+// defaultChartHeight is the height in mm of a chart without height, including its title and legend.
+const defaultChartHeight = 70
+
+// tableChart is a bar, stacked bar, line or pie chart of some table columns,
+// e.g. the hours per project of a time-tracking report.
+//
+// Kind is "bar", "stackedBar", "line" or "pie" (see generator.ChartBox).
+// The cells of LabelColumn are the labels, each of the ValueColumns is a series named by its table header.
+// Columns are counted from 0. A pie chart has exactly one value column.
+//
+// DecimalSeparator of the value cells is "," (e.g. "1.234,5") or "." (e.g. "1,234.5").
+// If it is empty, the separator is guessed for each cell, see parseChartValue.
+//
+// Position is "before" or "after" (default) the table. Height is measured in mm, defaultChartHeight if 0.
+type tableChart struct {
+	Kind             string  `json:"kind"`
+	Title            string  `json:"title"`
+	LabelColumn      int     `json:"labelColumn"`
+	ValueColumns     []int   `json:"valueColumns"`
+	DecimalSeparator string  `json:"decimalSeparator"`
+	Position         string  `json:"position"`
+	Height           float64 `json:"height"`
+}
+
+func (c tableChart) validate(header []string, data [][]string) error {
+	switch c.Position {
+	case "", chartBeforeTable, chartAfterTable:
+	default:
+		return errors.New(fmt.Sprintf("the position \"%s\" must be before or after", c.Position))
+	}
+
+	switch c.DecimalSeparator {
+	case "", ",", ".":
+	default:
+		return errors.New(fmt.Sprintf("the decimal separator \"%s\" must be \",\" or \".\"", c.DecimalSeparator))
+	}
+
+	if c.Height < 0 {
+		return errors.New(fmt.Sprintf("the height (%.2f) must not be negative", c.Height))
+	}
+
+	if len(c.ValueColumns) == 0 {
+		return errors.New("valueColumns must not be empty")
+	}
+	for _, column := range append([]int{c.LabelColumn}, c.ValueColumns...) {
+		if column < 0 || column >= len(header) {
+			return errors.New(fmt.Sprintf("the column %d does not exist", column))
+		}
+	}
+
+	for i, row := range data {
+		if len(row) != len(header) {
+			return errors.New(fmt.Sprintf("the table row %d has %d cells for %d columns", i+1, len(row), len(header)))
+		}
+		for _, column := range c.ValueColumns {
+			if _, err := parseChartValue(row[column], c.DecimalSeparator); err != nil {
+				return errors.New(fmt.Sprintf("the table row %d: %s", i+1, err.Error()))
+			}
+		}
+	}
+
+	box, err := c.box(header, data)
+	if err != nil {
+		return err
+	}
+	return box.Validate()
+}
+
+// position returns the position of the chart relative to the table, chartAfterTable by default.
+func (c tableChart) position() string {
+	if c.Position == "" {
+		return chartAfterTable
+	}
+	return c.Position
+}
+
+// box returns the chart of the table in the default colors, it is always printed on one page.
+func (c tableChart) box(header []string, data [][]string) (generator.ChartBox, error) {
+	box := generator.ChartBox{
+		BoxConstraints: generator.BoxConstraints{KeepTogether: true},
+		Kind:           c.Kind,
+		Title:          c.Title,
+		Height:         c.Height,
+	}
+	if box.Height == 0 {
+		box.Height = defaultChartHeight
+	}
+
+	for _, row := range data {
+		box.Labels = append(box.Labels, row[c.LabelColumn])
+	}
+
+	for _, column := range c.ValueColumns {
+		series := generator.ChartSeries{Name: header[column]}
+		for _, row := range data {
+			value, err := parseChartValue(row[column], c.DecimalSeparator)
+			if err != nil {
+				return generator.ChartBox{}, err
+			}
+			series.Values = append(series.Values, value)
+		}
+		box.Series = append(box.Series, series)
+	}
+
+	return box, nil
+}
+
+// parseChartValue returns the number of a table cell, e.g. "1.234,50 €", "12.5 h" or "-3".
+// Units and spaces are ignored, the other separator than decimalSeparator separates the thousands.
+//
+// Without decimalSeparator, the last one of a comma and a dot is the decimal separator, a single comma is
+// a decimal separator, too. So a single dot is always a decimal separator, e.g. "1.234" is 1.234, not 1234.
+func parseChartValue(cell string, decimalSeparator string) (float64, error) {
+	number := strings.Map(func(r rune) rune {
+		if strings.ContainsRune("0123456789.,-+", r) {
+			return r
+		}
+		return -1
+	}, cell)
+
+	if decimalSeparator == "" {
+		decimalSeparator = "."
+		if strings.LastIndex(number, ",") > strings.LastIndex(number, ".") {
+			decimalSeparator = ","
+		}
+	}
+
+	if decimalSeparator == "," {
+		number = strings.ReplaceAll(number, ".", "")
+		number = strings.ReplaceAll(number, ",", ".")
+	} else {
+		number = strings.ReplaceAll(number, ",", "")
+	}
+
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, errors.New(fmt.Sprintf("the cell \"%s\" is no number", cell))
+	}
+	return value, nil
+}
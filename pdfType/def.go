@@ -1,20 +1,34 @@
 package pdfType
 
 import (
-	"github.com/jung-kurt/gofpdf"
+	"SimpleInvoice/generator"
+	"SimpleInvoice/norms/letter"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 )
 
 type PdfType interface {
 	SetDataFromRequest(request *http.Request) (err error)
-	GeneratePDF() (*gofpdf.Fpdf, error)
+	SetGeneratorBackend(newGenerator generator.NewGeneratorFunc)
+	GeneratePDF() (generator.Generator, error)
+	RenderHTML(w io.Writer) error
+	RenderText(w io.Writer) error
 	LogError(err error)
 
 	validateData() (err error)
 }
 
 type PdfMeta struct {
-	Font pdfFont
+	Font  pdfFont
+	Theme theme
+	Fonts *generator.FontRegistry
+}
+
+// newPdfMeta returns the meta data of a pdf type with the look of the theme and the font families of fonts.
+func newPdfMeta(t theme, fonts *generator.FontRegistry) PdfMeta {
+	return PdfMeta{Font: t.font(), Theme: t, Fonts: fonts}
 }
 
 type pdfFont struct {
@@ -22,6 +36,7 @@ type pdfFont struct {
 	SizeDefault float64
 	SizeSmall   float64
 	SizeLarge   float64
+	LineGap     float64
 }
 
 type pdfMargin struct {
@@ -47,16 +62,68 @@ type FullPersonInfo struct {
 	} `json:"address"`
 }
 
+// SenderInfo holds the contact data and the images of the sender.
+//
+// MimeLogoUrl references the logo printed into the header, MimeBadgeUrl a further image printed into the footer,
+// e.g. a certification badge (see requestImages). The placements define their position and size.
 type SenderInfo struct {
-	Phone         string  `json:"phone"`
-	Web           string  `json:"web"`
-	Email         string  `json:"email"`
-	MimeLogoUrl   string  `json:"mimeLogoUrl"`
-	MimeLogoScale float64 `json:"mimeLogoScale"`
-	Iban          string  `json:"iban"`
-	Bic           string  `json:"bic"`
-	TaxNumber     string  `json:"taxNumber"`
-	BankName      string  `json:"bankName"`
+	Phone          string                `json:"phone"`
+	Web            string                `json:"web"`
+	Email          string                `json:"email"`
+	MimeLogoUrl    string                `json:"mimeLogoUrl"`
+	MimeLogoScale  float64               `json:"mimeLogoScale"`
+	LogoPlacement  letter.ImagePlacement `json:"logoPlacement"`
+	MimeBadgeUrl   string                `json:"mimeBadgeUrl"`
+	BadgePlacement letter.ImagePlacement `json:"badgePlacement"`
+	Iban           string                `json:"iban"`
+	Bic            string                `json:"bic"`
+	TaxNumber      string                `json:"taxNumber"`
+	BankName       string                `json:"bankName"`
+}
+
+// validateImages checks the image references and the placements of the logo and the badge.
+func (info SenderInfo) validateImages(images requestImages) error {
+	if err := images.validate(info.MimeLogoUrl, info.MimeBadgeUrl); err != nil {
+		return err
+	}
+	if err := info.LogoPlacement.Validate(); err != nil {
+		return errors.New(fmt.Sprintf("invalid logoPlacement: %s", err.Error()))
+	}
+	if err := info.BadgePlacement.Validate(); err != nil {
+		return errors.New(fmt.Sprintf("invalid badgePlacement: %s", err.Error()))
+	}
+	return nil
+}
+
+// imageRefs returns the references of the logo, if it is not part of the letterhead, and of the badge.
+func (info SenderInfo) imageRefs(letterhead letterhead) (refs []string) {
+	if !letterhead.HideLogo {
+		refs = append(refs, info.MimeLogoUrl)
+	}
+	return append(refs, info.MimeBadgeUrl)
+}
+
+// printLogo prints the logo into the header of the current page,
+// if it is not part of the letterhead and the placement allows the current page.
+func (info SenderInfo) printLogo(pdfGen generator.Generator, norm letter.Norm, letterhead letterhead) {
+	if info.MimeLogoUrl == "" || letterhead.HideLogo {
+		return
+	}
+	if pdfGen.GetCurrentPageNumber() > 1 && !info.LogoPlacement.FollowUpPages {
+		return
+	}
+	norm.MimeImageHeader(pdfGen, info.MimeLogoUrl, info.LogoPlacement)
+}
+
+// printBadge prints the badge into the footer of the current page, if the placement allows the current page.
+func (info SenderInfo) printBadge(pdfGen generator.Generator, norm letter.Norm, footerStartY float64) {
+	if info.MimeBadgeUrl == "" {
+		return
+	}
+	if pdfGen.GetCurrentPageNumber() > 1 && !info.BadgePlacement.FollowUpPages {
+		return
+	}
+	norm.MimeImageFooter(pdfGen, info.MimeBadgeUrl, info.BadgePlacement, footerStartY)
 }
 
 // todo übernehmen von https://www.alexedwards.net/blog/how-to-properly-parse-a-json-request-body ?
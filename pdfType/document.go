@@ -0,0 +1,608 @@
+package pdfType
+
+import (
+	"SimpleInvoice/generator"
+	"SimpleInvoice/norms/letter"
+	"encoding/json"
+	"fmt"
+	errorsWithStack "github.com/go-errors/errors"
+	"github.com/rs/zerolog"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// Document is a generic DIN 5008 letter, composed of an ordered list of body blocks.
+type Document struct {
+	data          documentRequestData
+	meta          PdfMeta
+	logger        *zerolog.Logger
+	printErrStack bool
+	pdfGen        generator.Generator
+	newGenerator  generator.NewGeneratorFunc
+	footerStartY  float64
+	norm          letter.Norm
+	report        letter.Report
+}
+
+type documentRequestData struct {
+	LetterNorm       string             `json:"letterNorm"`
+	FoldMarks        bool               `json:"foldMarks"`
+	Letterhead       letterhead         `json:"letterhead"`
+	Theme            json.RawMessage    `json:"theme"`
+	Fonts            requestFonts       `json:"fonts"`
+	Images           requestImages      `json:"images"`
+	Watermarks       []watermark        `json:"watermarks"`
+	SenderAddress    letter.FullAdresse `json:"senderAddress"`
+	ReceiverAddress  letter.FullAdresse `json:"receiverAddress"`
+	SenderInfo       SenderInfo         `json:"senderInfo"`
+	InfoBlock        []CustomMetaDatum  `json:"infoBlock"`
+	FooterColumns    [][]string         `json:"footerColumns"`
+	PageNumberPrefix string             `json:"pageNumberPrefix"`
+	Blocks           []documentBlock    `json:"blocks"`
+}
+
+// documentBlock is one body block of a Document.
+//
+// Type selects the block and defines the used fields:
+//
+//	"headline"  Text
+//	"paragraph" Text, Style, Align
+//	"keyValue"  Items
+//	"table"     Header, Rows, Footer, ColumnPercentages, FooterColumnPercentages, Align
+//	"image"     Url, Scale, Align
+//	"signature" Labels
+//	"pageBreak"
+//
+// A paragraph needs a Text and a signature at least one label.
+// Style is a style string of generator.ParseFontStyle, e.g. "bi", Align is "L", "C", "R" or empty for the default.
+// Each table row and footer row has one cell per column.
+// A table without FooterColumnPercentages (null or empty) uses the ColumnPercentages for the footer.
+type documentBlock struct {
+	Type                    string            `json:"type"`
+	Text                    string            `json:"text"`
+	Style                   string            `json:"style"`
+	Align                   string            `json:"align"`
+	Items                   []CustomMetaDatum `json:"items"`
+	Header                  []string          `json:"header"`
+	Rows                    [][]string        `json:"rows"`
+	Footer                  [][]string        `json:"footer"`
+	ColumnPercentages       []float64         `json:"columnPercentages"`
+	FooterColumnPercentages []float64         `json:"footerColumnPercentages"`
+	Url                     string            `json:"url"`
+	Scale                   float64           `json:"scale"`
+	Labels                  []string          `json:"labels"`
+}
+
+func NewDocument(logger *zerolog.Logger) *Document {
+	return &Document{
+		data:          documentRequestData{},
+		meta:          newPdfMeta(defaultTheme(), generator.DefaultFontRegistry),
+		logger:        logger,
+		printErrStack: logger.GetLevel() <= zerolog.DebugLevel,
+		newGenerator:  generator.NewPDFGeneratorBackend,
+	}
+}
+
+func (doc *Document) SetDataFromRequest(request *http.Request) (err error) {
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			doc.LogError(err)
+		}
+	}(request.Body)
+
+	files, err := decodeRequestData(request, &doc.data)
+	if err != nil {
+		return err
+	}
+	if letterheadPdf, ok := files["letterhead"]; ok {
+		doc.data.Letterhead.Pdf = letterheadPdf
+	}
+	if err = doc.data.Fonts.addFiles(files); err != nil {
+		return err
+	}
+	doc.data.Images.addFiles(files)
+
+	err = doc.validateData()
+	if err != nil {
+		doc.data = documentRequestData{}
+		return err
+	}
+
+	return nil
+}
+
+func (doc *Document) validateData() (err error) {
+	if _, err = getLetterNorm(doc.data.LetterNorm); err != nil {
+		return err
+	}
+
+	if err = doc.data.Letterhead.validate(); err != nil {
+		return err
+	}
+
+	fonts, err := doc.data.Fonts.registry()
+	if err != nil {
+		return err
+	}
+
+	theme, err := loadTheme(doc.data.Theme, fonts)
+	if err != nil {
+		return err
+	}
+	doc.meta = newPdfMeta(theme, fonts)
+
+	if err = doc.data.SenderInfo.validateImages(doc.data.Images); err != nil {
+		return err
+	}
+
+	if err = validateWatermarks(doc.data.Watermarks, doc.data.Images); err != nil {
+		return err
+	}
+
+	if len(doc.data.FooterColumns) > 3 {
+		return errorsWithStack.New(fmt.Sprintf("at most 3 footer columns are allowed, got %d", len(doc.data.FooterColumns)))
+	}
+
+	for i, block := range doc.data.Blocks {
+		switch block.Type {
+		case "headline", "keyValue", "pageBreak":
+		case "paragraph":
+			if block.Text == "" {
+				return errorsWithStack.New(fmt.Sprintf("block %d: the text of a paragraph must not be empty", i))
+			}
+			if _, err = generator.ParseFontStyle(block.Style); err != nil {
+				return errorsWithStack.New(fmt.Sprintf("block %d: %s", i, err.Error()))
+			}
+			if err = validateAlign(block.Align); err != nil {
+				return errorsWithStack.New(fmt.Sprintf("block %d: %s", i, err.Error()))
+			}
+		case "signature":
+			if len(block.Labels) == 0 {
+				return errorsWithStack.New(fmt.Sprintf("block %d: the labels of a signature must not be empty", i))
+			}
+		case "table":
+			if err = validateColumnPercentages(block.ColumnPercentages); err != nil {
+				return errorsWithStack.New(fmt.Sprintf("block %d: %s", i, err.Error()))
+			}
+			if len(block.Header) > 0 && len(block.Header) != len(block.ColumnPercentages) {
+				return errorsWithStack.New(fmt.Sprintf("block %d: the length of header and columnPercentages must be equal", i))
+			}
+			for _, row := range block.Rows {
+				if len(row) != len(block.ColumnPercentages) {
+					return errorsWithStack.New(fmt.Sprintf("block %d: the length of each row and columnPercentages must be equal", i))
+				}
+			}
+			footerColumnPercentages := block.footerColumnPercentages()
+			if len(block.Footer) > 0 && len(block.FooterColumnPercentages) > 0 {
+				if err = validateColumnPercentages(footerColumnPercentages); err != nil {
+					return errorsWithStack.New(fmt.Sprintf("block %d: footer %s", i, err.Error()))
+				}
+			}
+			for _, row := range block.Footer {
+				if len(row) != len(footerColumnPercentages) {
+					return errorsWithStack.New(fmt.Sprintf("block %d: the length of each footer row and footerColumnPercentages (or columnPercentages) must be equal", i))
+				}
+			}
+		case "image":
+			if block.Url == "" {
+				return errorsWithStack.New(fmt.Sprintf("block %d: invalid image url \"%s\"", i, block.Url))
+			}
+			if err = validateAlign(block.Align); err != nil {
+				return errorsWithStack.New(fmt.Sprintf("block %d: %s", i, err.Error()))
+			}
+			if err = doc.data.Images.validate(block.Url); err != nil {
+				return errorsWithStack.New(fmt.Sprintf("block %d: %s", i, err.Error()))
+			}
+		default:
+			return errorsWithStack.New(fmt.Sprintf("block %d: unknown block type \"%s\"", i, block.Type))
+		}
+	}
+
+	return nil
+}
+
+// SetGeneratorBackend replaces the default PDF backend, e.g. with generator.NewRecordingGenerator.
+func (doc *Document) SetGeneratorBackend(newGenerator generator.NewGeneratorFunc) {
+	doc.newGenerator = newGenerator
+}
+
+// ComplianceReport returns the zone overflows found by the last GeneratePDF.
+func (doc *Document) ComplianceReport() letter.Report {
+	return doc.report
+}
+
+func (doc *Document) LogError(err error) {
+	var errStr string
+
+	if _, ok := err.(*errorsWithStack.Error); ok && doc.printErrStack {
+		errStr = err.(*errorsWithStack.Error).ErrorStack()
+	} else {
+		errStr = err.Error()
+	}
+
+	doc.logger.Error().Msgf(errStr)
+}
+
+func (doc *Document) GeneratePDF() (generator.Generator, error) {
+	doc.logger.Debug().Msg("generate document")
+
+	norm, err := getLetterNorm(doc.data.LetterNorm)
+	if err != nil {
+		return nil, err
+	}
+	doc.norm = norm
+
+	pdfGen, err := doc.newGenerator(
+		generator.MetaData{
+			FontName:          doc.meta.Font.FontName,
+			FontRegistry:      doc.meta.Fonts,
+			FallbackFontNames: doc.meta.Theme.FallbackFonts,
+			FontGapY:          doc.meta.Theme.LineSpacing,
+			FontSize:          doc.meta.Font.SizeDefault,
+			MarginLeft:        doc.norm.Zones().BodyStartX,
+			MarginTop:         doc.norm.Zones().NextPageStartY,
+			MarginRight:       doc.norm.Zones().PageWidth - doc.norm.Zones().BodyStopX,
+			MarginBottom:      0,
+			Unit:              "mm",
+			DefaultLineWidth:  0.4,
+			DefaultLineColor:  generator.Color(doc.meta.Theme.LineColor),
+			DefaultTextColor:  generator.Color(doc.meta.Theme.TextColor),
+			TableStyle:        doc.meta.Theme.tableStyle(),
+			PageSize:          generator.PageSize{Width: doc.norm.Zones().PageWidth, Height: doc.norm.Zones().PageHeight},
+		},
+		false,
+		doc.logger,
+		func() {
+			doc.printHeader()
+		},
+		func(isLastPage bool) {
+			doc.printFooter()
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	doc.pdfGen = pdfGen
+	doc.data.Letterhead.register(doc.pdfGen)
+	doc.data.Images.register(doc.pdfGen, doc.imageRefs()...)
+	doc.pdfGen.NewPage()
+
+	doc.doGeneratePdf()
+
+	return doc.pdfGen, doc.pdfGen.GetError()
+}
+
+func (doc *Document) infoData() (infoData []letter.InfoData) {
+	for _, datum := range doc.data.InfoBlock {
+		infoData = append(infoData, letter.InfoData{Name: datum.Name, Value: datum.Value})
+	}
+	return infoData
+}
+
+func (doc *Document) doGeneratePdf() {
+	doc.report = checkCompliance(doc.pdfGen, doc.norm, letter.CheckContent{
+		SenderAddress:   doc.data.SenderAddress,
+		ReceiverAddress: doc.data.ReceiverAddress,
+		Info:            doc.infoData(),
+		FooterColumns:   footerColumns(doc.data.FooterColumns...),
+	}, doc.logger)
+
+	if doc.data.FoldMarks {
+		doc.norm.FoldMarks(doc.pdfGen)
+	}
+
+	doc.norm.FullAddressesAndInfoPart(doc.pdfGen, doc.data.SenderAddress, doc.data.ReceiverAddress, doc.infoData())
+
+	doc.norm.Body(doc.pdfGen, func() {
+		doc.pdfGen.RenderBoxes(generator.Frame{
+			StartX:         doc.norm.Zones().BodyStartX,
+			Width:          doc.norm.Zones().BodyStopX - doc.norm.Zones().BodyStartX,
+			NextPageStartY: doc.norm.Zones().NextPageStartY,
+			StopY:          doc.norm.BodyStopY(doc.footerLines()),
+		}, doc.bodyBoxes())
+	})
+
+	prefix := doc.data.PageNumberPrefix
+	if prefix == "" {
+		prefix = doc.norm.PageNumberPrefix()
+	}
+	printInColor(doc.pdfGen, doc.meta.Theme.FooterColor, func() {
+		doc.norm.PageNumberingCustom(prefix, doc.pdfGen, doc.footerStartY, true)
+	})
+
+	printWatermarks(doc.pdfGen, doc.norm, doc.data.Watermarks)
+}
+
+// bodyBoxes converts the requested blocks into layout boxes.
+func (doc *Document) bodyBoxes() (boxes []generator.Box) {
+	// the gap between two blocks is kept together with the following block
+	var newLine = generator.SpacerBox{
+		BoxConstraints: generator.BoxConstraints{KeepWithNext: true},
+		Height:         doc.pdfGen.GetLineHeight(doc.meta.Font.SizeDefault, doc.meta.Font.LineGap),
+	}
+	var brandColor = generator.Color(doc.meta.Theme.BrandColor)
+
+	for i, block := range doc.data.Blocks {
+		if i > 0 && block.Type != "pageBreak" && doc.data.Blocks[i-1].Type != "pageBreak" {
+			boxes = append(boxes, newLine)
+		}
+
+		switch block.Type {
+		case "headline":
+			boxes = append(boxes, generator.ParagraphBox{
+				BoxConstraints: generator.BoxConstraints{KeepWithNext: true},
+				Text:           block.Text,
+				StyleStr:       "b",
+				AlignStr:       "L",
+				FontSize:       doc.meta.Font.SizeLarge,
+				FontGapY:       doc.meta.Font.LineGap,
+				TextColor:      &brandColor,
+			})
+		case "paragraph":
+			boxes = append(boxes, generator.ParagraphBox{
+				Text:     block.Text,
+				StyleStr: block.Style,
+				AlignStr: alignOrDefault(block.Align, "L"),
+				FontSize: doc.meta.Font.SizeDefault,
+				FontGapY: doc.meta.Font.LineGap,
+			})
+		case "keyValue":
+			boxes = append(boxes, doc.keyValueBox(block.Items))
+		case "table":
+			boxes = append(boxes, doc.tableBox(block))
+		case "image":
+			boxes = append(boxes, doc.imageBox(block))
+		case "signature":
+			boxes = append(boxes, signatureSectionBox(doc.pdfGen, block.Labels, doc.meta.Font))
+		case "pageBreak":
+			boxes = append(boxes, generator.PageBreakBox{})
+		}
+	}
+
+	return boxes
+}
+
+// keyValueBox prints the names in the first and the values in the second column.
+func (doc *Document) keyValueBox(items []CustomMetaDatum) generator.CanvasBox {
+	const gapNameValue = 2
+	lineHeight := doc.pdfGen.GetLineHeight(doc.meta.Font.SizeDefault, doc.meta.Font.LineGap)
+
+	return generator.CanvasBox{
+		BoxConstraints: generator.BoxConstraints{KeepTogether: true},
+		Height:         float64(len(items)) * lineHeight,
+		Draw: func(x float64, y float64, width float64) {
+			var maxNameLength = 0.
+
+			doc.pdfGen.SetFontSize(doc.meta.Font.SizeDefault)
+			doc.pdfGen.SetFontGapY(doc.meta.Font.LineGap)
+
+			for _, item := range items {
+				if nameLength := doc.pdfGen.ComputeStringLength(item.Name); nameLength > maxNameLength {
+					maxNameLength = nameLength
+				}
+			}
+
+			for i, item := range items {
+				lineY := y + float64(i)*lineHeight
+				if item.Name != "" {
+					doc.pdfGen.SetUnsafeCursor(x, lineY)
+					doc.pdfGen.PrintPdfText(item.Name, "b", "L")
+				}
+				if item.Value != "" {
+					doc.pdfGen.SetUnsafeCursor(x+maxNameLength+gapNameValue, lineY)
+					doc.pdfGen.PrintPdfText(item.Value, "", "L")
+				}
+			}
+		},
+	}
+}
+
+// footerColumnPercentages returns the FooterColumnPercentages of a table block
+// or the ColumnPercentages, if they are null or empty.
+func (block documentBlock) footerColumnPercentages() []float64 {
+	if len(block.FooterColumnPercentages) == 0 {
+		return block.ColumnPercentages
+	}
+	return block.FooterColumnPercentages
+}
+
+func (doc *Document) tableBox(block documentBlock) generator.TableBox {
+	var bodyAlign []string
+	var headerAlign []string
+	for range block.ColumnPercentages {
+		bodyAlign = append(bodyAlign, "LM")
+		headerAlign = append(headerAlign, "LM")
+	}
+
+	var footerColumnPercentages = block.footerColumnPercentages()
+	var footerAlign []string
+	for i := range footerColumnPercentages {
+		if i == len(footerColumnPercentages)-1 {
+			footerAlign = append(footerAlign, "RM")
+		} else {
+			footerAlign = append(footerAlign, "LM")
+		}
+	}
+
+	return generator.TableBox{
+		Header:             block.Header,
+		Body:               block.Rows,
+		Footer:             block.Footer,
+		ColumnWidths:       getColumnWithFromPercentage(doc.pdfGen, block.ColumnPercentages),
+		FooterColumnWidths: getColumnWithFromPercentage(doc.pdfGen, footerColumnPercentages),
+		HeaderAlign:        headerAlign,
+		BodyAlign:          bodyAlign,
+		FooterAlign:        footerAlign,
+		FontSize:           doc.meta.Font.SizeDefault,
+		FontGapY:           doc.meta.Font.LineGap,
+	}
+}
+
+func (doc *Document) imageBox(block documentBlock) generator.Box {
+	imageNameStr := block.Url
+	if !doc.pdfGen.ImageIsRegistered(imageNameStr) {
+		urlStruct, err := url.Parse(block.Url)
+		if err != nil {
+			doc.pdfGen.SetError(errorsWithStack.New(err.Error()))
+			return generator.SpacerBox{}
+		}
+		imageNameStr = doc.pdfGen.RegisterMimeImageToPdf(urlStruct)
+	}
+
+	scale := block.Scale
+	if scale == 0 {
+		scale = 1
+	}
+
+	return generator.ImageBox{
+		BoxConstraints: generator.BoxConstraints{KeepTogether: true},
+		ImageNameStr:   imageNameStr,
+		Scale:          scale,
+		AlignStr:       alignOrDefault(block.Align, "L"),
+	}
+}
+
+// imageRefs returns the references of the logo, the badge and the image blocks, see requestImages.
+func (doc *Document) imageRefs() (refs []string) {
+	refs = doc.data.SenderInfo.imageRefs(doc.data.Letterhead)
+	for _, block := range doc.data.Blocks {
+		if block.Type == "image" {
+			refs = append(refs, block.Url)
+		}
+	}
+	return append(refs, watermarkRefs(doc.data.Watermarks)...)
+}
+
+// footerLines returns the number of lines of the highest footer column.
+func (doc *Document) footerLines() (lines int) {
+	for _, column := range doc.data.FooterColumns {
+		if len(column) > lines {
+			lines = len(column)
+		}
+	}
+	return lines
+}
+
+func (doc *Document) printFooter() {
+	if doc.data.Letterhead.HideFooter {
+		// the footer is part of the letterhead, the page numbers are printed above its position
+		doc.footerStartY = letter.FooterStartY(doc.norm.Zones(), doc.footerLines())
+		doc.data.SenderInfo.printBadge(doc.pdfGen, doc.norm, doc.footerStartY)
+		return
+	}
+
+	var footerStartY float64
+	var err error
+	printInColor(doc.pdfGen, doc.meta.Theme.FooterColor, func() {
+		footerStartY, err = doc.norm.Footer(doc.printFooterContent, doc.pdfGen)
+	})
+
+	if err != nil {
+		doc.pdfGen.SetError(err)
+	}
+
+	if doc.footerStartY == 0 {
+		doc.footerStartY = footerStartY
+	}
+
+	doc.data.SenderInfo.printBadge(doc.pdfGen, doc.norm, footerStartY)
+}
+
+// printFooterContent prints the footer columns.
+// The first column is aligned left, the last column right and a middle column centered.
+func (doc *Document) printFooterContent(maxFooterHeight float64) (footerStartY float64) {
+	doc.pdfGen.SetUnsafeCursor(doc.norm.Zones().BodyStartX, maxFooterHeight)
+	for i := 0; i < doc.footerLines(); i++ {
+		doc.pdfGen.PreviousLine(doc.norm.Zones().BodyStartX)
+	}
+	_, footerStartY = doc.pdfGen.GetCursor()
+
+	columns := doc.data.FooterColumns
+	for i, column := range columns {
+		var currentStartX = doc.norm.Zones().BodyStartX
+		var alignStr = "L"
+
+		switch {
+		case len(columns) > 1 && i == len(columns)-1:
+			currentStartX = doc.norm.Zones().BodyStopX
+			alignStr = "R"
+		case i > 0:
+			currentStartX = ((doc.norm.Zones().BodyStopX - doc.norm.Zones().BodyStartX) / 2) + doc.norm.Zones().BodyStartX
+			alignStr = "C"
+		}
+
+		doc.pdfGen.SetCursor(currentStartX, footerStartY)
+		for _, line := range column {
+			if line == "" {
+				doc.pdfGen.NewLine(currentStartX)
+				continue
+			}
+			doc.pdfGen.PrintLnPdfText(line, "", alignStr)
+		}
+	}
+
+	return footerStartY
+}
+
+// printHeader prints the logo on the first page and the compact header on each follow-up page,
+// with the logo if its placement repeats it on follow-up pages.
+// The follow-up header shows the first entries of the info block, e.g. the document number and the date.
+func (doc *Document) printHeader() {
+	doc.data.Letterhead.printBackground(doc.pdfGen)
+
+	if doc.pdfGen.GetCurrentPageNumber() > 1 {
+		printInColor(doc.pdfGen, doc.meta.Theme.HeaderColor, func() {
+			doc.norm.FollowUpHeader(doc.pdfGen, letter.FollowUpHeader{
+				Sender:   letter.ShortName(doc.data.SenderAddress),
+				Receiver: letter.ShortName(doc.data.ReceiverAddress),
+				Info:     doc.infoData(),
+			})
+		})
+	}
+
+	doc.data.SenderInfo.printLogo(doc.pdfGen, doc.norm, doc.data.Letterhead)
+}
+
+// RenderHTML writes the document as HTML e-mail body to w.
+func (doc *Document) RenderHTML(w io.Writer) error {
+	return renderMailHTML(w, doc.mailView())
+}
+
+// RenderText writes the document as plain text e-mail body to w.
+func (doc *Document) RenderText(w io.Writer) error {
+	return renderMailText(w, doc.mailView())
+}
+
+// mailView shows the text, key value and table blocks. Images, signatures and page breaks are skipped.
+func (doc *Document) mailView() mailView {
+	view := newMailView(doc.data.SenderAddress, doc.data.ReceiverAddress, doc.infoData())
+
+	for _, block := range doc.data.Blocks {
+		switch block.Type {
+		case "headline":
+			if view.Headline == "" && len(view.Sections) == 0 {
+				view.Headline = block.Text
+			} else {
+				view.addText(block.Text)
+			}
+		case "paragraph":
+			view.addText(block.Text)
+		case "keyValue":
+			var items []letter.InfoData
+			for _, item := range block.Items {
+				items = append(items, letter.InfoData{Name: item.Name, Value: item.Value})
+			}
+			view.Sections = append(view.Sections, mailSection{KeyValues: items})
+		case "table":
+			view.addTable(mailTable{Header: block.Header, Rows: block.Rows, Footer: block.Footer})
+		}
+	}
+
+	view.FooterColumns = footerColumns(doc.data.FooterColumns...)
+
+	return view
+}
@@ -0,0 +1,63 @@
+package pdfType
+
+import (
+	"SimpleInvoice/generator"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestDocument_validateData(t *testing.T) {
+	logger := zerolog.Nop()
+
+	tests := []struct {
+		name    string
+		block   documentBlock
+		wantErr string
+	}{
+		{name: "paragraph", block: documentBlock{Type: "paragraph", Text: "Sehr geehrte Damen und Herren,", Style: "bi", Align: "C"}},
+		{name: "paragraph in the default style", block: documentBlock{Type: "paragraph", Text: "Sehr geehrte Damen und Herren,"}},
+		{name: "paragraph without text", block: documentBlock{Type: "paragraph"}, wantErr: "the text of a paragraph must not be empty"},
+		{name: "paragraph with an unknown style", block: documentBlock{Type: "paragraph", Text: "Text", Style: "bx"}, wantErr: "unknown letter"},
+		{name: "paragraph with two weights", block: documentBlock{Type: "paragraph", Text: "Text", Style: "bl"}, wantErr: "more than one font weight"},
+		{name: "paragraph with an unknown align", block: documentBlock{Type: "paragraph", Text: "Text", Align: "J"}, wantErr: "the align \"J\" must be L, C or R"},
+		{name: "image with an unknown align", block: documentBlock{Type: "image", Url: "asset:logo.png", Align: "left"}, wantErr: "the align \"left\" must be L, C or R"},
+		{name: "signature", block: documentBlock{Type: "signature", Labels: []string{"Musterfirma GbR", "Auftraggeber"}}},
+		{name: "signature without labels", block: documentBlock{Type: "signature"}, wantErr: "the labels of a signature must not be empty"},
+		{name: "unknown block type", block: documentBlock{Type: "chart"}, wantErr: "unknown block type \"chart\""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := NewDocument(&logger)
+			doc.data.Blocks = []documentBlock{{Type: "headline", Text: "Angebot"}, tt.block}
+
+			err := doc.validateData()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateData() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), "block 1: ") || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateData() error = %v, want block 1: ...%s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSignaturePartHeight(t *testing.T) {
+	logger := zerolog.Nop()
+	rec, err := generator.NewRecordingGenerator(generator.MetaData{FontName: "OpenSans", FontSize: 10, Unit: "mm"}, false, &logger, func() {}, func(isLastPage bool) {})
+	if err != nil {
+		t.Fatalf("init recorder error\n%s", err.Error())
+	}
+
+	// the name, the three empty lines and the date of a part are spaced by the line gap of the font
+	narrow := signaturePartHeight(rec, pdfFont{SizeDefault: 10, SizeSmall: 8, LineGap: 1})
+	wide := signaturePartHeight(rec, pdfFont{SizeDefault: 10, SizeSmall: 8, LineGap: 3})
+	if diff := wide - narrow; math.Abs(diff-4*2) > 1e-9 {
+		t.Errorf("signaturePartHeight() grows by %v for a line gap of 3 instead of 1, want %v", diff, 4*2)
+	}
+}
@@ -0,0 +1,116 @@
+package pdfType
+
+import (
+	"SimpleInvoice/generator"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// requestFontFilePrefix is the prefix of the multipart files of the request fonts,
+// e.g. "font-NotoSansJP-Regular" for the regular style of the family "NotoSansJP".
+const requestFontFilePrefix = "font-"
+
+// requestFont is a font family uploaded with a request and only used for this request.
+// The font files are base64 encoded in the JSON or uploaded as multipart files (see requestFontFilePrefix).
+//
+// In the JSON, each face is a field named like the style name of generator.FontStyles in lower camel case,
+// e.g. {"name": "NotoSansJP", "regular": "AAEAAAAR...", "semiBoldItalic": "AAEAAAAR..."}.
+type requestFont struct {
+	Name string
+	// Faces maps the style names of generator.FontStyles, e.g. "SemiBoldItalic", to the font file data.
+	Faces map[string][]byte
+}
+
+func (font *requestFont) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	*font = requestFont{Faces: map[string][]byte{}}
+	for key, value := range fields {
+		if key == "name" {
+			if err := json.Unmarshal(value, &font.Name); err != nil {
+				return err
+			}
+			continue
+		}
+
+		styleName := ""
+		if key != "" {
+			styleName = strings.ToUpper(key[:1]) + key[1:]
+		}
+		if _, ok := generator.FontStyles[styleName]; !ok {
+			return errors.New(fmt.Sprintf("the font field \"%s\" is no font style like \"regular\" or \"semiBoldItalic\"", key))
+		}
+
+		var fontData []byte
+		if err := json.Unmarshal(value, &fontData); err != nil {
+			return err
+		}
+		font.Faces[styleName] = fontData
+	}
+
+	return nil
+}
+
+type requestFonts []requestFont
+
+// addFiles adds the multipart font files to the request fonts.
+func (fonts *requestFonts) addFiles(files map[string][]byte) error {
+	for name, data := range files {
+		if !strings.HasPrefix(name, requestFontFilePrefix) {
+			continue
+		}
+
+		familyName, styleName := "", ""
+		if separator := strings.LastIndex(name, "-"); separator > len(requestFontFilePrefix) {
+			familyName, styleName = name[len(requestFontFilePrefix):separator], name[separator+1:]
+		}
+		if _, ok := generator.FontStyles[styleName]; !ok || familyName == "" {
+			return errors.New(fmt.Sprintf("the font file \"%s\" must be named like \"font-<family>-Regular\"", name))
+		}
+
+		fonts.font(familyName).Faces[styleName] = data
+	}
+
+	return nil
+}
+
+// font returns the request font of the family name and adds it, if it is missing.
+func (fonts *requestFonts) font(name string) *requestFont {
+	for i := range *fonts {
+		if (*fonts)[i].Name == name {
+			if (*fonts)[i].Faces == nil {
+				(*fonts)[i].Faces = map[string][]byte{}
+			}
+			return &(*fonts)[i]
+		}
+	}
+
+	*fonts = append(*fonts, requestFont{Name: name, Faces: map[string][]byte{}})
+	return &(*fonts)[len(*fonts)-1]
+}
+
+// registry returns the font registry of the request: the server fonts and the request fonts.
+func (fonts requestFonts) registry() (*generator.FontRegistry, error) {
+	if len(fonts) == 0 {
+		return generator.DefaultFontRegistry, nil
+	}
+
+	registry := generator.NewFontRegistry(generator.DefaultFontRegistry)
+	for _, font := range fonts {
+		family := generator.FontFamily{Name: font.Name, Styles: map[string][]byte{}}
+		for styleName, fontData := range font.Faces {
+			family.Styles[generator.FontStyles[styleName]] = fontData
+		}
+
+		if err := registry.Register(family); err != nil {
+			return nil, err
+		}
+	}
+
+	return registry, nil
+}
@@ -2,6 +2,7 @@ package pdfType
 
 import (
 	"SimpleInvoice/generator"
+	"errors"
 	"fmt"
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
@@ -53,9 +54,50 @@ func germanNumber[T float64 | int](n T) string {
 	}
 }
 
-func getCellWith(pdfGen *generator.PDFGenerator, percent float64) float64 {
-	maxSavePrintingWidth, _ := pdfGen.GetPdf().GetPageSize()
+func getCellWith(pdfGen generator.Generator, percent float64) float64 {
+	maxSavePrintingWidth, _ := pdfGen.GetPageSize()
 	maxSavePrintingWidth = maxSavePrintingWidth - pdfGen.GetMarginLeft() - pdfGen.GetMarginRight()
 
 	return (percent * maxSavePrintingWidth) / 100.0
 }
+
+// validateColumnPercentages checks, if the sum of all column percentages is nearly 100%.
+// A small inaccuracy is allowed.
+func validateColumnPercentages(columnPercentages []float64) error {
+	if len(columnPercentages) == 0 {
+		return errors.New("columnPercentages must not be empty")
+	}
+
+	var pFull float64
+	for _, percentage := range columnPercentages {
+		if percentage <= 0 {
+			return errors.New("each column percentage must be grater then 0")
+		}
+		pFull += percentage
+	}
+
+	if pFull < 99.9 || pFull > 100.1 {
+		return errors.New(fmt.Sprintf("sum of columnPercentages (%.2f) out of range", pFull))
+	}
+
+	return nil
+}
+
+// validateAlign checks, if alignStr is "L", "C", "R" or empty for the default alignment.
+func validateAlign(alignStr string) error {
+	switch alignStr {
+	case "", "L", "C", "R":
+		return nil
+	default:
+		return errors.New(fmt.Sprintf("the align \"%s\" must be L, C or R", alignStr))
+	}
+}
+
+func alignOrDefault(alignStr string, defaultAlignStr string) string {
+	switch alignStr {
+	case "L", "C", "R":
+		return alignStr
+	default:
+		return defaultAlignStr
+	}
+}
@@ -0,0 +1,140 @@
+package pdfType
+
+import (
+	"SimpleInvoice/generator"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// AssetDir is the server directory of the images referenced with "asset:<path>", e.g. the company logos.
+// Without an asset directory, asset references are rejected.
+var AssetDir = ""
+
+// requestImageFilePrefix is the prefix of the multipart image files, e.g. "image-logo" for the image "image:logo".
+const requestImageFilePrefix = "image-"
+
+// prefixes of the image references, see requestImages
+const (
+	imageRefPrefix = "image:"
+	assetRefPrefix = "asset:"
+)
+
+// requestImages are the images uploaded with a request by their name.
+// The images are base64 encoded in the JSON field "images" or uploaded as multipart files (see requestImageFilePrefix).
+//
+// An image field of the request, e.g. mimeLogoUrl, references an image by
+//
+//	"image:<name>" an image uploaded with the request,
+//	"asset:<path>" an image of the AssetDir, or
+//	"https://..." an image downloaded by the generator (see generator.PDFGenerator.RegisterMimeImageToPdf).
+type requestImages map[string][]byte
+
+// addFiles adds the multipart image files to the request images.
+func (images *requestImages) addFiles(files map[string][]byte) {
+	for name, data := range files {
+		if !strings.HasPrefix(name, requestImageFilePrefix) {
+			continue
+		}
+
+		if *images == nil {
+			*images = requestImages{}
+		}
+		(*images)[name[len(requestImageFilePrefix):]] = data
+	}
+}
+
+// validate checks the uploaded images and the image references refs. Empty references are ignored.
+func (images requestImages) validate(refs ...string) error {
+	for name, data := range images {
+		if _, err := generator.ImageTypeFromData(data); err != nil {
+			return errors.New(fmt.Sprintf("the image \"%s\" is invalid: %s", name, err.Error()))
+		}
+	}
+
+	for _, ref := range refs {
+		if ref == "" {
+			continue
+		}
+
+		if isDownloadRef(ref) {
+			if _, err := url.Parse(ref); err != nil {
+				return errors.New(fmt.Sprintf("invalid image url \"%s\"", ref))
+			}
+			continue
+		}
+
+		data, err := images.load(ref)
+		if err != nil {
+			return err
+		}
+		if _, err = generator.ImageTypeFromData(data); err != nil {
+			return errors.New(fmt.Sprintf("the image \"%s\" is invalid: %s", ref, err.Error()))
+		}
+	}
+
+	return nil
+}
+
+// register registers the uploaded and asset images of refs with the reference as image name.
+// The images to download are registered by the letter norm or the document on their first use.
+func (images requestImages) register(pdfGen generator.Generator, refs ...string) {
+	for _, ref := range refs {
+		if ref == "" || isDownloadRef(ref) || pdfGen.ImageIsRegistered(ref) {
+			continue
+		}
+
+		data, err := images.load(ref)
+		if err != nil {
+			pdfGen.SetError(err)
+			return
+		}
+		pdfGen.RegisterImage(ref, data)
+	}
+}
+
+// load returns the image data of an "image:" or "asset:" reference.
+func (images requestImages) load(ref string) (data []byte, err error) {
+	switch {
+	case strings.HasPrefix(ref, imageRefPrefix):
+		data, ok := images[ref[len(imageRefPrefix):]]
+		if !ok {
+			return nil, errors.New(fmt.Sprintf("the image \"%s\" is not uploaded, send it in \"images\" or as multipart file \"%s%s\"", ref, requestImageFilePrefix, ref[len(imageRefPrefix):]))
+		}
+		return data, nil
+	case strings.HasPrefix(ref, assetRefPrefix):
+		return loadAsset(ref[len(assetRefPrefix):])
+	default:
+		return nil, errors.New(fmt.Sprintf("the image \"%s\" must be an http(s) URL, \"image:<name>\" or \"asset:<path>\"", ref))
+	}
+}
+
+// loadAsset reads the image file path of the AssetDir.
+// The path is relative to the AssetDir and must not leave it.
+func loadAsset(path string) ([]byte, error) {
+	if AssetDir == "" {
+		return nil, errors.New(fmt.Sprintf("the asset \"%s\" can't be loaded, the server has no asset directory", path))
+	}
+	if !fs.ValidPath(path) {
+		return nil, errors.New(fmt.Sprintf("the asset path \"%s\" is invalid", path))
+	}
+
+	assets := os.DirFS(AssetDir)
+	info, err := fs.Stat(assets, path)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("the asset \"%s\" does not exist", path))
+	}
+	if info.Size() > int64(generator.MaxImageSize) {
+		return nil, errors.New(fmt.Sprintf("the asset \"%s\" has %d bytes, only %d bytes are allowed", path, info.Size(), generator.MaxImageSize))
+	}
+
+	return fs.ReadFile(assets, path)
+}
+
+// isDownloadRef returns true, if the image reference is an http or https URL.
+func isDownloadRef(ref string) bool {
+	return strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://")
+}
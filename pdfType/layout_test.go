@@ -0,0 +1,109 @@
+package pdfType
+
+import (
+	"SimpleInvoice/generator"
+	din5008a "SimpleInvoice/norms/letter/din-5008-a"
+	"bytes"
+	"math"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestPdfType_GeneratePDF_layout(t *testing.T) {
+	logger := zerolog.Nop()
+
+	tests := []struct {
+		name         string
+		pdfType      PdfType
+		example      string
+		wantAddress  []string
+		wantTables   []string
+		wantBarcodes int
+	}{
+		{
+			name:         "invoice",
+			pdfType:      NewInvoice(&logger),
+			example:      "pdfInvoiceExample.json",
+			wantAddress:  []string{"Dr. Otto Normalverbraucher", "CrafingStraße 11a", "2.OG", "04321 Catcity"},
+			wantTables:   []string{"PrintTableHeader", "PrintTableBody", "PrintTableFooter"},
+			wantBarcodes: 0,
+		},
+		{
+			name:         "delivery note",
+			pdfType:      NewDeliveryNode(&logger),
+			example:      "pdfDeliveryNoteExample.json",
+			wantAddress:  []string{"Dr. Otto Normalverbraucher", "CrafingStraße 11a", "2.OG", "04321 Catcity"},
+			wantTables:   []string{"PrintTableHeader", "PrintTableBody"},
+			wantBarcodes: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := os.ReadFile(tt.example)
+			if err != nil {
+				t.Fatalf("read example error\n%s", err.Error())
+			}
+			if err = tt.pdfType.SetDataFromRequest(httptest.NewRequest("POST", "/", bytes.NewReader(body))); err != nil {
+				t.Fatalf("SetDataFromRequest() error = %v", err)
+			}
+			tt.pdfType.SetGeneratorBackend(generator.NewRecordingGeneratorBackend)
+
+			gen, err := tt.pdfType.GeneratePDF()
+			if err != nil {
+				t.Fatalf("GeneratePDF() error = %v", err)
+			}
+			rec := gen.(*generator.RecordingGenerator)
+
+			if pages := len(rec.OperationsByName("NewPage")); pages != 1 {
+				t.Errorf("GeneratePDF() has %d pages, want 1", pages)
+			}
+
+			// the address lines are printed from the top of the receiver zone in the window of the envelope
+			var gotAddress []string
+			for _, op := range rec.OperationsByName("PrintPdfText") {
+				if op.X == din5008a.AddressReceiverTextStartX && op.Y >= din5008a.AddressReceiverTextStartY && op.Y < din5008a.AddressReceiverTextStopY {
+					gotAddress = append(gotAddress, op.Text)
+				}
+			}
+			if len(gotAddress) != len(tt.wantAddress) {
+				t.Fatalf("the receiver zone contains %q, want %q", gotAddress, tt.wantAddress)
+			}
+			for i := range gotAddress {
+				if gotAddress[i] != tt.wantAddress[i] {
+					t.Errorf("the receiver zone contains %q, want %q", gotAddress, tt.wantAddress)
+					break
+				}
+			}
+
+			// the parts of the items table follow each other in the body and fill the width of the body
+			lastY := din5008a.BodyStartY
+			for _, name := range tt.wantTables {
+				operations := rec.OperationsByName(name)
+				if len(operations) != 1 {
+					t.Fatalf("%s is recorded %d times, want 1", name, len(operations))
+				}
+				op := operations[0]
+
+				if op.X != din5008a.BodyStartX || op.Y < lastY {
+					t.Errorf("%s starts at %.2f, %.2f, want %.2f and below %.2f", name, op.X, op.Y, din5008a.BodyStartX, lastY)
+				}
+				lastY = op.Y
+
+				var width float64
+				for _, columnWidth := range op.Values {
+					width += columnWidth
+				}
+				if wantWidth := din5008a.BodyStopX - din5008a.BodyStartX; math.Abs(width-wantWidth) > 0.01 {
+					t.Errorf("%s has the width %.2f, want %.2f", name, width, wantWidth)
+				}
+			}
+
+			if barcodes := len(rec.OperationsByName("DrawBarcode")); barcodes != tt.wantBarcodes {
+				t.Errorf("DrawBarcode is recorded %d times, want %d", barcodes, tt.wantBarcodes)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package pdfType
+
+import (
+	"SimpleInvoice/norms/letter"
+	din5008a "SimpleInvoice/norms/letter/din-5008-a"
+	din5008b "SimpleInvoice/norms/letter/din-5008-b"
+	sn010130 "SimpleInvoice/norms/letter/sn-010130"
+	us10 "SimpleInvoice/norms/letter/us-10"
+	"fmt"
+	errorsWithStack "github.com/go-errors/errors"
+	"sort"
+	"strings"
+)
+
+// defaultLetterNorm is used, if a request selects no letter norm.
+const defaultLetterNorm = "din-5008-a"
+
+// letterNorms contains all supported letter norms by the name used in a request.
+var letterNorms = map[string]letter.Norm{}
+
+func init() {
+	for _, norm := range []letter.Norm{din5008a.Norm, din5008b.Norm, sn010130.Norm, us10.Norm} {
+		letterNorms[norm.Name()] = norm
+	}
+}
+
+// getLetterNorm returns the letter norm of name, or the default letter norm for an empty name.
+func getLetterNorm(name string) (norm letter.Norm, err error) {
+	if name == "" {
+		name = defaultLetterNorm
+	}
+
+	norm, ok := letterNorms[name]
+	if !ok {
+		var names []string
+		for supported := range letterNorms {
+			names = append(names, fmt.Sprintf("\"%s\"", supported))
+		}
+		sort.Strings(names)
+
+		return nil, errorsWithStack.New(fmt.Sprintf("letterNorm \"%s\" is not supported, use one of %s", name, strings.Join(names, ", ")))
+	}
+
+	return norm, nil
+}
@@ -0,0 +1,121 @@
+package pdfType
+
+import (
+	"SimpleInvoice/generator"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// names of the imported letterhead pages, see generator.PDFGenerator.RegisterPdfPage
+const (
+	letterheadFirstPage    = "letterhead-first"
+	letterheadFollowUpPage = "letterhead-follow-up"
+)
+
+// maxMultipartMemory is the size in bytes of the uploaded files kept in memory, the rest is stored in temporary files.
+const maxMultipartMemory = 32 << 20
+
+// maxRequestSize limits the size in bytes of a request body with the JSON data and the uploaded files.
+const maxRequestSize = 64 << 20
+
+// letterhead is a designed letterhead PDF printed as background of the letter pages.
+// The zones of the letter norm are printed on top of it.
+//
+// Pdf is base64 encoded in the JSON or uploaded as multipart file "letterhead" (see decodeRequestData).
+// Page 1 is the background of the first page. If FollowUpPages is true, page 2 is the background of each follow-up page.
+// HideLogo and HideFooter switch off the built-in logo and footer, if they are part of the letterhead.
+type letterhead struct {
+	Pdf           []byte `json:"pdf"`
+	FollowUpPages bool   `json:"followUpPages"`
+	HideLogo      bool   `json:"hideLogo"`
+	HideFooter    bool   `json:"hideFooter"`
+}
+
+func (l letterhead) validate() error {
+	if len(l.Pdf) == 0 {
+		if l.FollowUpPages || l.HideLogo || l.HideFooter {
+			return errors.New("letterhead options are set, but no letterhead pdf is given")
+		}
+		return nil
+	}
+
+	if !bytes.HasPrefix(l.Pdf, []byte("%PDF-")) {
+		return errors.New("the letterhead is no pdf")
+	}
+
+	return nil
+}
+
+// register imports the letterhead pages. Call it before the first page is added.
+func (l letterhead) register(pdfGen generator.Generator) {
+	if len(l.Pdf) == 0 {
+		return
+	}
+
+	pdfGen.RegisterPdfPage(letterheadFirstPage, l.Pdf, 1)
+	if l.FollowUpPages {
+		pdfGen.RegisterPdfPage(letterheadFollowUpPage, l.Pdf, 2)
+	}
+}
+
+// printBackground places the letterhead page of the current page. Call it first in the header function.
+func (l letterhead) printBackground(pdfGen generator.Generator) {
+	switch {
+	case pdfGen.GetCurrentPageNumber() == 1 && pdfGen.PdfPageIsRegistered(letterheadFirstPage):
+		pdfGen.PlaceRegisteredPdfPage(letterheadFirstPage)
+	case pdfGen.GetCurrentPageNumber() > 1 && pdfGen.PdfPageIsRegistered(letterheadFollowUpPage):
+		pdfGen.PlaceRegisteredPdfPage(letterheadFollowUpPage)
+	}
+}
+
+// decodeRequestData decodes the JSON request body into data.
+//
+// A multipart/form-data request contains the JSON as field or file "data" and further files, e.g. a letterhead PDF.
+// The further files are returned by their field name.
+// A body above maxRequestSize returns an error.
+func decodeRequestData(request *http.Request, data interface{}) (files map[string][]byte, err error) {
+	// the response writer is unknown here, it is only used to close the connection after a too large body
+	request.Body = http.MaxBytesReader(nil, request.Body, maxRequestSize)
+
+	mediaType, _, _ := mime.ParseMediaType(request.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		return nil, json.NewDecoder(request.Body).Decode(data)
+	}
+
+	if err = request.ParseMultipartForm(maxMultipartMemory); err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = request.MultipartForm.RemoveAll()
+	}()
+
+	files = map[string][]byte{}
+	for name, fileHeaders := range request.MultipartForm.File {
+		file, err := fileHeaders[0].Open()
+		if err != nil {
+			return nil, err
+		}
+
+		files[name], err = io.ReadAll(file)
+		_ = file.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	jsonData, ok := files["data"]
+	if !ok {
+		values := request.MultipartForm.Value["data"]
+		if len(values) == 0 {
+			return nil, errors.New("the multipart request has no field \"data\" with the JSON data")
+		}
+		jsonData = []byte(values[0])
+	}
+	delete(files, "data")
+
+	return files, json.NewDecoder(bytes.NewReader(jsonData)).Decode(data)
+}
@@ -0,0 +1,330 @@
+package pdfType
+
+import (
+	"SimpleInvoice/norms/letter"
+	htmlTemplate "html/template"
+	"io"
+	"strings"
+	textTemplate "text/template"
+	"unicode/utf8"
+)
+
+// mailView is the content of a pdf type, rendered as HTML or plain text e-mail body
+// with renderMailHTML() and renderMailText().
+type mailView struct {
+	SenderLine    string
+	Receiver      []string
+	Info          []letter.InfoData
+	Headline      string
+	Sections      []mailSection
+	FooterColumns [][]string
+}
+
+// mailSection is one body part of a mailView. Only one of the fields is set.
+type mailSection struct {
+	Text      string
+	SmallText string
+	KeyValues []letter.InfoData
+	Table     *mailTable
+}
+
+// mailTable is a table of a mailView.
+// Each footer row is aligned to the right side of the table, e.g. the label and value of a tax summary.
+type mailTable struct {
+	Header       []string
+	Rows         [][]string
+	Footer       [][]string
+	RightAligned []bool
+}
+
+// newMailView returns a mailView with the address and info part of a letter.
+func newMailView(senderAddress letter.FullAdresse, receiverAddress letter.FullAdresse, info []letter.InfoData) mailView {
+	companyLine, roadLine := letter.SenderAddressLines(senderAddress)
+
+	return mailView{
+		SenderLine: strings.TrimSpace(companyLine) + " - " + roadLine,
+		Receiver:   letter.ReceiverAddressLines(receiverAddress, senderAddress.Address.CountryCode),
+		Info:       info,
+	}
+}
+
+// addText appends a paragraph section, empty texts are skipped.
+func (view *mailView) addText(text string) {
+	if strings.TrimSpace(text) != "" {
+		view.Sections = append(view.Sections, mailSection{Text: text})
+	}
+}
+
+// addSmallText appends a paragraph section printed in a small font, empty texts are skipped.
+func (view *mailView) addSmallText(text string) {
+	if strings.TrimSpace(text) != "" {
+		view.Sections = append(view.Sections, mailSection{SmallText: text})
+	}
+}
+
+func (view *mailView) addTable(table mailTable) {
+	view.Sections = append(view.Sections, mailSection{Table: &table})
+}
+
+// footerColumns returns the non-empty footer lines of each column.
+func footerColumns(columns ...[]string) (footer [][]string) {
+	for _, column := range columns {
+		var lines []string
+		for _, line := range column {
+			if strings.TrimSpace(line) != "" {
+				lines = append(lines, line)
+			}
+		}
+		if len(lines) > 0 {
+			footer = append(footer, lines)
+		}
+	}
+	return footer
+}
+
+var mailHTMLTemplate = htmlTemplate.Must(htmlTemplate.New("mail").Funcs(htmlTemplate.FuncMap{
+	"lines":       func(text string) []string { return strings.Split(text, "\n") },
+	"footerStart": mailTableFooterStart,
+	"add":         func(a int, b int) int { return a + b },
+	"rightAligned": func(table *mailTable, column int) bool {
+		return column < len(table.RightAligned) && table.RightAligned[column]
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Headline}}</title></head>
+<body style="font-family: 'Open Sans', Arial, sans-serif; font-size: 14px; color: #000000;">
+<table role="presentation" style="width: 100%; max-width: 700px; border-collapse: collapse;">
+<tr>
+<td style="vertical-align: top; padding: 0 0 24px 0;">
+{{- if .SenderLine}}<div style="font-size: 10px; color: #555555;">{{.SenderLine}}</div>{{end}}
+{{- range .Receiver}}<div>{{.}}</div>{{end -}}
+</td>
+<td style="vertical-align: top; padding: 0 0 24px 0;">
+{{- if .Info}}<table role="presentation" style="border-collapse: collapse;">
+{{- range .Info}}<tr><td style="padding: 0 12px 0 0;">{{.Name}}</td><td>{{.Value}}</td></tr>{{end -}}
+</table>{{end -}}
+</td>
+</tr>
+</table>
+{{- if .Headline}}
+<h1 style="font-size: 20px;">{{.Headline}}</h1>
+{{- end}}
+{{- range .Sections}}
+{{- if .Text}}
+<p>{{range $i, $line := lines .Text}}{{if $i}}<br>{{end}}{{$line}}{{end}}</p>
+{{- else if .SmallText}}
+<p style="font-size: 11px; font-style: italic;">{{range $i, $line := lines .SmallText}}{{if $i}}<br>{{end}}{{$line}}{{end}}</p>
+{{- else if .KeyValues}}
+<table role="presentation" style="border-collapse: collapse; margin: 0 0 14px 0;">
+{{- range .KeyValues}}<tr><td style="padding: 0 12px 0 0; font-weight: bold;">{{.Name}}</td><td>{{.Value}}</td></tr>{{end}}
+</table>
+{{- else if .Table}}
+{{- $table := .Table}}
+<table style="width: 100%; max-width: 700px; border-collapse: collapse; margin: 0 0 14px 0;">
+{{- if $table.Header}}
+<thead><tr style="background-color: #efefef;">
+{{- range $i, $cell := $table.Header}}<th style="padding: 6px; border-bottom: 1px solid #a2a2a2; text-align: {{if rightAligned $table $i}}right{{else}}left{{end}};">{{$cell}}</th>{{end -}}
+</tr></thead>
+{{- end}}
+<tbody>
+{{- range $table.Rows}}<tr>
+{{- range $i, $cell := .}}<td style="padding: 6px; border-bottom: 1px solid #a2a2a2; vertical-align: top; text-align: {{if rightAligned $table $i}}right{{else}}left{{end}};">{{range $j, $line := lines $cell}}{{if $j}}<br>{{end}}{{$line}}{{end}}</td>{{end -}}
+</tr>{{end}}
+</tbody>
+{{- if $table.Footer}}
+<tfoot>
+{{- range $r, $row := $table.Footer}}<tr>
+{{- $start := footerStart $table $row}}
+{{- if $start}}<td colspan="{{$start}}"></td>{{end}}
+{{- range $i, $cell := $row}}<td style="padding: 6px; {{if eq (add $r 1) (len $table.Footer)}}font-weight: bold; background-color: #efefef; border-bottom: 1px solid #a2a2a2;{{end}} text-align: {{if eq (add $i 1) (len $row)}}right{{else}}left{{end}};">{{$cell}}</td>{{end -}}
+</tr>{{end}}
+</tfoot>
+{{- end}}
+</table>
+{{- end}}
+{{- end}}
+{{- if .FooterColumns}}
+<table role="presentation" style="width: 100%; max-width: 700px; border-collapse: collapse; border-top: 1px solid #a2a2a2; margin: 24px 0 0 0; font-size: 12px; color: #555555;">
+<tr>
+{{- range .FooterColumns}}<td style="vertical-align: top; padding: 6px 6px 0 0;">{{range $i, $line := .}}{{if $i}}<br>{{end}}{{$line}}{{end}}</td>{{end -}}
+</tr>
+</table>
+{{- end}}
+</body>
+</html>
+`))
+
+var mailTextTemplate = textTemplate.Must(textTemplate.New("mail").Funcs(textTemplate.FuncMap{
+	"table":    formatTextTable,
+	"keyValue": formatTextKeyValues,
+}).Parse(`{{if .SenderLine}}{{.SenderLine}}
+
+{{end}}{{range .Receiver}}{{.}}
+{{end}}{{if .Info}}
+{{keyValue .Info}}{{end}}{{if .Headline}}
+{{.Headline}}
+{{end}}{{range .Sections}}
+{{if .Text}}{{.Text}}
+{{else if .SmallText}}{{.SmallText}}
+{{else if .KeyValues}}{{keyValue .KeyValues}}{{else if .Table}}{{table .Table}}{{end}}{{end}}{{if .FooterColumns}}
+--
+{{range $i, $column := .FooterColumns}}{{if $i}}
+{{end}}{{range $column}}{{.}}
+{{end}}{{end}}{{end}}`))
+
+// renderMailHTML writes the view as HTML e-mail body to w.
+func renderMailHTML(w io.Writer, view mailView) error {
+	return mailHTMLTemplate.Execute(w, view)
+}
+
+// renderMailText writes the view as plain text e-mail body to w.
+func renderMailText(w io.Writer, view mailView) error {
+	return mailTextTemplate.Execute(w, view)
+}
+
+// mailTableFooterStart returns the number of empty columns in front of a footer row, to align the row to the right side.
+func mailTableFooterStart(table *mailTable, row []string) int {
+	if start := len(table.Header) - len(row); start > 0 {
+		return start
+	}
+	return 0
+}
+
+// formatTextKeyValues formats the names and values in two aligned columns.
+func formatTextKeyValues(items []letter.InfoData) string {
+	var nameWidth int
+	for _, item := range items {
+		if width := utf8.RuneCountInString(item.Name); width > nameWidth {
+			nameWidth = width
+		}
+	}
+
+	var builder strings.Builder
+	for _, item := range items {
+		builder.WriteString(padText(item.Name, nameWidth, false))
+		builder.WriteString("  ")
+		builder.WriteString(item.Value)
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+// formatTextTable formats a table with aligned columns in a monospace font.
+// Multiline cells are joined into one line.
+func formatTextTable(table *mailTable) string {
+	const columnGap = "  "
+
+	singleLine := func(cell string) string {
+		return strings.Join(strings.Fields(cell), " ")
+	}
+
+	columns := len(table.Header)
+	for _, row := range table.Rows {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+
+	widths := make([]int, columns)
+	for _, row := range append([][]string{table.Header}, table.Rows...) {
+		for i, cell := range row {
+			if width := utf8.RuneCountInString(singleLine(cell)); width > widths[i] {
+				widths[i] = width
+			}
+		}
+	}
+
+	// a table with only a footer has no columns, it is sized by the footer
+	var tableWidth int
+	for i, width := range widths {
+		if i > 0 {
+			tableWidth += len(columnGap)
+		}
+		tableWidth += width
+	}
+
+	var builder strings.Builder
+	writeRow := func(row []string) {
+		var cells []string
+		for i := range widths {
+			var cell string
+			if i < len(row) {
+				cell = singleLine(row[i])
+			}
+			rightAligned := i < len(table.RightAligned) && table.RightAligned[i]
+			cells = append(cells, padText(cell, widths[i], rightAligned))
+		}
+		builder.WriteString(strings.TrimRight(strings.Join(cells, columnGap), " "))
+		builder.WriteString("\n")
+	}
+
+	if len(table.Header) > 0 {
+		writeRow(table.Header)
+		builder.WriteString(strings.Repeat("-", tableWidth))
+		builder.WriteString("\n")
+	}
+	for _, row := range table.Rows {
+		writeRow(row)
+	}
+
+	if len(table.Footer) > 0 {
+		// the labels of the footer rows are aligned left in front of the right aligned value in the last column
+		type footerRow struct {
+			label string
+			value string
+		}
+		var footerRows []footerRow
+		var labelWidth, valueWidth int
+		if columns > 0 {
+			valueWidth = widths[columns-1]
+		}
+		for _, row := range table.Footer {
+			var cells []string
+			for _, cell := range row {
+				if cell != "" {
+					cells = append(cells, singleLine(cell))
+				}
+			}
+			if len(cells) == 0 {
+				footerRows = append(footerRows, footerRow{})
+				continue
+			}
+
+			footerRows = append(footerRows, footerRow{label: strings.Join(cells[:len(cells)-1], columnGap), value: cells[len(cells)-1]})
+			if width := utf8.RuneCountInString(footerRows[len(footerRows)-1].label); width > labelWidth {
+				labelWidth = width
+			}
+			if width := utf8.RuneCountInString(cells[len(cells)-1]); width > valueWidth {
+				valueWidth = width
+			}
+		}
+
+		var indent string
+		if indentWidth := tableWidth - valueWidth - labelWidth - len(columnGap); indentWidth > 0 {
+			indent = strings.Repeat(" ", indentWidth)
+		} else {
+			tableWidth = valueWidth + labelWidth + len(columnGap)
+		}
+
+		builder.WriteString(strings.Repeat("-", tableWidth))
+		builder.WriteString("\n")
+		for _, row := range footerRows {
+			builder.WriteString(indent + padText(row.label, labelWidth, false) + columnGap + padText(row.value, valueWidth, true))
+			builder.WriteString("\n")
+		}
+	}
+
+	return builder.String()
+}
+
+// padText fills text with spaces up to width runes.
+func padText(text string, width int, alignRight bool) string {
+	padding := width - utf8.RuneCountInString(text)
+	if padding <= 0 {
+		return text
+	}
+	if alignRight {
+		return strings.Repeat(" ", padding) + text
+	}
+	return text + strings.Repeat(" ", padding)
+}
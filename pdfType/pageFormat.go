@@ -0,0 +1,82 @@
+package pdfType
+
+import (
+	"SimpleInvoice/generator"
+	"SimpleInvoice/norms/letter"
+	dinA3 "SimpleInvoice/norms/paperSize/din-a3"
+	dinA4 "SimpleInvoice/norms/paperSize/din-a4"
+	dinA5 "SimpleInvoice/norms/paperSize/din-a5"
+	usLegal "SimpleInvoice/norms/paperSize/us-legal"
+	usLetter "SimpleInvoice/norms/paperSize/us-letter"
+	"fmt"
+	errorsWithStack "github.com/go-errors/errors"
+)
+
+// pageFormat is the paper size and orientation of a request.
+//
+// Size is one of the paperSizes or "custom" with Width and Height in mm.
+// If Size is empty, the paper size of the letter norm is used.
+//
+// Orientation is "portrait" (default) or "landscape".
+type pageFormat struct {
+	Size        string  `json:"size"`
+	Width       float64 `json:"width"`
+	Height      float64 `json:"height"`
+	Orientation string  `json:"orientation"`
+}
+
+// paperSizes contains all supported paper sizes in portrait orientation by the name used in a request.
+var paperSizes = map[string]generator.PageSize{
+	"a3":     {Width: dinA3.Width, Height: dinA3.Height},
+	"a4":     {Width: dinA4.Width, Height: dinA4.Height},
+	"a5":     {Width: dinA5.Width, Height: dinA5.Height},
+	"letter": {Width: usLetter.Width, Height: usLetter.Height},
+	"legal":  {Width: usLegal.Width, Height: usLegal.Height},
+}
+
+// pageOrientations maps the orientation of a request to generator.MetaData Orientation.
+var pageOrientations = map[string]string{
+	"":          "P",
+	"portrait":  "P",
+	"landscape": "L",
+}
+
+// validate checks the paper size and the orientation.
+func (format pageFormat) validate() error {
+	if _, ok := pageOrientations[format.Orientation]; !ok {
+		return errorsWithStack.New(fmt.Sprintf("page orientation \"%s\" is not supported, use \"portrait\" or \"landscape\"", format.Orientation))
+	}
+
+	switch format.Size {
+	case "":
+		return nil
+	case "custom":
+		if format.Width <= 0 || format.Height <= 0 {
+			return errorsWithStack.New(fmt.Sprintf("custom page size %.2f x %.2f mm is not allowed, width and height must be greater than 0", format.Width, format.Height))
+		}
+		return nil
+	}
+
+	if _, ok := paperSizes[format.Size]; !ok {
+		return errorsWithStack.New(fmt.Sprintf("page size \"%s\" is not supported, use \"a3\", \"a4\", \"a5\", \"letter\", \"legal\" or \"custom\"", format.Size))
+	}
+
+	return nil
+}
+
+// pageSize returns the paper size in portrait orientation, or the paper size of the letter norm.
+func (format pageFormat) pageSize(zones letter.Zones) generator.PageSize {
+	switch format.Size {
+	case "":
+		return generator.PageSize{Width: zones.PageWidth, Height: zones.PageHeight}
+	case "custom":
+		return generator.PageSize{Width: format.Width, Height: format.Height}
+	}
+
+	return paperSizes[format.Size]
+}
+
+// orientation returns the orientation for generator.MetaData.
+func (format pageFormat) orientation() string {
+	return pageOrientations[format.Orientation]
+}
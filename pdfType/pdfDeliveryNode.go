@@ -2,10 +2,11 @@ package pdfType
 
 import (
 	"SimpleInvoice/generator"
-	din5008a "SimpleInvoice/norms/letter/din-5008-a"
+	"SimpleInvoice/norms/letter"
 	"encoding/json"
+	"errors"
+	"fmt"
 	errorsWithStack "github.com/go-errors/errors"
-	"github.com/jung-kurt/gofpdf"
 	"github.com/rs/zerolog"
 	"io"
 	"net/http"
@@ -16,18 +17,30 @@ type DeliveryNode struct {
 	meta          PdfMeta
 	logger        *zerolog.Logger
 	printErrStack bool
-	pdfGen        *generator.PDFGenerator
+	pdfGen        generator.Generator
+	newGenerator  generator.NewGeneratorFunc
 	footerStartY  float64
+	norm          letter.Norm
+	debugOverlay  bool
+	report        letter.Report
 }
 
 type deliveryNodeRequestData struct {
-	SenderAddress   din5008a.FullAdresse `json:"senderAddress"`
-	ReceiverAddress din5008a.FullAdresse `json:"receiverAddress"`
-	SenderInfo      SenderInfo           `json:"senderInfo"`
+	LetterNorm      string             `json:"letterNorm"`
+	FoldMarks       bool               `json:"foldMarks"`
+	Letterhead      letterhead         `json:"letterhead"`
+	Theme           json.RawMessage    `json:"theme"`
+	Fonts           requestFonts       `json:"fonts"`
+	Images          requestImages      `json:"images"`
+	Watermarks      []watermark        `json:"watermarks"`
+	SenderAddress   letter.FullAdresse `json:"senderAddress"`
+	ReceiverAddress letter.FullAdresse `json:"receiverAddress"`
+	SenderInfo      SenderInfo         `json:"senderInfo"`
 	DeliveryMeta    struct {
 		DeliveryNodeNumber string `json:"deliveryNodeNumber"`
 		DeliveryDate       string `json:"deliveryDate"`
 		CustomerNumber     string `json:"customerNumber"`
+		Barcode            bool   `json:"barcode"`
 	} `json:"deliveryMeta"`
 	DeliveryNodeTexts struct {
 		OpeningText  string `json:"openingText"`
@@ -45,17 +58,11 @@ type deliveryNodeRequestData struct {
 
 func NewDeliveryNode(logger *zerolog.Logger) *DeliveryNode {
 	return &DeliveryNode{
-		data: deliveryNodeRequestData{},
-		meta: PdfMeta{
-			Font: pdfFont{
-				FontName:    "openSans",
-				SizeDefault: din5008a.FontSize10,
-				SizeSmall:   din5008a.FontSizeSender8,
-				SizeLarge:   din5008a.FontSize10 + 5,
-			},
-		},
+		data:          deliveryNodeRequestData{},
+		meta:          newPdfMeta(defaultTheme(), generator.DefaultFontRegistry),
 		logger:        logger,
 		printErrStack: logger.GetLevel() <= zerolog.DebugLevel,
+		newGenerator:  generator.NewPDFGeneratorBackend,
 	}
 }
 
@@ -67,10 +74,17 @@ func (d *DeliveryNode) SetDataFromRequest(request *http.Request) (err error) {
 		}
 	}(request.Body)
 
-	err = json.NewDecoder(request.Body).Decode(&d.data)
+	files, err := decodeRequestData(request, &d.data)
 	if err != nil {
 		return err
 	}
+	if letterheadPdf, ok := files["letterhead"]; ok {
+		d.data.Letterhead.Pdf = letterheadPdf
+	}
+	if err = d.data.Fonts.addFiles(files); err != nil {
+		return err
+	}
+	d.data.Images.addFiles(files)
 
 	err = d.validateData()
 	if err != nil {
@@ -83,7 +97,51 @@ func (d *DeliveryNode) SetDataFromRequest(request *http.Request) (err error) {
 
 func (d *DeliveryNode) validateData() (err error) {
 	//TODO implement
-	return err
+	if _, err = getLetterNorm(d.data.LetterNorm); err != nil {
+		return err
+	}
+
+	fonts, err := d.data.Fonts.registry()
+	if err != nil {
+		return err
+	}
+
+	theme, err := loadTheme(d.data.Theme, fonts)
+	if err != nil {
+		return err
+	}
+	d.meta = newPdfMeta(theme, fonts)
+
+	if err = d.data.SenderInfo.validateImages(d.data.Images); err != nil {
+		return err
+	}
+
+	if err = validateWatermarks(d.data.Watermarks, d.data.Images); err != nil {
+		return err
+	}
+
+	if d.data.DeliveryMeta.Barcode {
+		if err = generator.ValidateBarcode(generator.BarcodeCode128, d.data.DeliveryMeta.DeliveryNodeNumber); err != nil {
+			return errors.New(fmt.Sprintf("invalid deliveryNodeNumber barcode: %s", err.Error()))
+		}
+	}
+
+	return d.data.Letterhead.validate()
+}
+
+// SetGeneratorBackend replaces the default PDF backend, e.g. with generator.NewRecordingGenerator.
+func (d *DeliveryNode) SetGeneratorBackend(newGenerator generator.NewGeneratorFunc) {
+	d.newGenerator = newGenerator
+}
+
+// SetDebugOverlay enables drawing the zones of the letter norm and the envelope windows on the first page.
+func (d *DeliveryNode) SetDebugOverlay(enabled bool) {
+	d.debugOverlay = enabled
+}
+
+// ComplianceReport returns the zone overflows found by the last GeneratePDF.
+func (d *DeliveryNode) ComplianceReport() letter.Report {
+	return d.report
 }
 
 func (d *DeliveryNode) LogError(err error) {
@@ -98,21 +156,32 @@ func (d *DeliveryNode) LogError(err error) {
 	d.logger.Error().Msgf(errStr)
 }
 
-func (d *DeliveryNode) GeneratePDF() (*gofpdf.Fpdf, error) {
+func (d *DeliveryNode) GeneratePDF() (generator.Generator, error) {
 	d.logger.Debug().Msg("generate delivery node")
 
-	pdfGen, err := generator.NewPDFGenerator(
+	norm, err := getLetterNorm(d.data.LetterNorm)
+	if err != nil {
+		return nil, err
+	}
+	d.norm = norm
+
+	pdfGen, err := d.newGenerator(
 		generator.MetaData{
-			FontName:         "OpenSans",
-			FontGapY:         1.3,
-			FontSize:         d.meta.Font.SizeDefault,
-			MarginLeft:       din5008a.BodyStartX,
-			MarginTop:        din5008a.AddressSenderTextStartY,
-			MarginRight:      din5008a.Width - din5008a.BodyStopX,
-			MarginBottom:     0,
-			Unit:             "mm",
-			DefaultLineWidth: 0.4,
-			DefaultLineColor: generator.Color{R: 162, G: 162, B: 162},
+			FontName:          d.meta.Font.FontName,
+			FontRegistry:      d.meta.Fonts,
+			FallbackFontNames: d.meta.Theme.FallbackFonts,
+			FontGapY:          d.meta.Theme.LineSpacing,
+			FontSize:          d.meta.Font.SizeDefault,
+			MarginLeft:        d.norm.Zones().BodyStartX,
+			MarginTop:         d.norm.Zones().NextPageStartY,
+			MarginRight:       d.norm.Zones().PageWidth - d.norm.Zones().BodyStopX,
+			MarginBottom:      0,
+			Unit:              "mm",
+			DefaultLineWidth:  0.4,
+			DefaultLineColor:  generator.Color(d.meta.Theme.LineColor),
+			DefaultTextColor:  generator.Color(d.meta.Theme.TextColor),
+			TableStyle:        d.meta.Theme.tableStyle(),
+			PageSize:          generator.PageSize{Width: d.norm.Zones().PageWidth, Height: d.norm.Zones().PageHeight},
 		},
 		false,
 		d.logger,
@@ -129,46 +198,109 @@ func (d *DeliveryNode) GeneratePDF() (*gofpdf.Fpdf, error) {
 	}
 
 	d.pdfGen = pdfGen
+	d.data.Letterhead.register(d.pdfGen)
+	d.data.Images.register(d.pdfGen, append(d.data.SenderInfo.imageRefs(d.data.Letterhead), watermarkRefs(d.data.Watermarks)...)...)
 	d.pdfGen.NewPage()
 
 	d.doGeneratePdf()
 
-	return d.pdfGen.GetPdf(), d.pdfGen.GetError()
+	return d.pdfGen, d.pdfGen.GetError()
+}
+
+func (d *DeliveryNode) infoData() (infoData []letter.InfoData) {
+	infoData = append(infoData, letter.InfoData{Name: "Kundennummer:", Value: d.data.DeliveryMeta.CustomerNumber})
+	infoData = append(infoData, letter.InfoData{Name: "Liefernummer:", Value: d.data.DeliveryMeta.DeliveryNodeNumber})
+	infoData = append(infoData, letter.InfoData{Name: "Datum:", Value: d.data.DeliveryMeta.DeliveryDate})
+	return infoData
 }
 
 func (d *DeliveryNode) doGeneratePdf() {
-	var infoData []din5008a.InfoData
-	infoData = append(infoData, din5008a.InfoData{Name: "Kundennummer:", Value: d.data.DeliveryMeta.CustomerNumber})
-	infoData = append(infoData, din5008a.InfoData{Name: "Liefernummer:", Value: d.data.DeliveryMeta.DeliveryNodeNumber})
-	infoData = append(infoData, din5008a.InfoData{Name: "Datum:", Value: d.data.DeliveryMeta.DeliveryDate})
-
-	din5008a.FullAddressesAndInfoPart(d.pdfGen, d.data.SenderAddress, d.data.ReceiverAddress, infoData)
-
-	din5008a.Body(d.pdfGen, func() {
-		d.printHeadlineAndOpeningText()
-		d.printDeliveryTable()
-		d.printClosingText()
-		d.printSignatureSection()
+	d.report = checkCompliance(d.pdfGen, d.norm, letter.CheckContent{
+		SenderAddress:   d.data.SenderAddress,
+		ReceiverAddress: d.data.ReceiverAddress,
+		Info:            d.infoData(),
+		FooterColumns:   d.footerColumns(),
+	}, d.logger)
+
+	if d.data.FoldMarks {
+		d.norm.FoldMarks(d.pdfGen)
+	}
+
+	d.norm.FullAddressesAndInfoPart(d.pdfGen, d.data.SenderAddress, d.data.ReceiverAddress, d.infoData())
+	if d.data.DeliveryMeta.Barcode {
+		letter.PrintMetaInfoBarcode(d.pdfGen, d.norm.Zones(), len(d.infoData()), d.data.DeliveryMeta.DeliveryNodeNumber)
+	}
+
+	d.norm.Body(d.pdfGen, func() {
+		d.pdfGen.RenderBoxes(generator.Frame{
+			StartX:         d.norm.Zones().BodyStartX,
+			Width:          d.norm.Zones().BodyStopX - d.norm.Zones().BodyStartX,
+			NextPageStartY: d.norm.Zones().NextPageStartY,
+			StopY:          d.norm.BodyStopY(deliveryNoteFooterLines),
+		}, d.bodyBoxes())
 	})
 
-	din5008a.PageNumbering(d.pdfGen, d.footerStartY)
+	printInColor(d.pdfGen, d.meta.Theme.FooterColor, func() {
+		d.norm.PageNumbering(d.pdfGen, d.footerStartY)
+	})
+
+	printWatermarks(d.pdfGen, d.norm, d.data.Watermarks)
+
+	if d.debugOverlay {
+		printDebugOverlay(d.pdfGen, d.norm, d.logger)
+	}
 }
 
-func (d *DeliveryNode) printHeadlineAndOpeningText() {
+// bodyBoxes describes the delivery node body as layout boxes.
+func (d *DeliveryNode) bodyBoxes() (boxes []generator.Box) {
+	var newLine = generator.SpacerBox{Height: d.pdfGen.GetLineHeight(d.meta.Font.SizeDefault, d.meta.Font.LineGap)}
+	var brandColor = generator.Color(d.meta.Theme.BrandColor)
+
 	//Überschrift
-	d.pdfGen.SetFontSize(d.meta.Font.SizeLarge)
-	d.pdfGen.PrintLnPdfText(d.data.DeliveryNodeTexts.HeadlineText+" "+d.data.DeliveryMeta.DeliveryNodeNumber, "b", "L")
+	boxes = append(boxes, generator.ParagraphBox{
+		BoxConstraints: generator.BoxConstraints{KeepWithNext: true},
+		Text:           d.data.DeliveryNodeTexts.HeadlineText + " " + d.data.DeliveryMeta.DeliveryNodeNumber,
+		StyleStr:       "b",
+		AlignStr:       "L",
+		FontSize:       d.meta.Font.SizeLarge,
+		FontGapY:       d.meta.Font.LineGap,
+		TextColor:      &brandColor,
+	})
 
 	//opening
-	d.pdfGen.SetFontSize(din5008a.FontSize10)
-	d.pdfGen.SetFontGapY(din5008a.FontGab10)
-	d.pdfGen.NewLine(din5008a.BodyStartX)
-	d.pdfGen.PrintLnPdfText(d.data.DeliveryNodeTexts.OpeningText, "", "L")
-}
+	boxes = append(boxes, newLine)
+	boxes = append(boxes, generator.ParagraphBox{
+		Text:     d.data.DeliveryNodeTexts.OpeningText,
+		AlignStr: "L",
+		FontSize: d.meta.Font.SizeDefault,
+		FontGapY: d.meta.Font.LineGap,
+	})
+
+	boxes = append(boxes, newLine)
+	boxes = append(boxes, d.deliveryTableBox())
 
-func (d *DeliveryNode) printDeliveryTable() {
-	var items = [][]string{{}}
+	//closing
+	boxes = append(boxes, newLine)
+	boxes = append(boxes, generator.ParagraphBox{
+		Text:     d.data.DeliveryNodeTexts.Agb,
+		AlignStr: "L",
+		FontSize: d.meta.Font.SizeDefault,
+		FontGapY: d.meta.Font.LineGap,
+	})
+	boxes = append(boxes, newLine)
+	boxes = append(boxes, generator.ParagraphBox{
+		Text:     d.data.DeliveryNodeTexts.ClosingText,
+		AlignStr: "L",
+		FontSize: d.meta.Font.SizeDefault,
+		FontGapY: d.meta.Font.LineGap,
+	})
 
+	boxes = append(boxes, d.signatureSectionBox())
+
+	return boxes
+}
+
+func (d *DeliveryNode) deliveryTable() (headerCells []string, items [][]string) {
 	for _, item := range d.data.DeliveryItems {
 		items = append(items,
 			[]string{
@@ -180,38 +312,28 @@ func (d *DeliveryNode) printDeliveryTable() {
 		)
 	}
 
-	var headerCells = []string{"Pos", "Anzahl", "Beschreibung", "Notiz"}
-	var columnPercent = []float64{7, 18, 40, 35}
-	var columnWidth = getColumnWithFromPercentage(d.pdfGen, columnPercent)
-	var headerCellAlign = []string{"LM", "LM", "LM", "LM"}
-	var bodyCellAlign = []string{"LM", "LM", "LM", "LM"}
+	return []string{"Pos", "Anzahl", "Beschreibung", "Notiz"}, items
+}
 
-	d.pdfGen.NewLine(din5008a.BodyStartX)
-	d.pdfGen.SetFontSize(din5008a.FontSize10)
-	d.pdfGen.SetFontGapY(din5008a.FontGab10)
-	d.pdfGen.PrintTableHeader(headerCells, columnWidth, headerCellAlign)
-	d.pdfGen.PrintTableBody(items, columnWidth, bodyCellAlign)
+func (d *DeliveryNode) deliveryTableBox() generator.TableBox {
+	headerCells, items := d.deliveryTable()
 
-}
+	var columnPercent = []float64{7, 18, 40, 35}
 
-func (d *DeliveryNode) printClosingText() {
-	d.pdfGen.SetFontSize(din5008a.FontSize10)
-	d.pdfGen.SetFontGapY(din5008a.FontGab10)
-	d.pdfGen.NewLine(din5008a.BodyStartX)
-	d.pdfGen.PrintLnPdfText(d.data.DeliveryNodeTexts.Agb, "", "L")
-	d.pdfGen.NewLine(din5008a.BodyStartX)
-	d.pdfGen.PrintLnPdfText(d.data.DeliveryNodeTexts.ClosingText, "", "L")
+	// the empty first row is not printed, it starts the striped rows (see PrintTableBody) with the first item
+	return generator.TableBox{
+		Header:       headerCells,
+		Body:         append([][]string{{}}, items...),
+		ColumnWidths: getColumnWithFromPercentage(d.pdfGen, columnPercent),
+		HeaderAlign:  []string{"LM", "LM", "LM", "LM"},
+		BodyAlign:    []string{"LM", "LM", "LM", "LM"},
+		FontSize:     d.meta.Font.SizeDefault,
+		FontGapY:     d.meta.Font.LineGap,
+	}
 }
 
-func (d *DeliveryNode) printSignatureSection() {
-	const signatureHeight = 50
-	d.pdfGen.NewLine(din5008a.BodyStartX)
-	d.pdfGen.NewLine(din5008a.BodyStartX)
-	d.pdfGen.NewLine(din5008a.BodyStartX)
-	_, y := d.pdfGen.GetCursor()
-	var startSignatureSectionOnPosY = y //230
-	var startSupplierX = din5008a.BodyStartX
-	var startCustomerX = ((din5008a.BodyStopX - din5008a.BodyStartX) / 2) + din5008a.BodyStartX
+// signatureSectionBox returns the signature section of supplier and customer.
+func (d *DeliveryNode) signatureSectionBox() generator.GroupBox {
 	var senderSignatureName string
 
 	if d.data.SenderAddress.CompanyName != "" {
@@ -220,54 +342,26 @@ func (d *DeliveryNode) printSignatureSection() {
 		senderSignatureName = "Lieferant"
 	}
 
-	d.printSignaturePart(senderSignatureName, startSupplierX, startSignatureSectionOnPosY)
-	d.printSignaturePart("Kunde", startCustomerX, startSignatureSectionOnPosY)
-
+	return signatureSectionBox(d.pdfGen, []string{senderSignatureName, "Kunde"}, d.meta.Font)
 }
 
-func (d *DeliveryNode) printSignaturePart(headText string, startX float64, startY float64) {
-	const contentWidth = (din5008a.BodyStopX - din5008a.BodyStartX) / 2
-	const marginLeft = 22.5
-	const nameLength = contentWidth - marginLeft
-	const dateLength = nameLength / 3
-	const gabLength = 5
-	const signatureLength = (nameLength/3)*2 - gabLength
-
-	var cY float64
-
-	// name
-	d.pdfGen.SetCursor(startX, startY)
-	d.pdfGen.DrawLine(startX, startY, startX+nameLength, startY)
-	_, cY = d.pdfGen.GetCursor()
-	d.pdfGen.SetCursor(startX, cY+1)
-	d.pdfGen.SetFontSize(d.meta.Font.SizeSmall)
-	d.pdfGen.PrintPdfText(headText, "b", "L")
-	d.pdfGen.PrintLnPdfText("(Name)", "", "L")
-
-	d.pdfGen.SetFontSize(d.meta.Font.SizeDefault)
-	d.pdfGen.NewLine(startX)
-	d.pdfGen.NewLine(startX)
-	d.pdfGen.NewLine(startX)
-
-	//date & signature
-	_, cY = d.pdfGen.GetCursor()
-	var dateEndX = startX + dateLength
-	var signatureStartX = startX + dateLength + gabLength
-	var signatureEndX = startX + dateLength + gabLength + signatureLength
-	d.pdfGen.DrawLine(startX, cY, dateEndX, cY)
-	d.pdfGen.DrawLine(signatureStartX, cY, signatureEndX, cY)
-	_, cY = d.pdfGen.GetCursor()
-	d.pdfGen.SetCursor(startX, cY+1)
-	d.pdfGen.SetFontSize(d.meta.Font.SizeSmall)
-	d.pdfGen.PrintPdfText("Datum", "", "L")
-	_, cY = d.pdfGen.GetCursor()
-	d.pdfGen.SetCursor(signatureStartX, cY)
-	d.pdfGen.PrintPdfText("Unterschrift", "", "L")
-	d.pdfGen.SetFontSize(din5008a.FontGab10)
-}
+// deliveryNoteFooterLines is the number of footer lines, the single line with web, phone and email
+// is printed in the middle of them, see printFooterContent.
+const deliveryNoteFooterLines = 3
 
 func (d *DeliveryNode) printFooter() {
-	footerStartY, err := din5008a.Footer(d.printFooterContent, d.pdfGen)
+	if d.data.Letterhead.HideFooter {
+		// the footer is part of the letterhead, the page numbers are printed above its position
+		d.footerStartY = letter.FooterStartY(d.norm.Zones(), deliveryNoteFooterLines)
+		d.data.SenderInfo.printBadge(d.pdfGen, d.norm, d.footerStartY)
+		return
+	}
+
+	var footerStartY float64
+	var err error
+	printInColor(d.pdfGen, d.meta.Theme.FooterColor, func() {
+		footerStartY, err = d.norm.Footer(d.printFooterContent, d.pdfGen)
+	})
 
 	if err != nil {
 		d.pdfGen.SetError(err)
@@ -276,30 +370,32 @@ func (d *DeliveryNode) printFooter() {
 	if d.footerStartY == 0 {
 		d.footerStartY = footerStartY
 	}
+
+	d.data.SenderInfo.printBadge(d.pdfGen, d.norm, footerStartY)
 }
 
 func (d *DeliveryNode) printFooterContent(maxFooterHeight float64) (footerStartY float64) {
 	// calculate height
 	var currentStartX float64
 	var currentY float64
-	d.pdfGen.SetUnsafeCursor(din5008a.BodyStartX, maxFooterHeight)
-	d.pdfGen.PreviousLine(din5008a.BodyStartX)
-	d.pdfGen.PreviousLine(din5008a.BodyStartX)
-	d.pdfGen.PreviousLine(din5008a.BodyStartX)
+	d.pdfGen.SetUnsafeCursor(d.norm.Zones().BodyStartX, maxFooterHeight)
+	for line := 0; line < deliveryNoteFooterLines; line++ {
+		d.pdfGen.PreviousLine(d.norm.Zones().BodyStartX)
+	}
 	_, currentY = d.pdfGen.GetCursor()
 	footerStartY = currentY
 
-	currentStartX = din5008a.BodyStartX
+	currentStartX = d.norm.Zones().BodyStartX
 	d.pdfGen.SetCursor(currentStartX, footerStartY)
 	d.pdfGen.NewLine(currentStartX)
 	d.pdfGen.PrintPdfText(d.data.SenderInfo.Web, "", "L")
 
-	currentStartX = ((din5008a.BodyStopX - din5008a.BodyStartX) / 2) + din5008a.BodyStartX
+	currentStartX = ((d.norm.Zones().BodyStopX - d.norm.Zones().BodyStartX) / 2) + d.norm.Zones().BodyStartX
 	d.pdfGen.SetCursor(currentStartX, footerStartY)
 	d.pdfGen.NewLine(currentStartX)
 	d.pdfGen.PrintPdfText(d.data.SenderInfo.Phone, "", "C")
 
-	currentStartX = din5008a.BodyStopX
+	currentStartX = d.norm.Zones().BodyStopX
 	d.pdfGen.SetCursor(currentStartX, footerStartY)
 	d.pdfGen.NewLine(currentStartX)
 	d.pdfGen.PrintPdfText(d.data.SenderInfo.Email, "", "R")
@@ -308,7 +404,57 @@ func (d *DeliveryNode) printFooterContent(maxFooterHeight float64) (footerStartY
 }
 
 func (d *DeliveryNode) printHeader() {
-	if d.data.SenderInfo.MimeLogoUrl != "" {
-		din5008a.MimeImageHeader(d.pdfGen, d.data.SenderInfo.MimeLogoUrl)
+	d.data.Letterhead.printBackground(d.pdfGen)
+
+	if d.pdfGen.GetCurrentPageNumber() > 1 {
+		printInColor(d.pdfGen, d.meta.Theme.HeaderColor, func() {
+			d.norm.FollowUpHeader(d.pdfGen, letter.FollowUpHeader{
+				Sender:   letter.ShortName(d.data.SenderAddress),
+				Receiver: letter.ShortName(d.data.ReceiverAddress),
+				Info: []letter.InfoData{
+					{Name: "Liefernummer:", Value: d.data.DeliveryMeta.DeliveryNodeNumber},
+					{Name: "Datum:", Value: d.data.DeliveryMeta.DeliveryDate},
+				},
+			})
+		})
 	}
+
+	d.data.SenderInfo.printLogo(d.pdfGen, d.norm, d.data.Letterhead)
+}
+
+// RenderHTML writes the delivery node as HTML e-mail body to w.
+func (d *DeliveryNode) RenderHTML(w io.Writer) error {
+	return renderMailHTML(w, d.mailView())
+}
+
+// RenderText writes the delivery node as plain text e-mail body to w.
+func (d *DeliveryNode) RenderText(w io.Writer) error {
+	return renderMailText(w, d.mailView())
+}
+
+// mailView shows the same content as the PDF, without the signature section.
+func (d *DeliveryNode) mailView() mailView {
+	view := newMailView(d.data.SenderAddress, d.data.ReceiverAddress, d.infoData())
+	view.Headline = d.data.DeliveryNodeTexts.HeadlineText + " " + d.data.DeliveryMeta.DeliveryNodeNumber
+
+	view.addText(d.data.DeliveryNodeTexts.OpeningText)
+
+	headerCells, items := d.deliveryTable()
+	view.addTable(mailTable{Header: headerCells, Rows: items})
+
+	view.addText(d.data.DeliveryNodeTexts.Agb)
+	view.addText(d.data.DeliveryNodeTexts.ClosingText)
+
+	view.FooterColumns = d.footerColumns()
+
+	return view
+}
+
+// footerColumns returns the non-empty lines of the footer columns, see printFooterContent.
+func (d *DeliveryNode) footerColumns() [][]string {
+	return footerColumns(
+		[]string{d.data.SenderInfo.Web},
+		[]string{d.data.SenderInfo.Phone},
+		[]string{d.data.SenderInfo.Email},
+	)
 }
@@ -2,11 +2,10 @@ package pdfType
 
 import (
 	"SimpleInvoice/generator"
-	din5008a "SimpleInvoice/norms/letter/din-5008-a"
+	"SimpleInvoice/norms/letter"
 	"encoding/json"
 	"fmt"
 	errorsWithStack "github.com/go-errors/errors"
-	"github.com/jung-kurt/gofpdf"
 	"github.com/rs/zerolog"
 	"io"
 	"net/http"
@@ -18,14 +17,25 @@ type Invoice struct {
 	meta          PdfMeta
 	logger        *zerolog.Logger
 	printErrStack bool
-	pdfGen        *generator.PDFGenerator
+	pdfGen        generator.Generator
+	newGenerator  generator.NewGeneratorFunc
 	footerStartY  float64
+	norm          letter.Norm
+	debugOverlay  bool
+	report        letter.Report
 }
 
 type invoiceRequestData struct {
-	SenderAddress   din5008a.FullAdresse `json:"senderAddress"`
-	ReceiverAddress din5008a.FullAdresse `json:"receiverAddress"`
-	SenderInfo      SenderInfo           `json:"senderInfo"`
+	LetterNorm      string             `json:"letterNorm"`
+	FoldMarks       bool               `json:"foldMarks"`
+	Letterhead      letterhead         `json:"letterhead"`
+	Theme           json.RawMessage    `json:"theme"`
+	Fonts           requestFonts       `json:"fonts"`
+	Images          requestImages      `json:"images"`
+	Watermarks      []watermark        `json:"watermarks"`
+	SenderAddress   letter.FullAdresse `json:"senderAddress"`
+	ReceiverAddress letter.FullAdresse `json:"receiverAddress"`
+	SenderInfo      SenderInfo         `json:"senderInfo"`
 	InvoiceMeta     struct {
 		InvoiceNumber  string            `json:"invoiceNumber"`
 		InvoiceDate    string            `json:"invoiceDate"`
@@ -57,17 +67,11 @@ type CustomMetaDatum struct {
 
 func NewInvoice(logger *zerolog.Logger) *Invoice {
 	return &Invoice{
-		data: invoiceRequestData{},
-		meta: PdfMeta{
-			Font: pdfFont{
-				FontName:    "openSans",
-				SizeDefault: din5008a.FontSize10,
-				SizeSmall:   din5008a.FontSizeSender8,
-				SizeLarge:   din5008a.FontSize10 + 5,
-			},
-		},
+		data:          invoiceRequestData{},
+		meta:          newPdfMeta(defaultTheme(), generator.DefaultFontRegistry),
 		logger:        logger,
 		printErrStack: logger.GetLevel() <= zerolog.DebugLevel,
+		newGenerator:  generator.NewPDFGeneratorBackend,
 	}
 }
 
@@ -79,10 +83,17 @@ func (i *Invoice) SetDataFromRequest(request *http.Request) (err error) {
 		}
 	}(request.Body)
 
-	err = json.NewDecoder(request.Body).Decode(&i.data)
+	files, err := decodeRequestData(request, &i.data)
 	if err != nil {
 		return err
 	}
+	if letterheadPdf, ok := files["letterhead"]; ok {
+		i.data.Letterhead.Pdf = letterheadPdf
+	}
+	if err = i.data.Fonts.addFiles(files); err != nil {
+		return err
+	}
+	i.data.Images.addFiles(files)
 
 	err = i.validateData()
 	if err != nil {
@@ -95,7 +106,45 @@ func (i *Invoice) SetDataFromRequest(request *http.Request) (err error) {
 
 func (i *Invoice) validateData() (err error) {
 	//TODO implement
-	return err
+	if _, err = getLetterNorm(i.data.LetterNorm); err != nil {
+		return err
+	}
+
+	fonts, err := i.data.Fonts.registry()
+	if err != nil {
+		return err
+	}
+
+	theme, err := loadTheme(i.data.Theme, fonts)
+	if err != nil {
+		return err
+	}
+	i.meta = newPdfMeta(theme, fonts)
+
+	if err = i.data.SenderInfo.validateImages(i.data.Images); err != nil {
+		return err
+	}
+
+	if err = validateWatermarks(i.data.Watermarks, i.data.Images); err != nil {
+		return err
+	}
+
+	return i.data.Letterhead.validate()
+}
+
+// SetGeneratorBackend replaces the default PDF backend, e.g. with generator.NewRecordingGenerator.
+func (i *Invoice) SetGeneratorBackend(newGenerator generator.NewGeneratorFunc) {
+	i.newGenerator = newGenerator
+}
+
+// SetDebugOverlay enables drawing the zones of the letter norm and the envelope windows on the first page.
+func (i *Invoice) SetDebugOverlay(enabled bool) {
+	i.debugOverlay = enabled
+}
+
+// ComplianceReport returns the zone overflows found by the last GeneratePDF.
+func (i *Invoice) ComplianceReport() letter.Report {
+	return i.report
 }
 
 func (i *Invoice) LogError(err error) {
@@ -110,21 +159,32 @@ func (i *Invoice) LogError(err error) {
 	i.logger.Error().Msgf(errStr)
 }
 
-func (i *Invoice) GeneratePDF() (*gofpdf.Fpdf, error) {
+func (i *Invoice) GeneratePDF() (generator.Generator, error) {
 	i.logger.Debug().Msg("generate invoice")
 
-	pdfGen, err := generator.NewPDFGenerator(
+	norm, err := getLetterNorm(i.data.LetterNorm)
+	if err != nil {
+		return nil, err
+	}
+	i.norm = norm
+
+	pdfGen, err := i.newGenerator(
 		generator.MetaData{
-			FontName:         "OpenSans",
-			FontGapY:         1.3,
-			FontSize:         i.meta.Font.SizeDefault,
-			MarginLeft:       din5008a.BodyStartX,
-			MarginTop:        din5008a.AddressSenderTextStartY,
-			MarginRight:      din5008a.Width - din5008a.BodyStopX,
-			MarginBottom:     0,
-			Unit:             "mm",
-			DefaultLineWidth: 0.4,
-			DefaultLineColor: generator.Color{R: 162, G: 162, B: 162},
+			FontName:          i.meta.Font.FontName,
+			FontRegistry:      i.meta.Fonts,
+			FallbackFontNames: i.meta.Theme.FallbackFonts,
+			FontGapY:          i.meta.Theme.LineSpacing,
+			FontSize:          i.meta.Font.SizeDefault,
+			MarginLeft:        i.norm.Zones().BodyStartX,
+			MarginTop:         i.norm.Zones().NextPageStartY,
+			MarginRight:       i.norm.Zones().PageWidth - i.norm.Zones().BodyStopX,
+			MarginBottom:      0,
+			Unit:              "mm",
+			DefaultLineWidth:  0.4,
+			DefaultLineColor:  generator.Color(i.meta.Theme.LineColor),
+			DefaultTextColor:  generator.Color(i.meta.Theme.TextColor),
+			TableStyle:        i.meta.Theme.tableStyle(),
+			PageSize:          generator.PageSize{Width: i.norm.Zones().PageWidth, Height: i.norm.Zones().PageHeight},
 		},
 		false,
 		i.logger,
@@ -141,49 +201,118 @@ func (i *Invoice) GeneratePDF() (*gofpdf.Fpdf, error) {
 	}
 
 	i.pdfGen = pdfGen
+	i.data.Letterhead.register(i.pdfGen)
+	i.data.Images.register(i.pdfGen, append(i.data.SenderInfo.imageRefs(i.data.Letterhead), watermarkRefs(i.data.Watermarks)...)...)
 	i.pdfGen.NewPage()
 
 	i.doGeneratePdf()
 
-	return pdfGen.GetPdf(), pdfGen.GetError()
+	return i.pdfGen, i.pdfGen.GetError()
 }
 
-func (i *Invoice) doGeneratePdf() {
-	var infoData []din5008a.InfoData
-	infoData = append(infoData, din5008a.InfoData{Name: "Kundennummer:", Value: i.data.InvoiceMeta.CustomerNumber})
-	infoData = append(infoData, din5008a.InfoData{Name: "Rechnungsnummer:", Value: i.data.InvoiceMeta.InvoiceNumber})
-	infoData = append(infoData, din5008a.InfoData{Name: "Datum:", Value: i.data.InvoiceMeta.InvoiceDate})
+func (i *Invoice) infoData() (infoData []letter.InfoData) {
+	infoData = append(infoData, letter.InfoData{Name: "Kundennummer:", Value: i.data.InvoiceMeta.CustomerNumber})
+	infoData = append(infoData, letter.InfoData{Name: "Rechnungsnummer:", Value: i.data.InvoiceMeta.InvoiceNumber})
+	infoData = append(infoData, letter.InfoData{Name: "Datum:", Value: i.data.InvoiceMeta.InvoiceDate})
 	//TODO check length and throw error, if over din norm
 	for _, datum := range i.data.InvoiceMeta.CustomMetaData {
-		infoData = append(infoData, din5008a.InfoData{Name: datum.Name, Value: datum.Value})
+		infoData = append(infoData, letter.InfoData{Name: datum.Name, Value: datum.Value})
+	}
+	return infoData
+}
+
+func (i *Invoice) doGeneratePdf() {
+	i.report = checkCompliance(i.pdfGen, i.norm, letter.CheckContent{
+		SenderAddress:   i.data.SenderAddress,
+		ReceiverAddress: i.data.ReceiverAddress,
+		Info:            i.infoData(),
+		FooterColumns:   i.footerColumns(),
+	}, i.logger)
+
+	if i.data.FoldMarks {
+		i.norm.FoldMarks(i.pdfGen)
 	}
 
-	din5008a.FullAddressesAndInfoPart(i.pdfGen, i.data.SenderAddress, i.data.ReceiverAddress, infoData)
+	i.norm.FullAddressesAndInfoPart(i.pdfGen, i.data.SenderAddress, i.data.ReceiverAddress, i.infoData())
 
-	din5008a.Body(i.pdfGen, func() {
-		i.printHeadlineAndOpeningText()
-		i.printInvoiceTable()
-		i.printClosingText()
+	i.norm.Body(i.pdfGen, func() {
+		i.pdfGen.RenderBoxes(generator.Frame{
+			StartX:         i.norm.Zones().BodyStartX,
+			Width:          i.norm.Zones().BodyStopX - i.norm.Zones().BodyStartX,
+			NextPageStartY: i.norm.Zones().NextPageStartY,
+			StopY:          i.norm.BodyStopY(invoiceFooterLines),
+		}, i.bodyBoxes())
 	})
 
-	din5008a.PageNumbering(i.pdfGen, i.footerStartY)
+	printInColor(i.pdfGen, i.meta.Theme.FooterColor, func() {
+		i.norm.PageNumbering(i.pdfGen, i.footerStartY)
+	})
+
+	printWatermarks(i.pdfGen, i.norm, i.data.Watermarks)
+
+	if i.debugOverlay {
+		printDebugOverlay(i.pdfGen, i.norm, i.logger)
+	}
 }
 
-func (i *Invoice) printHeadlineAndOpeningText() {
+// bodyBoxes describes the invoice body as layout boxes.
+func (i *Invoice) bodyBoxes() (boxes []generator.Box) {
+	var newLine = generator.SpacerBox{Height: i.pdfGen.GetLineHeight(i.meta.Font.SizeDefault, i.meta.Font.LineGap)}
+	var brandColor = generator.Color(i.meta.Theme.BrandColor)
+
 	//Überschrift
-	i.pdfGen.SetFontSize(i.meta.Font.SizeLarge)
-	i.pdfGen.PrintLnPdfText(i.data.InvoiceBody.HeadlineText+" "+i.data.InvoiceMeta.InvoiceNumber, "b", "L")
+	boxes = append(boxes, generator.ParagraphBox{
+		BoxConstraints: generator.BoxConstraints{KeepWithNext: true},
+		Text:           i.data.InvoiceBody.HeadlineText + " " + i.data.InvoiceMeta.InvoiceNumber,
+		StyleStr:       "b",
+		AlignStr:       "L",
+		FontSize:       i.meta.Font.SizeLarge,
+		FontGapY:       i.meta.Font.LineGap,
+		TextColor:      &brandColor,
+	})
 
 	//opening
-	i.pdfGen.SetFontSize(din5008a.FontSize10)
-	i.pdfGen.SetFontGapY(din5008a.FontGab10)
-	i.pdfGen.NewLine(din5008a.BodyStartX)
-	i.pdfGen.PrintLnPdfText(i.data.InvoiceBody.OpeningText, "", "L")
-}
+	boxes = append(boxes, newLine)
+	boxes = append(boxes, generator.ParagraphBox{
+		Text:     i.data.InvoiceBody.OpeningText,
+		AlignStr: "L",
+		FontSize: i.meta.Font.SizeDefault,
+		FontGapY: i.meta.Font.LineGap,
+	})
+
+	//the service time belongs to the first rows of the table
+	boxes = append(boxes, newLine)
+	boxes = append(boxes, generator.ParagraphBox{
+		BoxConstraints: generator.BoxConstraints{KeepWithNext: true},
+		Text:           i.data.InvoiceBody.ServiceTimeText,
+		StyleStr:       "i",
+		AlignStr:       "L",
+		FontSize:       i.meta.Font.SizeSmall,
+		FontGapY:       i.meta.Font.LineGap,
+	})
+	boxes = append(boxes, i.invoiceTableBox())
+
+	//closing
+	boxes = append(boxes, generator.SpacerBox{Height: 3 * newLine.Height})
+	boxes = append(boxes, generator.ParagraphBox{
+		Text:     i.data.InvoiceBody.ClosingText,
+		AlignStr: "L",
+		FontSize: i.meta.Font.SizeDefault,
+		FontGapY: i.meta.Font.LineGap,
+	})
+	boxes = append(boxes, generator.SpacerBox{Height: 2 * newLine.Height})
+	boxes = append(boxes, generator.ParagraphBox{
+		Text:     i.data.InvoiceBody.UstNotice,
+		AlignStr: "L",
+		FontSize: i.meta.Font.SizeDefault,
+		FontGapY: i.meta.Font.LineGap,
+	})
 
-func (i *Invoice) printInvoiceTable() {
-	var invoicedItems = [][]string{{}}
+	return boxes
+}
 
+// invoiceTable computes the invoice item rows and the summary rows with the net sum, each tax rate and the total sum.
+func (i *Invoice) invoiceTable() (headerCells []string, invoicedItems [][]string, summaryCells [][]string) {
 	type taxSumType struct {
 		taxName string
 		taxSum  float64
@@ -220,13 +349,9 @@ func (i *Invoice) printInvoiceTable() {
 			})
 	}
 
-	var headerCells = []string{"Pos", "Anzahl", "Preis", "Beschreibung", "USt", "Netto"}
-	var columnPercent = []float64{6, 10, 10, 54, 8, 12}
-	var columnWidth = getColumnWithFromPercentage(i.pdfGen, columnPercent)
+	headerCells = []string{"Pos", "Anzahl", "Preis", "Beschreibung", "USt", "Netto"}
 
-	var headerCellAlign = []string{"LM", "LM", "LM", "LM", "RM", "RM"}
-	var bodyCellAlign = []string{"LM", "LM", "LM", "LM", "RM", "RM"}
-	var summaryCells = [][]string{
+	summaryCells = [][]string{
 		{"", "Zwischensumme", germanNumber(netSum) + "€"},
 	}
 	//summaryCells append taxSums
@@ -245,33 +370,46 @@ func (i *Invoice) printInvoiceTable() {
 	//add last row with total sum, calculated from netSum plus each taxSum
 	summaryCells = append(summaryCells, []string{"", "Gesamtbetrag", germanNumber(totalTax+netSum) + "€"})
 
-	var summaryColumnPercent = []float64{60, 25, 15}
-	var summaryColumnWidths = getColumnWithFromPercentage(i.pdfGen, summaryColumnPercent)
-	var summaryCellAlign = []string{"LM", "LM", "RM"}
+	return headerCells, invoicedItems, summaryCells
+}
 
-	i.pdfGen.NewLine(din5008a.BodyStartX)
-	i.pdfGen.SetFontSize(i.meta.Font.SizeSmall)
-	i.pdfGen.PrintLnPdfText(i.data.InvoiceBody.ServiceTimeText, "i", "L")
-	i.pdfGen.SetFontSize(i.meta.Font.SizeDefault)
+func (i *Invoice) invoiceTableBox() generator.TableBox {
+	headerCells, invoicedItems, summaryCells := i.invoiceTable()
 
-	i.pdfGen.PrintTableHeader(headerCells, columnWidth, headerCellAlign)
-	i.pdfGen.PrintTableBody(invoicedItems, columnWidth, bodyCellAlign)
-	i.pdfGen.PrintTableFooter(summaryCells, summaryColumnWidths, summaryCellAlign)
-}
+	var columnPercent = []float64{6, 10, 10, 54, 8, 12}
+	var summaryColumnPercent = []float64{60, 25, 15}
 
-func (i *Invoice) printClosingText() {
-	i.pdfGen.SetFontSize(i.meta.Font.SizeDefault)
-	i.pdfGen.NewLine(din5008a.BodyStartX)
-	i.pdfGen.NewLine(din5008a.BodyStartX)
-	i.pdfGen.NewLine(din5008a.BodyStartX)
-	i.pdfGen.PrintLnPdfText(i.data.InvoiceBody.ClosingText, "", "L")
-	i.pdfGen.NewLine(din5008a.BodyStartX)
-	i.pdfGen.NewLine(din5008a.BodyStartX)
-	i.pdfGen.PrintLnPdfText(i.data.InvoiceBody.UstNotice, "", "L")
+	return generator.TableBox{
+		Header:             headerCells,
+		Body:               invoicedItems,
+		Footer:             summaryCells,
+		ColumnWidths:       getColumnWithFromPercentage(i.pdfGen, columnPercent),
+		FooterColumnWidths: getColumnWithFromPercentage(i.pdfGen, summaryColumnPercent),
+		HeaderAlign:        []string{"LM", "LM", "LM", "LM", "RM", "RM"},
+		BodyAlign:          []string{"LM", "LM", "LM", "LM", "RM", "RM"},
+		FooterAlign:        []string{"LM", "LM", "RM"},
+		FontSize:           i.meta.Font.SizeDefault,
+		FontGapY:           i.meta.Font.LineGap,
+	}
 }
 
+// invoiceFooterLines is the number of footer lines, the sender address column
+// with company, road, city and tax number is the highest column, see printFooterContent.
+const invoiceFooterLines = 4
+
 func (i *Invoice) printFooter() {
-	footerStartY, err := din5008a.Footer(i.printFooterContent, i.pdfGen)
+	if i.data.Letterhead.HideFooter {
+		// the footer is part of the letterhead, the page numbers are printed above its position
+		i.footerStartY = letter.FooterStartY(i.norm.Zones(), invoiceFooterLines)
+		i.data.SenderInfo.printBadge(i.pdfGen, i.norm, i.footerStartY)
+		return
+	}
+
+	var footerStartY float64
+	var err error
+	printInColor(i.pdfGen, i.meta.Theme.FooterColor, func() {
+		footerStartY, err = i.norm.Footer(i.printFooterContent, i.pdfGen)
+	})
 
 	if err != nil {
 		i.pdfGen.SetError(err)
@@ -280,34 +418,35 @@ func (i *Invoice) printFooter() {
 	if i.footerStartY == 0 {
 		i.footerStartY = footerStartY
 	}
+
+	i.data.SenderInfo.printBadge(i.pdfGen, i.norm, footerStartY)
 }
 
 func (i *Invoice) printFooterContent(maxFooterHeight float64) (footerStartY float64) {
 	// calculate height
 	var currentStartX float64
 	var currentY float64
-	i.pdfGen.SetUnsafeCursor(din5008a.BodyStartX, maxFooterHeight)
-	i.pdfGen.PreviousLine(0)
-	i.pdfGen.PreviousLine(0)
-	i.pdfGen.PreviousLine(0)
-	i.pdfGen.PreviousLine(0)
+	i.pdfGen.SetUnsafeCursor(i.norm.Zones().BodyStartX, maxFooterHeight)
+	for line := 0; line < invoiceFooterLines; line++ {
+		i.pdfGen.PreviousLine(0)
+	}
 	_, currentY = i.pdfGen.GetCursor()
 	footerStartY = currentY
 
-	currentStartX = din5008a.BodyStartX
+	currentStartX = i.norm.Zones().BodyStartX
 	i.pdfGen.SetCursor(currentStartX, footerStartY)
 	i.pdfGen.PrintLnPdfText(i.data.SenderInfo.Web, "", "L")
 	i.pdfGen.PrintLnPdfText(i.data.SenderInfo.Phone, "", "L")
 	i.pdfGen.PrintLnPdfText(i.data.SenderInfo.Email, "", "L")
 
-	currentStartX = ((din5008a.BodyStopX - din5008a.BodyStartX) / 2) + din5008a.BodyStartX
+	currentStartX = ((i.norm.Zones().BodyStopX - i.norm.Zones().BodyStartX) / 2) + i.norm.Zones().BodyStartX
 	i.pdfGen.SetCursor(currentStartX, footerStartY)
 	i.pdfGen.PrintLnPdfText(i.data.SenderAddress.CompanyName, "", "C")
 	i.pdfGen.PrintLnPdfText(fmt.Sprintf("%s %s", i.data.SenderAddress.Address.Road, i.data.SenderAddress.Address.HouseNumber), "", "C")
 	i.pdfGen.PrintLnPdfText(i.data.SenderAddress.Address.ZipCode+" "+i.data.SenderAddress.Address.CityName, "", "C")
 	i.pdfGen.PrintLnPdfText(i.data.SenderInfo.TaxNumber, "", "C")
 
-	currentStartX = din5008a.BodyStopX
+	currentStartX = i.norm.Zones().BodyStopX
 	i.pdfGen.SetCursor(currentStartX, footerStartY)
 	i.pdfGen.PrintLnPdfText(i.data.SenderInfo.BankName, "", "R")
 	i.pdfGen.PrintLnPdfText(i.data.SenderInfo.Iban, "", "R")
@@ -317,7 +456,72 @@ func (i *Invoice) printFooterContent(maxFooterHeight float64) (footerStartY floa
 }
 
 func (i *Invoice) printHeader() {
-	if i.data.SenderInfo.MimeLogoUrl != "" {
-		din5008a.MimeImageHeader(i.pdfGen, i.data.SenderInfo.MimeLogoUrl)
+	i.data.Letterhead.printBackground(i.pdfGen)
+
+	if i.pdfGen.GetCurrentPageNumber() > 1 {
+		printInColor(i.pdfGen, i.meta.Theme.HeaderColor, func() {
+			i.norm.FollowUpHeader(i.pdfGen, letter.FollowUpHeader{
+				Sender:   letter.ShortName(i.data.SenderAddress),
+				Receiver: letter.ShortName(i.data.ReceiverAddress),
+				Info: []letter.InfoData{
+					{Name: "Rechnungsnummer:", Value: i.data.InvoiceMeta.InvoiceNumber},
+					{Name: "Datum:", Value: i.data.InvoiceMeta.InvoiceDate},
+				},
+			})
+		})
+	}
+
+	i.data.SenderInfo.printLogo(i.pdfGen, i.norm, i.data.Letterhead)
+}
+
+// RenderHTML writes the invoice as HTML e-mail body to w.
+func (i *Invoice) RenderHTML(w io.Writer) error {
+	return renderMailHTML(w, i.mailView())
+}
+
+// RenderText writes the invoice as plain text e-mail body to w.
+func (i *Invoice) RenderText(w io.Writer) error {
+	return renderMailText(w, i.mailView())
+}
+
+// mailView shows the same content as the PDF.
+func (i *Invoice) mailView() mailView {
+	view := newMailView(i.data.SenderAddress, i.data.ReceiverAddress, i.infoData())
+	view.Headline = i.data.InvoiceBody.HeadlineText + " " + i.data.InvoiceMeta.InvoiceNumber
+
+	view.addText(i.data.InvoiceBody.OpeningText)
+	view.addSmallText(i.data.InvoiceBody.ServiceTimeText)
+
+	headerCells, invoicedItems, summaryCells := i.invoiceTable()
+	var summaryRows [][]string
+	for _, row := range summaryCells {
+		summaryRows = append(summaryRows, row[1:])
 	}
+	view.addTable(mailTable{
+		Header:       headerCells,
+		Rows:         invoicedItems,
+		Footer:       summaryRows,
+		RightAligned: []bool{false, false, false, false, true, true},
+	})
+
+	view.addText(i.data.InvoiceBody.ClosingText)
+	view.addText(i.data.InvoiceBody.UstNotice)
+
+	view.FooterColumns = i.footerColumns()
+
+	return view
+}
+
+// footerColumns returns the non-empty lines of the footer columns, see printFooterContent.
+func (i *Invoice) footerColumns() [][]string {
+	return footerColumns(
+		[]string{i.data.SenderInfo.Web, i.data.SenderInfo.Phone, i.data.SenderInfo.Email},
+		[]string{
+			i.data.SenderAddress.CompanyName,
+			fmt.Sprintf("%s %s", i.data.SenderAddress.Address.Road, i.data.SenderAddress.Address.HouseNumber),
+			i.data.SenderAddress.Address.ZipCode + " " + i.data.SenderAddress.Address.CityName,
+			i.data.SenderInfo.TaxNumber,
+		},
+		[]string{i.data.SenderInfo.BankName, i.data.SenderInfo.Iban, i.data.SenderInfo.Bic},
+	)
 }
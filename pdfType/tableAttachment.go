@@ -2,11 +2,11 @@ package pdfType
 
 import (
 	"SimpleInvoice/generator"
-	din5008a "SimpleInvoice/norms/letter/din-5008-a"
+	"SimpleInvoice/norms/letter"
 	"encoding/json"
 	"errors"
+	"fmt"
 	errorsWithStack "github.com/go-errors/errors"
-	"github.com/jung-kurt/gofpdf"
 	"github.com/rs/zerolog"
 	"io"
 	"net/http"
@@ -14,28 +14,39 @@ import (
 
 type TableAttachment struct {
 	data          tableAttachmentRequestData
+	meta          PdfMeta
 	logger        *zerolog.Logger
 	printErrStack bool
-	pdfGen        *generator.PDFGenerator
+	pdfGen        generator.Generator
+	newGenerator  generator.NewGeneratorFunc
 	footerStartY  float64
+	norm          letter.Norm
 }
 
 type tableAttachmentRequestData struct {
-	Headline          string     `json:"headline"`
-	TableInfo         string     `json:"tableInfo"`
-	TableHeader       []string   `json:"tableHeader"`
-	TableData         [][]string `json:"tableData"`
-	ColumnPercentages []float64  `json:"columnPercentages"`
-	PageNumberPrefix  string     `json:"pageNumberPrefix"`
+	LetterNorm        string          `json:"letterNorm"`
+	Headline          string          `json:"headline"`
+	TableInfo         string          `json:"tableInfo"`
+	TableHeader       []string        `json:"tableHeader"`
+	TableData         [][]string      `json:"tableData"`
+	ColumnPercentages []float64       `json:"columnPercentages"`
+	Charts            []tableChart    `json:"charts"`
+	PageNumberPrefix  string          `json:"pageNumberPrefix"`
+	Page              pageFormat      `json:"page"`
+	Theme             json.RawMessage `json:"theme"`
+	Fonts             requestFonts    `json:"fonts"`
+	Images            requestImages   `json:"images"`
+	Watermarks        []watermark     `json:"watermarks"`
 }
 
 func NewTableAttachment(logger *zerolog.Logger) *TableAttachment {
 	return &TableAttachment{
 		data:          tableAttachmentRequestData{},
+		meta:          newPdfMeta(defaultTheme(), generator.DefaultFontRegistry),
 		logger:        logger,
 		printErrStack: logger.GetLevel() <= zerolog.DebugLevel,
+		newGenerator:  generator.NewPDFGeneratorBackend,
 		pdfGen:        nil,
-		footerStartY:  din5008a.Height - 5,
 	}
 }
 
@@ -47,10 +58,14 @@ func (t *TableAttachment) SetDataFromRequest(request *http.Request) (err error)
 		}
 	}(request.Body)
 
-	err = json.NewDecoder(request.Body).Decode(&t.data)
+	files, err := decodeRequestData(request, &t.data)
 	if err != nil {
 		return err
 	}
+	if err = t.data.Fonts.addFiles(files); err != nil {
+		return err
+	}
+	t.data.Images.addFiles(files)
 
 	err = t.validateData()
 	if err != nil {
@@ -61,27 +76,39 @@ func (t *TableAttachment) SetDataFromRequest(request *http.Request) (err error)
 	return nil
 }
 
-func (t *TableAttachment) GeneratePDF() (*gofpdf.Fpdf, error) {
+func (t *TableAttachment) GeneratePDF() (generator.Generator, error) {
+
+	norm, err := getLetterNorm(t.data.LetterNorm)
+	if err != nil {
+		return nil, err
+	}
+	t.norm = norm
 
 	t.logger.Debug().Msg("generate table attachment")
 
-	pdfGen, err := generator.NewPDFGenerator(
+	pdfGen, err := t.newGenerator(
 		generator.MetaData{
-			FontName:         "OpenSans",
-			FontGapY:         1.3,
-			FontSize:         din5008a.FontSize10,
-			MarginLeft:       din5008a.BodyStartX,
-			MarginTop:        din5008a.AddressSenderTextStartY,
-			MarginRight:      din5008a.Width - din5008a.BodyStopX,
-			MarginBottom:     0,
-			Unit:             "mm",
-			DefaultLineWidth: 0.4,
-			DefaultLineColor: generator.Color{R: 162, G: 162, B: 162},
+			FontName:          t.meta.Font.FontName,
+			FontRegistry:      t.meta.Fonts,
+			FallbackFontNames: t.meta.Theme.FallbackFonts,
+			FontGapY:          t.meta.Theme.LineSpacing,
+			FontSize:          t.meta.Font.SizeDefault,
+			MarginLeft:        t.norm.Zones().BodyStartX,
+			MarginTop:         t.norm.Zones().NextPageStartY,
+			MarginRight:       t.norm.Zones().PageWidth - t.norm.Zones().BodyStopX,
+			MarginBottom:      0,
+			Unit:              "mm",
+			DefaultLineWidth:  0.4,
+			DefaultLineColor:  generator.Color(t.meta.Theme.LineColor),
+			DefaultTextColor:  generator.Color(t.meta.Theme.TextColor),
+			TableStyle:        t.meta.Theme.tableStyle(),
+			PageSize:          t.data.Page.pageSize(t.norm.Zones()),
+			Orientation:       t.data.Page.orientation(),
 		},
 		false,
 		t.logger,
 		func() {
-
+			t.printHeader()
 		},
 		func(isLastPage bool) {
 
@@ -93,11 +120,21 @@ func (t *TableAttachment) GeneratePDF() (*gofpdf.Fpdf, error) {
 	}
 
 	t.pdfGen = pdfGen
+	t.data.Images.register(t.pdfGen, watermarkRefs(t.data.Watermarks)...)
 	t.pdfGen.NewPage()
 
+	// the page numbers are printed at the bottom of the real page, e.g. of a landscape page
+	_, pageHeight := t.pdfGen.GetPageSize()
+	t.footerStartY = pageHeight - 5
+
 	t.doGenerate()
 
-	return t.pdfGen.GetPdf(), t.pdfGen.GetError()
+	return t.pdfGen, t.pdfGen.GetError()
+}
+
+// SetGeneratorBackend replaces the default PDF backend, e.g. with generator.NewRecordingGenerator.
+func (t *TableAttachment) SetGeneratorBackend(newGenerator generator.NewGeneratorFunc) {
+	t.newGenerator = newGenerator
 }
 
 func (t *TableAttachment) LogError(err error) {
@@ -114,37 +151,81 @@ func (t *TableAttachment) LogError(err error) {
 
 func (t *TableAttachment) validateData() (err error) {
 	//TODO implement me
-	return err
+	_, err = getLetterNorm(t.data.LetterNorm)
+	if err != nil {
+		return err
+	}
+
+	fonts, err := t.data.Fonts.registry()
+	if err != nil {
+		return err
+	}
+
+	theme, err := loadTheme(t.data.Theme, fonts)
+	if err != nil {
+		return err
+	}
+	t.meta = newPdfMeta(theme, fonts)
+
+	if err = validateWatermarks(t.data.Watermarks, t.data.Images); err != nil {
+		return err
+	}
+
+	for i, chart := range t.data.Charts {
+		if err = chart.validate(t.data.TableHeader, t.data.TableData); err != nil {
+			return errors.New(fmt.Sprintf("invalid chart %d: %s", i+1, err.Error()))
+		}
+	}
+
+	return t.data.Page.validate()
 }
 
 func (t *TableAttachment) doGenerate() {
 
-	din5008a.Body(t.pdfGen, func() {
+	t.norm.Body(t.pdfGen, func() {
 		t.printHeadline()
 		t.printTimeInfo()
+		t.printCharts(chartBeforeTable)
 		t.printTable()
+		t.printCharts(chartAfterTable)
 	})
 
-	din5008a.PageNumberingCustom(t.data.PageNumberPrefix, t.pdfGen, t.footerStartY, false)
+	printInColor(t.pdfGen, t.meta.Theme.FooterColor, func() {
+		t.norm.PageNumberingCustom(t.data.PageNumberPrefix, t.pdfGen, t.footerStartY, false)
+	})
+
+	printWatermarks(t.pdfGen, t.norm, t.data.Watermarks)
 }
 
 func (t *TableAttachment) printHeadline() {
-	t.pdfGen.SetFontSize(din5008a.FontSize10 + 5)
+	t.pdfGen.SetFontSize(t.meta.Font.SizeLarge)
 	x, y := t.pdfGen.GetCursor()
 
-	//todo is this DIN conform or how to design the second page???
-	y = din5008a.HeaderStopY + 5
+	// an attachment has no address part, the headline starts below the header of the first page,
+	// the following pages start with the compact header, see printHeader
+	y = t.norm.Zones().Header.StopY + 5
 	t.pdfGen.SetCursor(x, y)
-	t.pdfGen.PrintLnPdfText(t.data.Headline, "b", "L")
-	t.pdfGen.SetFontSize(din5008a.FontSize10)
-	t.pdfGen.NewLine(din5008a.BodyStartX)
+	printInColor(t.pdfGen, t.meta.Theme.BrandColor, func() {
+		t.pdfGen.PrintLnPdfText(t.data.Headline, "b", "L")
+	})
+	t.pdfGen.SetFontSize(t.meta.Font.SizeDefault)
+	t.pdfGen.NewLine(t.norm.Zones().BodyStartX)
+}
+
+// printHeader prints the headline as compact header on each follow-up page.
+func (t *TableAttachment) printHeader() {
+	if t.pdfGen.GetCurrentPageNumber() > 1 {
+		printInColor(t.pdfGen, t.meta.Theme.HeaderColor, func() {
+			t.norm.FollowUpHeader(t.pdfGen, letter.FollowUpHeader{Title: t.data.Headline})
+		})
+	}
 }
 
 func (t *TableAttachment) printTimeInfo() {
-	t.pdfGen.NewLine(din5008a.BodyStartX)
-	t.pdfGen.SetFontSize(din5008a.FontSizeSender8)
+	t.pdfGen.NewLine(t.norm.Zones().BodyStartX)
+	t.pdfGen.SetFontSize(t.meta.Font.SizeSmall)
 	t.pdfGen.PrintLnPdfText(t.data.TableInfo, "i", "L")
-	t.pdfGen.SetFontSize(din5008a.FontSize10)
+	t.pdfGen.SetFontSize(t.meta.Font.SizeDefault)
 }
 
 func (t *TableAttachment) printTable() {
@@ -166,10 +247,69 @@ func (t *TableAttachment) printTable() {
 	var columnWidth = getColumnWithFromPercentage(t.pdfGen, t.data.ColumnPercentages)
 	var cellAlign []string
 
-	for i := 0; i <= len(t.data.TableData); i++ {
+	for range columnWidth {
 		cellAlign = append(cellAlign, "LM")
 	}
 
 	t.pdfGen.PrintTableHeader(t.data.TableHeader, columnWidth, cellAlign)
 	t.pdfGen.PrintTableBody(t.data.TableData, columnWidth, cellAlign)
 }
+
+// printCharts prints the charts at the position before or after the table, each with a blank line on top.
+// A chart, that does not fit on the current page, starts on the next page.
+func (t *TableAttachment) printCharts(position string) {
+	lineHeight := t.pdfGen.GetLineHeight(t.meta.Font.SizeDefault, t.meta.Font.LineGap)
+
+	var boxes []generator.Box
+	for _, chart := range t.data.Charts {
+		if chart.position() != position {
+			continue
+		}
+
+		box, err := chart.box(t.data.TableHeader, t.data.TableData)
+		if err != nil {
+			t.pdfGen.SetError(err)
+			return
+		}
+		box.Colors = append([]generator.Color{generator.Color(t.meta.Theme.BrandColor)}, generator.DefaultChartColors...)
+		box.GridColor = generator.Color(t.meta.Theme.LineColor)
+		box.FontSize = t.meta.Font.SizeSmall
+
+		boxes = append(boxes, generator.SpacerBox{Height: lineHeight}, box)
+	}
+	if len(boxes) == 0 {
+		return
+	}
+	if position == chartBeforeTable {
+		boxes = append(boxes, generator.SpacerBox{Height: lineHeight})
+	}
+
+	// the page numbers are printed in the line above footerStartY - MarginPageNumberY, see PageNumberingCustom
+	zones := t.norm.Zones().FitPage(t.pdfGen.GetPageSize())
+	t.pdfGen.RenderBoxes(generator.Frame{
+		StartX:         zones.BodyStartX,
+		Width:          zones.BodyStopX - zones.BodyStartX,
+		NextPageStartY: zones.NextPageStartY,
+		StopY:          t.footerStartY - zones.MarginPageNumberY - 2*lineHeight,
+	}, boxes)
+}
+
+// RenderHTML writes the table attachment as HTML e-mail body to w.
+func (t *TableAttachment) RenderHTML(w io.Writer) error {
+	return renderMailHTML(w, t.mailView())
+}
+
+// RenderText writes the table attachment as plain text e-mail body to w.
+func (t *TableAttachment) RenderText(w io.Writer) error {
+	return renderMailText(w, t.mailView())
+}
+
+// mailView shows the same content as the PDF. A table attachment has no address and footer part.
+func (t *TableAttachment) mailView() mailView {
+	view := mailView{Headline: t.data.Headline}
+
+	view.addSmallText(t.data.TableInfo)
+	view.addTable(mailTable{Header: t.data.TableHeader, Rows: t.data.TableData})
+
+	return view
+}
@@ -2,33 +2,18 @@ package pdfType
 
 import (
 	"SimpleInvoice/generator"
+	"SimpleInvoice/norms/letter"
 	"fmt"
-	errorsWithStack "github.com/go-errors/errors"
-	"net/url"
+	"github.com/rs/zerolog"
 )
 
-func mimeImg(pdfGen *generator.PDFGenerator, strUrl string, posX float64, posY float64, scale float64) {
-	urlStruct, err := url.Parse(strUrl)
-	if err != nil {
-		pdfGen.SetError(errorsWithStack.New(err.Error()))
-		return
-	}
-
-	pdfGen.SetUnsafeCursor(posX, posY)
-
-	if !pdfGen.ImageIsRegistered(urlStruct.String()) {
-		pdfGen.RegisterMimeImageToPdf(urlStruct)
-	}
-	pdfGen.PlaceRegisteredImageOnPage(urlStruct.String(), "R", scale)
-}
-
-func letterAddressSenderSmall(pdfGen *generator.PDFGenerator, address string, posX float64, posY float64, size float64) {
+func letterAddressSenderSmall(pdfGen generator.Generator, address string, posX float64, posY float64, size float64) {
 	pdfGen.SetCursor(posX, posY)
 	pdfGen.SetFontSize(size)
 	pdfGen.PrintPdfText(address, "", "L")
 }
 
-func letterReceiverAddress(pdfGen *generator.PDFGenerator, receiverAddress FullPersonInfo, posX float64, posY float64) {
+func letterReceiverAddress(pdfGen generator.Generator, receiverAddress FullPersonInfo, posX float64, posY float64) {
 	pdfGen.SetCursor(posX, posY)
 	if receiverAddress.CompanyName != "" {
 		pdfGen.PrintLnPdfText(receiverAddress.CompanyName, "", "L")
@@ -47,9 +32,93 @@ func letterReceiverAddress(pdfGen *generator.PDFGenerator, receiverAddress FullP
 		"", "L")
 }
 
-func getColumnWithFromPercentage(pdfGen *generator.PDFGenerator, columnPercent []float64) (columnWidth []float64) {
+func getColumnWithFromPercentage(pdfGen generator.Generator, columnPercent []float64) (columnWidth []float64) {
 	for _, p := range columnPercent {
 		columnWidth = append(columnWidth, getCellWith(pdfGen, p))
 	}
 	return columnWidth
 }
+
+// signatureSectionBox returns a signature field (name, date and signature) for each label side by side.
+// The section is always printed together on one page.
+func signatureSectionBox(pdfGen generator.Generator, labels []string, font pdfFont) generator.GroupBox {
+	return generator.GroupBox{
+		BoxConstraints: generator.BoxConstraints{KeepTogether: true},
+		Boxes: []generator.Box{
+			generator.SpacerBox{Height: 3 * pdfGen.GetLineHeight(font.SizeDefault, font.LineGap)},
+			generator.CanvasBox{
+				Height: signaturePartHeight(pdfGen, font),
+				Draw: func(x float64, y float64, width float64) {
+					partWidth := width / float64(len(labels))
+					for i, label := range labels {
+						printSignaturePart(pdfGen, label, x+float64(i)*partWidth, y, partWidth, font)
+					}
+				},
+			},
+		},
+	}
+}
+
+// signaturePartHeight returns the height of one part printed by printSignaturePart.
+// The small lines are spaced by the line gap of font, i.e. of the theme.
+func signaturePartHeight(pdfGen generator.Generator, font pdfFont) float64 {
+	smallLineHeight := pdfGen.GetLineHeight(font.SizeSmall, font.LineGap)
+	return 1 + 4*smallLineHeight + 1 + smallLineHeight - font.LineGap
+}
+
+func printSignaturePart(pdfGen generator.Generator, headText string, startX float64, startY float64, contentWidth float64, font pdfFont) {
+	const marginLeft = 22.5
+	var nameLength = contentWidth - marginLeft
+	var dateLength = nameLength / 3
+	const gabLength = 5
+	var signatureLength = (nameLength/3)*2 - gabLength
+
+	var cY float64
+
+	// name
+	pdfGen.SetCursor(startX, startY)
+	pdfGen.DrawLine(startX, startY, startX+nameLength, startY, pdfGen.GetLineColor(), pdfGen.GetLineWidth())
+	_, cY = pdfGen.GetCursor()
+	pdfGen.SetCursor(startX, cY+1)
+	pdfGen.SetFontSize(font.SizeSmall)
+	pdfGen.PrintPdfText(headText, "b", "L")
+	pdfGen.PrintLnPdfText("(Name)", "", "L")
+
+	pdfGen.SetFontSize(font.SizeDefault)
+	pdfGen.NewLine(startX)
+	pdfGen.NewLine(startX)
+	pdfGen.NewLine(startX)
+
+	//date & signature
+	_, cY = pdfGen.GetCursor()
+	var dateEndX = startX + dateLength
+	var signatureStartX = startX + dateLength + gabLength
+	var signatureEndX = startX + dateLength + gabLength + signatureLength
+	pdfGen.DrawLine(startX, cY, dateEndX, cY, pdfGen.GetLineColor(), pdfGen.GetLineWidth())
+	pdfGen.DrawLine(signatureStartX, cY, signatureEndX, cY, pdfGen.GetLineColor(), pdfGen.GetLineWidth())
+	_, cY = pdfGen.GetCursor()
+	pdfGen.SetCursor(startX, cY+1)
+	pdfGen.SetFontSize(font.SizeSmall)
+	pdfGen.PrintPdfText("Datum", "", "L")
+	_, cY = pdfGen.GetCursor()
+	pdfGen.SetCursor(signatureStartX, cY)
+	pdfGen.PrintPdfText("Unterschrift", "", "L")
+	pdfGen.SetFontSize(font.SizeDefault)
+}
+
+// checkCompliance measures the zones of the letter norm with content and logs each issue of the report.
+func checkCompliance(pdfGen generator.Generator, norm letter.Norm, content letter.CheckContent, logger *zerolog.Logger) letter.Report {
+	report := letter.Check(pdfGen, norm, content)
+	for _, issue := range report.Issues {
+		logger.Warn().Msgf("%s %s: %s", issue.Level, issue.Zone, issue.Message)
+	}
+	return report
+}
+
+// printDebugOverlay draws the zones of the letter norm and the envelope windows on the first page,
+// to check the placement of the address.
+func printDebugOverlay(pdfGen generator.Generator, norm letter.Norm, logger *zerolog.Logger) {
+	pdfGen.GoToPage(1)
+	letter.ShowDebugFrame(pdfGen, norm.Zones(), logger)
+	letter.ShowEnvelopeWindows(pdfGen, norm.Zones())
+}
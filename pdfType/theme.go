@@ -0,0 +1,202 @@
+package pdfType
+
+import (
+	"SimpleInvoice/generator"
+	din5008a "SimpleInvoice/norms/letter/din-5008-a"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ThemeDir is the directory of the named theme files, e.g. "themes/acme.json" for the theme "acme".
+var ThemeDir = "themes"
+
+// themeNamePattern prevents theme names, which point outside ThemeDir.
+var themeNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// theme defines the look of a pdf type: the font, the colors and the table style.
+//
+// The theme of a request is the default theme, overwritten by the named theme file (see ThemeDir)
+// and then by the fields of the request, e.g. {"name": "acme", "brandColor": "#c00000"}.
+// The fonts of the address and info zones are defined by the letter norm and not changed by a theme.
+//
+// FontName and FallbackFonts are font families of the font registry (see generator.FontRegistry).
+// The standard PDF fonts (e.g. "Helvetica") are not allowed, because they have no utf-8 symbols like € and no light and medium style.
+//
+// BrandColor is the color of the headlines, HeaderColor of the compact header of the follow-up pages
+// and FooterColor of the footer and the page numbers.
+type theme struct {
+	Name            string     `json:"name"`
+	FontName        string     `json:"fontName"`
+	FallbackFonts   []string   `json:"fallbackFonts"`
+	FontSizeDefault float64    `json:"fontSizeDefault"`
+	FontSizeSmall   float64    `json:"fontSizeSmall"`
+	FontSizeLarge   float64    `json:"fontSizeLarge"`
+	LineSpacing     float64    `json:"lineSpacing"`
+	LineGap         float64    `json:"lineGap"`
+	TextColor       themeColor `json:"textColor"`
+	BrandColor      themeColor `json:"brandColor"`
+	HeaderColor     themeColor `json:"headerColor"`
+	FooterColor     themeColor `json:"footerColor"`
+	LineColor       themeColor `json:"lineColor"`
+	Table           themeTable `json:"table"`
+}
+
+// themeTable is the table style of a theme, see generator.TableStyle.
+type themeTable struct {
+	HeaderFillColor themeColor `json:"headerFillColor"`
+	FooterFillColor themeColor `json:"footerFillColor"`
+	StripeFillColor themeColor `json:"stripeFillColor"`
+	Striped         bool       `json:"striped"`
+	Border          string     `json:"border"`
+	BorderColor     themeColor `json:"borderColor"`
+}
+
+// themeColor is a color written as hex string in JSON, e.g. "#1a4d8f" or "#333".
+type themeColor generator.Color
+
+func (c *themeColor) UnmarshalJSON(data []byte) error {
+	var hexStr string
+	if err := json.Unmarshal(data, &hexStr); err != nil {
+		return err
+	}
+
+	hexStr = strings.TrimPrefix(hexStr, "#")
+	if len(hexStr) == 3 {
+		hexStr = string([]byte{hexStr[0], hexStr[0], hexStr[1], hexStr[1], hexStr[2], hexStr[2]})
+	}
+
+	rgb, err := strconv.ParseUint(hexStr, 16, 32)
+	if err != nil || len(hexStr) != 6 {
+		return errors.New(fmt.Sprintf("the color %s must be a hex color like \"#1a4d8f\"", string(data)))
+	}
+
+	*c = themeColor{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb)}
+	return nil
+}
+
+// defaultTheme is the look of the pdf types without a theme: OpenSans, black text and light gray tables.
+func defaultTheme() theme {
+	return theme{
+		Name:            "default",
+		FontName:        "OpenSans",
+		FontSizeDefault: din5008a.FontSize10,
+		FontSizeSmall:   din5008a.FontSizeSender8,
+		FontSizeLarge:   din5008a.FontSize10 + 5,
+		LineSpacing:     din5008a.LineSpacing,
+		LineGap:         din5008a.FontGab10,
+		LineColor:       themeColor{R: 162, G: 162, B: 162},
+		Table: themeTable{
+			HeaderFillColor: themeColor(generator.DefaultTableStyle.HeaderFillColor),
+			FooterFillColor: themeColor(generator.DefaultTableStyle.FooterFillColor),
+			StripeFillColor: themeColor(generator.DefaultTableStyle.StripeFillColor),
+			Border:          generator.DefaultTableStyle.Border,
+			BorderColor:     themeColor{R: 162, G: 162, B: 162},
+		},
+	}
+}
+
+// loadTheme returns the theme of the request field "theme".
+// The field is the name of a theme file (e.g. "acme") or a theme object, see theme.
+// The fonts of the theme must be registered in fonts.
+func loadTheme(themeJson json.RawMessage, fonts *generator.FontRegistry) (t theme, err error) {
+	t = defaultTheme()
+	if len(themeJson) == 0 || string(themeJson) == "null" {
+		return t, nil
+	}
+
+	var name string
+	if err = json.Unmarshal(themeJson, &name); err != nil {
+		var named struct {
+			Name string `json:"name"`
+		}
+		if err = json.Unmarshal(themeJson, &named); err != nil {
+			return t, err
+		}
+		name = named.Name
+	} else {
+		// only the name is given
+		themeJson = nil
+	}
+
+	if name != "" {
+		if !themeNamePattern.MatchString(name) {
+			return t, errors.New(fmt.Sprintf("the theme name \"%s\" may only contain letters, digits, - and _", name))
+		}
+
+		themeFile, err := os.ReadFile(filepath.Join(ThemeDir, name+".json"))
+		if err != nil {
+			return t, errors.New(fmt.Sprintf("the theme \"%s\" does not exist", name))
+		}
+
+		if err = json.Unmarshal(themeFile, &t); err != nil {
+			return t, errors.New(fmt.Sprintf("the theme file \"%s\" is invalid: %s", name, err.Error()))
+		}
+	}
+
+	if themeJson != nil {
+		if err = json.Unmarshal(themeJson, &t); err != nil {
+			return t, err
+		}
+	}
+
+	return t, t.validate(fonts)
+}
+
+func (t theme) validate(fonts *generator.FontRegistry) error {
+	for _, fontName := range append([]string{t.FontName}, t.FallbackFonts...) {
+		if !fonts.IsRegistered(fontName) {
+			return errors.New(fmt.Sprintf("the font \"%s\" of the theme is not registered, see GET /fonts", fontName))
+		}
+	}
+
+	if t.FontSizeDefault <= 0 || t.FontSizeSmall <= 0 || t.FontSizeLarge <= 0 {
+		return errors.New("each font size of the theme must be grater then 0")
+	}
+
+	if t.LineSpacing < 0 || t.LineGap < 0 {
+		return errors.New("the lineSpacing and lineGap of the theme must not be negative")
+	}
+
+	switch t.Table.Border {
+	case "horizontal", "grid", "none":
+	default:
+		return errors.New(fmt.Sprintf("the table border \"%s\" of the theme must be horizontal, grid or none", t.Table.Border))
+	}
+
+	return nil
+}
+
+func (t theme) font() pdfFont {
+	return pdfFont{
+		FontName:    t.FontName,
+		SizeDefault: t.FontSizeDefault,
+		SizeSmall:   t.FontSizeSmall,
+		SizeLarge:   t.FontSizeLarge,
+		LineGap:     t.LineGap,
+	}
+}
+
+func (t theme) tableStyle() generator.TableStyle {
+	return generator.TableStyle{
+		HeaderFillColor: generator.Color(t.Table.HeaderFillColor),
+		FooterFillColor: generator.Color(t.Table.FooterFillColor),
+		StripeFillColor: generator.Color(t.Table.StripeFillColor),
+		Striped:         t.Table.Striped,
+		Border:          t.Table.Border,
+		BorderColor:     generator.Color(t.Table.BorderColor),
+	}
+}
+
+// printInColor calls print with the text color and restores the previous text color afterwards.
+func printInColor(pdfGen generator.Generator, color themeColor, print func()) {
+	textColor := pdfGen.GetTextColor()
+	pdfGen.SetTextColor(generator.Color(color))
+	print()
+	pdfGen.SetTextColor(textColor)
+}
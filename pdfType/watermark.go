@@ -0,0 +1,249 @@
+package pdfType
+
+import (
+	"SimpleInvoice/generator"
+	"SimpleInvoice/norms/letter"
+	"encoding/json"
+	"errors"
+	"fmt"
+	errorsWithStack "github.com/go-errors/errors"
+	"net/url"
+	"time"
+)
+
+// presets of a watermark, see watermarkPresets
+const (
+	watermarkDraft = "draft"
+	watermarkCopy  = "copy"
+	watermarkPaid  = "paid"
+)
+
+// styles of a watermark
+const (
+	watermarkStyleWatermark = "watermark"
+	watermarkStyleStamp     = "stamp"
+)
+
+// pages of a watermark
+const (
+	watermarkAllPages  = "all"
+	watermarkFirstPage = "first"
+)
+
+// stampWidth is the default width of a stamp in mm. A watermark is 80 % of the page width wide by default.
+const stampWidth = 60
+
+// watermarkPresets are the German and English texts of the presets.
+var watermarkPresets = map[string]map[string]string{
+	watermarkDraft: {"de": "ENTWURF", "en": "DRAFT"},
+	watermarkCopy:  {"de": "KOPIE", "en": "COPY"},
+	watermarkPaid:  {"de": "BEZAHLT", "en": "PAID"},
+}
+
+// watermarkDateFormats are the formats of the date of a paid stamp per language.
+var watermarkDateFormats = map[string]string{"de": "02.01.2006", "en": "2006-01-02"}
+
+// watermark is a rotated, semi-transparent text or image on top of the pages (see generator.Watermark),
+// e.g. "KOPIE" on a re-issued invoice or a "BEZAHLT" stamp with the payment date.
+//
+// Preset "draft", "copy" or "paid" sets the text in the Language "de" (default) or "en" and the look,
+// all further fields overwrite the preset:
+//
+//	"draft" and "copy" are a large gray text across the page with the Style "watermark",
+//	"paid" is a red stamp with a frame and the Date of today (Style "stamp").
+//
+// MimeImageUrl references an image drawn instead of the text (see requestImages).
+// Pages is "all" (default) or "first". Color, Opacity (0 to 1) and Angle (counterclockwise in degree) define the look.
+// Width, X and Y of the center are measured in mm. If they are 0, a watermark is centered on the page
+// and a stamp is placed at the top right of the body.
+type watermark struct {
+	Preset       string     `json:"preset"`
+	Language     string     `json:"language"`
+	Text         string     `json:"text"`
+	Date         string     `json:"date"`
+	MimeImageUrl string     `json:"mimeImageUrl"`
+	Style        string     `json:"style"`
+	Pages        string     `json:"pages"`
+	Color        themeColor `json:"color"`
+	Opacity      float64    `json:"opacity"`
+	Angle        float64    `json:"angle"`
+	Width        float64    `json:"width"`
+	X            float64    `json:"x"`
+	Y            float64    `json:"y"`
+}
+
+// UnmarshalJSON sets the defaults of the preset, before the fields of the request overwrite them.
+func (w *watermark) UnmarshalJSON(data []byte) error {
+	var preset struct {
+		Preset   string `json:"preset"`
+		Language string `json:"language"`
+	}
+	if err := json.Unmarshal(data, &preset); err != nil {
+		return err
+	}
+	*w = watermarkPreset(preset.Preset, preset.Language)
+
+	// the plain type has no UnmarshalJSON method
+	type plainWatermark watermark
+	return json.Unmarshal(data, (*plainWatermark)(w))
+}
+
+// watermarkPreset returns the defaults of the preset. Unknown presets and languages are rejected by validate.
+func watermarkPreset(preset string, language string) watermark {
+	if language == "" {
+		language = "de"
+	}
+
+	w := watermark{
+		Preset:   preset,
+		Language: language,
+		Text:     watermarkPresets[preset][language],
+		Style:    watermarkStyleWatermark,
+		Pages:    watermarkAllPages,
+		Color:    themeColor{R: 128, G: 128, B: 128},
+		Opacity:  .15,
+		Angle:    45,
+	}
+
+	if preset == watermarkPaid {
+		w.Date = time.Now().Format(watermarkDateFormats[language])
+		w.Style = watermarkStyleStamp
+		w.Color = themeColor{R: 200, G: 30, B: 30}
+		w.Opacity = .8
+		w.Angle = 12
+	}
+
+	return w
+}
+
+func (w watermark) validate(images requestImages) error {
+	if _, ok := watermarkPresets[w.Preset]; !ok && w.Preset != "" {
+		return errors.New(fmt.Sprintf("the preset \"%s\" must be draft, copy or paid", w.Preset))
+	}
+
+	if _, ok := watermarkDateFormats[w.Language]; !ok {
+		return errors.New(fmt.Sprintf("the language \"%s\" must be de or en", w.Language))
+	}
+
+	switch w.Style {
+	case watermarkStyleWatermark, watermarkStyleStamp:
+	default:
+		return errors.New(fmt.Sprintf("the style \"%s\" must be watermark or stamp", w.Style))
+	}
+
+	switch w.Pages {
+	case watermarkAllPages, watermarkFirstPage:
+	default:
+		return errors.New(fmt.Sprintf("the pages \"%s\" must be all or first", w.Pages))
+	}
+
+	if w.Opacity <= 0 || w.Opacity > 1 {
+		return errors.New(fmt.Sprintf("the opacity (%.2f) must be greater than 0 and at most 1", w.Opacity))
+	}
+
+	if w.Width < 0 || w.X < 0 || w.Y < 0 {
+		return errors.New("width, x and y must not be negative")
+	}
+
+	if w.Text == "" && w.MimeImageUrl == "" {
+		return errors.New("a watermark requires a text, a preset or a mimeImageUrl")
+	}
+
+	return images.validate(w.MimeImageUrl)
+}
+
+// validateWatermarks checks all watermarks of a request.
+func validateWatermarks(marks []watermark, images requestImages) error {
+	for i, mark := range marks {
+		if err := mark.validate(images); err != nil {
+			return errors.New(fmt.Sprintf("invalid watermark %d: %s", i+1, err.Error()))
+		}
+	}
+	return nil
+}
+
+// watermarkRefs returns the image references of the watermarks, see requestImages.
+func watermarkRefs(marks []watermark) (refs []string) {
+	for _, mark := range marks {
+		if mark.MimeImageUrl != "" {
+			refs = append(refs, mark.MimeImageUrl)
+		}
+	}
+	return refs
+}
+
+// printWatermarks draws the watermarks on top of the printed pages, call it after the page numbers are printed.
+// The uploaded images must be registered before, see requestImages.register.
+func printWatermarks(pdfGen generator.Generator, norm letter.Norm, marks []watermark) {
+	if len(marks) == 0 {
+		return
+	}
+
+	zones := norm.Zones().FitPage(pdfGen.GetPageSize())
+	pages := pdfGen.GetTotalNumber()
+
+	for _, mark := range marks {
+		generatorMark := mark.generatorWatermark(pdfGen, zones)
+
+		lastPage := pages
+		if mark.Pages == watermarkFirstPage {
+			lastPage = 1
+		}
+		for page := 1; page <= lastPage; page++ {
+			pdfGen.GoToPage(page)
+			pdfGen.DrawWatermark(generatorMark)
+		}
+	}
+
+	pdfGen.GoToPage(pages)
+}
+
+// generatorWatermark returns the watermark with the default size and position of its style on a page with the zones.
+func (w watermark) generatorWatermark(pdfGen generator.Generator, zones letter.Zones) generator.Watermark {
+	mark := generator.Watermark{
+		X:       w.X,
+		Y:       w.Y,
+		Width:   w.Width,
+		Angle:   w.Angle,
+		Opacity: w.Opacity,
+		Color:   generator.Color(w.Color),
+		Border:  w.Style == watermarkStyleStamp,
+	}
+
+	if w.MimeImageUrl != "" {
+		mark.ImageName = w.MimeImageUrl
+		if isDownloadRef(w.MimeImageUrl) && !pdfGen.ImageIsRegistered(w.MimeImageUrl) {
+			urlStruct, err := url.Parse(w.MimeImageUrl)
+			if err != nil {
+				pdfGen.SetError(errorsWithStack.New(err.Error()))
+				return mark
+			}
+			mark.ImageName = pdfGen.RegisterMimeImageToPdf(urlStruct)
+		}
+	} else {
+		mark.Lines = []string{w.Text}
+		if w.Date != "" {
+			mark.Lines = append(mark.Lines, w.Date)
+		}
+	}
+
+	switch {
+	case mark.Width > 0:
+	case w.Style == watermarkStyleStamp:
+		mark.Width = stampWidth
+	default:
+		mark.Width = .8 * zones.PageWidth
+	}
+
+	if mark.X == 0 && mark.Y == 0 {
+		if w.Style == watermarkStyleStamp {
+			mark.X = zones.BodyStopX - mark.Width/2
+			mark.Y = zones.BodyStartY + mark.Width/4
+		} else {
+			mark.X = zones.PageWidth / 2
+			mark.Y = zones.PageHeight / 2
+		}
+	}
+
+	return mark
+}